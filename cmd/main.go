@@ -1,17 +1,165 @@
 package main
 
 import (
-	"log"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"os"
 
 	"karalis/internal/app"
+	"karalis/pkg/cli"
+	"karalis/pkg/config"
+	"karalis/pkg/input"
+	"karalis/pkg/logging"
+	"karalis/pkg/net/client"
+	"karalis/pkg/net/server"
+	"karalis/pkg/profile"
+	"karalis/pkg/rng"
+	"karalis/pkg/scene"
+	"karalis/pkg/settings"
+)
+
+var (
+	logger = logging.For(logging.App)
 )
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run the subcommand in args and get the exit code
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	c, err := cli.Parse(args, stderr)
+	if errors.Is(err, cli.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "ERR: %+v\n", err)
+		return 2
+	}
+
+	switch c.Name {
+	case cli.Play:
+		err = play(c)
+	case cli.Server:
+		server.Server(c.Listen)
+	case cli.Client:
+		client.Client(c.Connect, c.Local)
+	case cli.Schema:
+		err = schema(c.Schema, stdout)
+	case cli.Gen:
+		err = gen(c)
+	case cli.Replay:
+		err = replay(c, stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "ERR: %+v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// start the game
+func play(c *cli.Command) error {
+	//a missing config dir falls back to the default one
+	err := config.InitConfigDir(c.ConfigDir)
+	if err != nil && config.ConfigDir == "" {
+		return err
+	} else if err != nil {
+		logger.Warn("config dir", "err", err)
+	}
+
 	a := app.NewApp()
-	if a != nil {
-		err := a.Start(false)
+	if a == nil {
+		return fmt.Errorf("Failed to create app")
+	}
+	a.SetWorld(c.World, c.Seed)
+	a.SetResDir(c.ResDir)
+	a.SetRecord(c.Record)
+	if c.Replay != "" {
+		r, err := input.LoadReplay(c.Replay)
+		if err != nil {
+			return err
+		}
+		err = a.SetReplay(r, nil)
 		if err != nil {
-			log.Printf("ERR: %+v\n", err)
+			return err
 		}
 	}
+	if c.Profile == "" {
+		return a.Start(c.Debug)
+	}
+
+	profile.StartCapture()
+	err = a.Start(c.Debug)
+	dropped, perr := profile.SaveCapture(c.Profile)
+	if perr != nil {
+		return perr
+	}
+	if dropped > 0 {
+		logger.Warn("trace events did not fit", "dropped", dropped, "file", c.Profile)
+	}
+	return err
+}
+
+// play back a replay headless and print the final player position
+func replay(c *cli.Command, out io.Writer) error {
+	r, err := input.LoadReplay(c.Replay)
+	if err != nil {
+		return err
+	}
+	err = config.InitConfigDir(c.ConfigDir)
+	if err != nil && config.ConfigDir == "" {
+		return err
+	} else if err != nil {
+		logger.Warn("config dir", "err", err)
+	}
+
+	a := app.NewApp()
+	if a == nil {
+		return fmt.Errorf("Failed to create app")
+	}
+	a.SetHeadless(true)
+	err = a.SetReplay(r, out)
+	if err != nil {
+		return err
+	}
+	return a.Start(false)
+}
+
+// print the json schemas of config and scene files
+func schema(name string, out io.Writer) error {
+	schemas := map[string]interface{}{
+		"scene":    scene.Schema(),
+		"bindings": input.Schema(),
+		"settings": settings.Schema(),
+	}
+
+	var doc interface{} = schemas
+	if name != "all" {
+		doc = schemas[name]
+	}
+	txt, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(txt, '\n'))
+	return err
+}
+
+// write a world preview image
+func gen(c *cli.Command) error {
+	img, err := rng.Preview(c.World, c.Seed, c.Size)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(c.Out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return png.Encode(file, img)
 }
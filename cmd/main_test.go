@@ -1,26 +1,121 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"testing"
-	"time"
 )
 
+// run the app on the null backend so tests need no display
+func TestMain(m *testing.M) {
+	os.Setenv("KARALIS_HEADLESS", "1")
+	os.Exit(m.Run())
+}
+
 func UnexpectedVal(t *testing.T, expect int, got int) {
 	t.Error(fmt.Sprintf("Got unexpected value from main: expected %v, got %v", expect, got))
 }
 
 func TestSchemaMainFunc(t *testing.T) {
-	ch := make(chan int)
-	go func() {
-		os.Args = []string{"test", "--schema", "all"}
-		main()
-	}()
-	time.Sleep(1 * time.Second)
-	close(ch)
-	output := <-ch
-	if output != 0 {
-		UnexpectedVal(t, 0, output)
+	tests := []struct {
+		args []string
+		code int
+		keys []string
+	}{
+		{[]string{"schema", "all"}, 0, []string{"scene", "bindings", "settings"}},
+		{[]string{"schema"}, 0, []string{"scene", "bindings"}},
+		{[]string{"schema", "scene"}, 0, []string{"$defs", "properties"}},
+		{[]string{"schema", "bindings"}, 0, []string{"properties"}},
+		{[]string{"schema", "settings"}, 0, []string{"properties"}},
+		{[]string{"schema", "saves"}, 2, nil},
+		{[]string{"--schema", "all"}, 2, nil},
+	}
+	for _, test := range tests {
+		out := bytes.Buffer{}
+		code := run(test.args, &out, &bytes.Buffer{})
+		if code != test.code {
+			UnexpectedVal(t, test.code, code)
+			continue
+		}
+		if test.code != 0 {
+			continue
+		}
+
+		doc := map[string]interface{}{}
+		err := json.Unmarshal(out.Bytes(), &doc)
+		if err != nil {
+			t.Error(fmt.Sprintf("Schema for %v is not json: %+v", test.args, err))
+		}
+		for _, key := range test.keys {
+			if _, ok := doc[key]; !ok {
+				t.Error(fmt.Sprintf("Schema for %v is missing %s", test.args, key))
+			}
+		}
+	}
+}
+
+func TestGenMainFunc(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		world string
+		code  int
+	}{
+		{"terrain", 0},
+		{"city", 0},
+		{"dungeon", 0},
+		{"moon", 2},
+	}
+	for _, test := range tests {
+		out := filepath.Join(dir, test.world+".png")
+		code := run([]string{"gen", "--world", test.world, "--seed", "7", "--size", "32", "--out", out}, &bytes.Buffer{}, &bytes.Buffer{})
+		if code != test.code {
+			UnexpectedVal(t, test.code, code)
+			continue
+		}
+		if _, err := os.Stat(out); (err == nil) != (test.code == 0) {
+			t.Error(fmt.Sprintf("Unexpected preview file for %s: %+v", test.world, err))
+		}
+	}
+}
+
+func TestReplayMainFunc(t *testing.T) {
+	tests := []struct {
+		args   []string
+		code   int
+		expect [3]float32
+	}{
+		{[]string{"replay", "--config-dir", t.TempDir(), "testdata/fly.json"}, 0, [3]float32{-0.0205, 20.4647, -2.5006}},
+		//playing back twice ends in the same place
+		{[]string{"replay", "--config-dir", t.TempDir(), "testdata/fly.json"}, 0, [3]float32{-0.0205, 20.4647, -2.5006}},
+		{[]string{"replay", "--config-dir", t.TempDir(), "testdata/missing.json"}, 1, [3]float32{}},
+		{[]string{"replay"}, 2, [3]float32{}},
+	}
+	for _, test := range tests {
+		out := bytes.Buffer{}
+		code := run(test.args, &out, &bytes.Buffer{})
+		if code != test.code {
+			UnexpectedVal(t, test.code, code)
+			continue
+		}
+		if test.code != 0 {
+			continue
+		}
+
+		got := [3]float32{}
+		_, err := fmt.Sscanf(out.String(), "%f %f %f", &got[0], &got[1], &got[2])
+		if err != nil {
+			t.Error(fmt.Sprintf("Unexpected replay output %q: %+v", out.String(), err))
+			continue
+		}
+		for i := range got {
+			if math.Abs(float64(got[i]-test.expect[i])) > 0.001 {
+				t.Error(fmt.Sprintf("Unexpected final position: expected %v, got %v", test.expect, got))
+				break
+			}
+		}
 	}
 }
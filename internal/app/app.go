@@ -1,16 +1,30 @@
 package app
 
 import (
+	"errors"
 	"image/color"
-	"log"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
-	"time"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"karalis/internal/rlx"
 	"karalis/internal/shader"
 	"karalis/internal/stage"
+	"karalis/internal/target"
+	"karalis/pkg/capture"
 	"karalis/pkg/config"
+	"karalis/pkg/console"
+	"karalis/pkg/event"
 	"karalis/pkg/input"
+	"karalis/pkg/logging"
+	"karalis/pkg/loop"
+	"karalis/pkg/profile"
+	"karalis/pkg/settings"
 	"karalis/res"
 
 	App "karalis/pkg/app"
@@ -20,15 +34,49 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-var ()
+var (
+	logger = logging.For(logging.App)
+)
 
 type app struct {
-	curStage  pub_stage.Stage
+	stages    *stage.Stack
 	curShader pub_shader.Shader
-	console   interface{}
-
-	logicInterval int64
-	drawInterval  int64
+	console   *console.Console
+
+	//set by the quit command, stops both loops
+	quit atomic.Bool
+	//path of the crash report once a loop panicked
+	crashed atomic.Pointer[string]
+	//loop the crash command makes panic
+	panicOn atomic.Pointer[string]
+	//frame timings overlay
+	profiling atomic.Bool
+
+	//screenshot requested for the next frame and the sequence being recorded
+	shot atomic.Bool
+	rec  atomic.Pointer[capture.Recorder]
+	//held while ticking so the recorder can step the logic from the main thread
+	tickMu sync.Mutex
+
+	//logic runs in fixed ticks, frames draw between them
+	clock     *loop.FixedStep
+	targetFPS int32
+
+	//run without a window or gpu on the null rlx backend
+	headless bool
+
+	//world the game starts in
+	world string
+	seed  int64
+
+	//resource folder watched for changes while developing
+	resDir string
+
+	//replay played back once the game loads, the final position goes to replayOut
+	replay    *input.Replay
+	replayOut io.Writer
+	//file input is recorded to while playing
+	record string
 
 	width  int32
 	height int32
@@ -36,18 +84,44 @@ type app struct {
 
 // initialize app
 func (a *app) init() error {
-	a.curStage = nil
-	a.console = nil
-
-	a.width = 800
-	a.height = 512
+	a.stages = stage.NewStack()
+	a.console = console.Default
+	a.console.Register(console.Command{
+		Name: "quit",
+		Help: "exit the game",
+		Run: func(c *console.Console, args []string) error {
+			a.quit.Store(true)
+			return nil
+		},
+	})
+	a.registerProfiler()
+	a.registerCapture()
+	a.registerCrash()
+
+	//a broken settings file leaves the defaults in place
+	err := settings.Load()
+	if err != nil {
+		logger.Error("load settings", "err", err)
+	}
+	cfg := settings.Get()
+	err = logging.SetLevels(cfg.Log)
+	if err != nil {
+		return err
+	}
+	a.width = cfg.Width
+	a.height = cfg.Height
 
-	a.logicInterval = 16
-	a.drawInterval = 16
+	a.clock, err = loop.NewFixedStep(loop.DefaultTickRate, loop.DefaultMaxSteps)
+	if err != nil {
+		return err
+	}
+	a.targetFPS = cfg.FPS
+	event.On(a.onSettings)
+	a.headless = os.Getenv(strings.ToUpper(config.AppName)+"_HEADLESS") != ""
 
 	App.CurApp = a
 
-	err := res.Init()
+	err = res.Init()
 	if err != nil {
 		return err
 	}
@@ -59,19 +133,22 @@ func (a *app) init() error {
 
 // handle input
 func (a *app) handleInput(dt float32) {
-	if a.curStage != nil {
-		a.curStage.OnInput(dt)
-	}
+	a.stages.OnInput(dt)
 }
 
 // render cycle
-func (a *app) render() {
+// alpha is how far the frame is between the previous and current tick
+func (a *app) render(alpha float32) {
+	sp := profile.Begin(profile.TrackMain, "Frame")
 	rlx.BeginDrawing()
 	rlx.ClearBackground(color.RGBA{0, 0, 0, 255})
-	if a.curStage != nil {
-		a.curStage.Render()
+	a.stages.Render(alpha)
+	if a.profiling.Load() {
+		a.drawProfile()
 	}
 	rlx.EndDrawing()
+	sp.End()
+	profile.Frame()
 }
 
 // handle resizing
@@ -81,19 +158,50 @@ func (a *app) onResize() {
 
 	//check for resize event
 	if a.width != w || a.height != h {
-		if a.curStage != nil {
-			a.curStage.OnResize(w, h)
-		}
+		target.OnResize(int32(rlx.GetRenderWidth()), int32(rlx.GetRenderHeight()))
+		a.stages.OnResize(w, h)
 		a.width = w
 		a.height = h
+		event.Queue(event.WindowResized{Width: w, Height: h})
+	}
+}
+
+// apply changed window and audio settings
+func (a *app) onSettings(c settings.Changed) {
+	if c.Next.Width != c.Prev.Width || c.Next.Height != c.Prev.Height {
+		rlx.SetWindowSize(int(c.Next.Width), int(c.Next.Height))
+	}
+	if c.Next.FPS != c.Prev.FPS {
+		a.targetFPS = c.Next.FPS
+		rlx.SetTargetFPS(a.targetFPS)
+	}
+	if c.Next.Log != c.Prev.Log {
+		//validated with the settings
+		logging.SetLevels(c.Next.Log)
+	}
+	if c.Next.Volume != c.Prev.Volume && rlx.IsAudioDeviceReady() {
+		rlx.SetMasterVolume(c.Next.Volume)
+	}
+}
+
+// run a single logic tick
+func (a *app) tick(dt float32) {
+	defer profile.Begin(profile.TrackLogic, "Tick").End()
+	a.update(dt)
+	if input.Active(rlx.IsCursorOnScreen()) {
+		a.handleInput(dt)
+	}
+	if input.Tick() {
+		a.replayFinished()
 	}
 }
 
 // update cycle
 func (a *app) update(dt float32) {
-	if a.curStage != nil {
-		a.curStage.Update(dt)
-	}
+	a.stages.Update(dt)
+
+	//queued events are delivered on the logic loop after the tick
+	event.Default.Flush()
 }
 
 // get window width
@@ -106,84 +214,157 @@ func (a *app) GetHeight() int32 {
 	return a.height
 }
 
+// get the number of logic ticks per second
+func (a *app) GetTickRate() int {
+	return a.clock.GetRate()
+}
+
+// set the number of logic ticks per second
+func (a *app) SetTickRate(rate int) error {
+	return a.clock.SetRate(rate)
+}
+
 // detect if app should continue running
 func (a *app) Running() bool {
-	return !rlx.WindowShouldClose()
+	return !a.quit.Load() && !rlx.WindowShouldClose()
+}
+
+// run without a window, must be set before the app starts
+func (a *app) SetHeadless(on bool) {
+	a.headless = on
+}
+
+// pick the world kind and seed the game starts in, must be set before the app starts
+func (a *app) SetWorld(kind string, seed int64) {
+	a.world = kind
+	a.seed = seed
+}
+
+// watch a resource folder and reload files changing in it, must be set before the app starts
+func (a *app) SetResDir(dir string) {
+	a.resDir = dir
 }
 
 // main run loop for the app while running
 func (a *app) run(debug bool) error {
+	rlx.SetHeadless(a.headless)
 	rlx.EnterMainThread()
 	defer rlx.ExitMainThread()
 	rlx.SetConfigFlags(rl.FlagWindowResizable)
 	rlx.InitWindow(a.width, a.height, config.AppName)
-	rlx.SetTargetFPS(int32(time.Second / (time.Duration(a.drawInterval) * time.Millisecond)))
+	rlx.SetTargetFPS(a.targetFPS)
 
+	//the log shows in the console as well, with --debug it goes to rotated files in the config dir
+	var out io.Writer = os.Stderr
 	if debug {
-		file, err := os.OpenFile("info.log", os.O_CREATE|os.O_WRONLY, 0644)
+		file, err := logging.OpenFile(logging.FilePath(), logging.MaxFileSize, logging.KeepFiles)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		defer file.Close()
-		log.SetOutput(file)
+		out = file
 	}
-	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+	logging.SetOutput(io.MultiWriter(out, a.console))
+	defer logging.SetOutput(os.Stderr)
+	slog.SetDefault(logger)
 
 	defer a.Exit()
 	err := input.InitBindings()
 	if err != nil {
 		return err
 	}
-
-	a.curShader, err = shader.NewShader("shader")
+	err = stage.RegisterConsole()
 	if err != nil {
 		return err
 	}
-
-	menu := stage.Game{}
-	err = menu.Init()
+	err = a.registerProfilerAction()
 	if err != nil {
 		return err
 	}
-	a.SetStage(&menu)
-
-	//logic loop
-	go func() {
-		for a.Running() {
-			dt := rlx.GetFrameTime()
-			a.onResize()
-			a.update(dt)
-			if rlx.IsCursorOnScreen() {
-				a.handleInput(dt)
-			}
-			time.Sleep(time.Duration(a.logicInterval) * time.Millisecond)
+	err = a.registerCaptureAction()
+	if err != nil {
+		return err
+	}
+	a.autoexec()
+
+	//the watched folder replaces the embedded resources before anything loads them
+	if a.resDir != "" {
+		stop, err := res.Watch(a.resDir, res.WatchInterval)
+		if err != nil {
+			return err
 		}
-	}()
+		defer stop()
+	}
+
+	a.curShader, err = shader.NewShader("shader")
+	if err != nil {
+		return err
+	}
 
-	//handle gpu calls
-	for a.Running() {
-		a.render()
-		rlx.Poll()
-		time.Sleep(time.Duration(a.drawInterval) * time.Millisecond)
+	//the game initializes off the main thread behind a loading stage
+	game := &stage.Game{World: a.world, Seed: a.seed, Replay: a.replay, Record: a.record != ""}
+	if a.replay != nil {
+		game.World = a.replay.World
+		game.Seed = a.replay.Seed
 	}
+	a.LoadStage(game, stage.NewFade(0.5))
+
+	//the logic loop is given time to finish its tick before the window closes
+	done := make(chan struct{})
+	go a.logicLoop(done)
+	a.mainLoop()
+	if !rlx.Drain(done, ShutdownTimeout) {
+		logger.Error("logic loop did not stop", "timeout", ShutdownTimeout)
+	}
+	//a logic loop still running gets errors rather than waiting on a main thread that stopped polling
+	rlx.Shutdown()
+	a.saveRecord()
 
-	return nil
+	return a.crashErr()
 }
 
-// set  the currently active stage
-func (a *app) SetStage(nextStage pub_stage.Stage) {
-	if a.curStage != nil {
-		a.curStage.OnRemove()
-	}
-	if nextStage != nil {
-		a.curStage = nextStage
-		a.curStage.OnAdd()
+// run the autoexec.cfg in the config dir if there is one
+func (a *app) autoexec() {
+	path := filepath.Join(config.ConfigDir, "autoexec.cfg")
+	err := a.console.ExecFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Error("autoexec", "err", err)
 	}
 }
 
-// get the currently active stage in the app
+// replace every stage with the given one
+func (a *app) SetStage(nextStage pub_stage.Stage) {
+	a.stages.Set(nextStage)
+}
+
+// get the stage stack of the app
 func (a *app) GetStage() pub_stage.Stage {
-	return a.curStage
+	return a.stages
+}
+
+// get the stage on top of the stack
+func (a *app) GetTopStage() pub_stage.Stage {
+	return a.stages.Top()
+}
+
+// push a stage over the current ones on the next update
+func (a *app) PushStage(next pub_stage.Stage, tr pub_stage.Transition) {
+	a.stages.Push(next, tr)
+}
+
+// pop the top stage on the next update
+func (a *app) PopStage(tr pub_stage.Transition) {
+	a.stages.Pop(tr)
+}
+
+// replace the top stage on the next update
+func (a *app) ChangeStage(next pub_stage.Stage, tr pub_stage.Transition) {
+	a.stages.Change(next, tr)
+}
+
+// replace the top stage, showing a loading stage while next initializes
+func (a *app) LoadStage(next pub_stage.Stage, tr pub_stage.Transition) {
+	a.stages.Load(next, tr)
 }
 
 // get the currently active shader in the app
@@ -193,12 +374,11 @@ func (a *app) GetShader() pub_shader.Shader {
 
 // Exit the application
 func (a *app) Exit() {
-	if a.curStage != nil {
-		a.curStage.OnRemove()
-	}
+	a.stages.OnRemove()
 	if a.curShader != nil {
 		a.curShader.OnRemove()
 	}
+	target.Unload()
 	rlx.CloseWindow()
 }
 
@@ -212,7 +392,7 @@ func NewApp() *app {
 	a := &app{}
 	err := a.init()
 	if err != nil {
-		log.Printf("ERR: %+v\n", err)
+		logger.Error("init app", "err", err)
 		return nil
 	}
 	return a
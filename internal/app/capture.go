@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"karalis/internal/stage"
+	"karalis/pkg/capture"
+	"karalis/pkg/console"
+	"karalis/pkg/input"
+)
+
+const (
+	// frame rate of sequences recorded without one given
+	DefaultRecordFPS = 30
+)
+
+// register the capture commands
+func (a *app) registerCapture() {
+	a.console.Register(console.Command{
+		Name: "screenshot",
+		Help: "save the next frame to the captures folder",
+		Run: func(c *console.Console, args []string) error {
+			a.shot.Store(true)
+			return nil
+		},
+	})
+	a.console.Register(console.Command{
+		Name: "record_start",
+		Args: "[fps]",
+		Help: "record a png sequence, the game steps 1/fps per frame however long frames take",
+		Run: func(c *console.Console, args []string) error {
+			fps := DefaultRecordFPS
+			if len(args) > 0 {
+				var err error
+				fps, err = strconv.Atoi(args[0])
+				if err != nil {
+					return fmt.Errorf("Invalid fps: %s", args[0])
+				}
+			}
+			return a.StartRecording(fps)
+		},
+	})
+	a.console.Register(console.Command{
+		Name: "record_stop",
+		Help: "stop recording",
+		Run: func(c *console.Console, args []string) error {
+			a.StopRecording()
+			return nil
+		},
+	})
+}
+
+// register the screenshot action, once the bindings are loaded
+func (a *app) registerCaptureAction() error {
+	shot := func() {
+		a.shot.Store(true)
+	}
+	return input.RegisterAction("Screenshot", &shot, nil, true)
+}
+
+// start recording a frame sequence to the captures folder
+func (a *app) StartRecording(fps int) error {
+	if a.rec.Load() != nil {
+		return fmt.Errorf("Already recording")
+	}
+
+	rec, err := capture.NewRecorder(capture.Dir(), fps, a.GetTickRate())
+	if err != nil {
+		return err
+	}
+	a.rec.Store(rec)
+	logger.Info("recording", "dir", rec.Dir)
+	return nil
+}
+
+// stop recording, logic goes back to real time
+func (a *app) StopRecording() {
+	rec := a.rec.Swap(nil)
+	if rec != nil {
+		logger.Info("recorded", "frames", rec.GetFrames(), "dir", rec.Dir)
+	}
+}
+
+// save the last game frame, encoding off the main thread
+func (a *app) screenshot() {
+	img, err := stage.CaptureGame()
+	if err != nil {
+		logger.Error("screenshot", "err", err)
+		return
+	}
+
+	path := capture.NextScreenshot(capture.Dir(), time.Now())
+	go func() {
+		err := capture.Save(img, path)
+		if err != nil {
+			logger.Error("save screenshot", "err", err)
+			return
+		}
+		logger.Info("saved screenshot", "path", path)
+	}()
+}
+
+// step the logic by one recorded frame, draw it and save it
+// runs on the main thread while the logic loop waits
+func (a *app) recordFrame(rec *capture.Recorder) {
+	steps, alpha := rec.Advance()
+	a.ticks(steps)
+	a.render(alpha)
+
+	//frames drawn while the game loads are not kept
+	img, err := stage.CaptureGame()
+	if errors.Is(err, stage.ErrNoGame) {
+		return
+	}
+	if err == nil {
+		err = capture.Save(img, rec.NextFrame())
+	}
+	if err != nil {
+		logger.Error("record frame", "err", err)
+		a.StopRecording()
+	}
+}
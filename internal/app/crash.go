@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"karalis/internal/rlx"
+	"karalis/internal/stage"
+	"karalis/pkg/console"
+	"karalis/pkg/crash"
+	"karalis/pkg/profile"
+)
+
+const (
+	// how long shutdown serves the main thread calls of the logic loop before closing the window anyway
+	ShutdownTimeout = 2 * time.Second
+)
+
+// register the crash command for checking reports are written
+func (a *app) registerCrash() {
+	a.console.Register(console.Command{
+		Name: "crash",
+		Args: "[main|logic]",
+		Help: "panic on a loop, writing a crash report",
+		Run: func(c *console.Console, args []string) error {
+			thread := profile.TrackLogic
+			if len(args) > 0 {
+				thread = args[0]
+			}
+			if thread != profile.TrackMain && thread != profile.TrackLogic {
+				return fmt.Errorf("Invalid loop: %s", thread)
+			}
+			a.panicOn.Store(&thread)
+			return nil
+		},
+	})
+}
+
+// panic if the crash command asked for it on this loop
+func (a *app) checkPanic(thread string) {
+	if on := a.panicOn.Load(); on != nil && *on == thread {
+		a.panicOn.Store(nil)
+		panic("crash command")
+	}
+}
+
+// write a crash report for a recovered panic and stop both loops
+// called from the deferred recover of the loop that panicked
+func (a *app) crash(thread string, val interface{}) {
+	a.quit.Store(true)
+
+	r := crash.NewReport(thread, val, debug.Stack())
+	stage.FillReport(r, a.stages)
+	path, err := r.Save(crash.Dir())
+	if err != nil {
+		logger.Error("panic, no crash report", "loop", thread, "panic", val, "err", err)
+		path = "unsaved"
+	} else {
+		logger.Error("panic", "loop", thread, "panic", val, "report", path)
+	}
+	a.crashed.CompareAndSwap(nil, &path)
+}
+
+// get the error the app stopped with after a crash, nil when it did not crash
+func (a *app) crashErr() error {
+	path := a.crashed.Load()
+	if path == nil {
+		return nil
+	}
+	return fmt.Errorf("Crashed, report: %s", *path)
+}
+
+// logic loop, runs the ticks due since the last pass with a constant dt
+// done is closed once it stops, panics in a tick stop the app
+func (a *app) logicLoop(done chan struct{}) {
+	defer close(done)
+	defer func() {
+		if val := recover(); val != nil {
+			a.crash(profile.TrackLogic, val)
+		}
+	}()
+
+	a.clock.Reset(time.Now())
+	for a.Running() {
+		//while recording the main thread steps the logic per frame
+		if a.rec.Load() != nil {
+			a.clock.Reset(time.Now())
+			time.Sleep(a.clock.GetStep())
+			continue
+		}
+
+		a.checkPanic(profile.TrackLogic)
+		//replays checked from the command line do not wait for the clock
+		if a.replayOut != nil {
+			a.ticks(1)
+			continue
+		}
+		a.ticks(a.clock.Advance(time.Now()))
+		time.Sleep(a.clock.Until(time.Now()))
+	}
+}
+
+// handle gpu calls, resizes are detected here as the window belongs to this thread
+// frames are paced by the target fps, panics in a frame stop the app
+func (a *app) mainLoop() {
+	defer func() {
+		if val := recover(); val != nil {
+			a.crash(profile.TrackMain, val)
+		}
+	}()
+
+	for a.Running() {
+		a.checkPanic(profile.TrackMain)
+		a.onResize()
+		if rec := a.rec.Load(); rec != nil {
+			a.recordFrame(rec)
+		} else {
+			a.render(a.clock.Alpha(time.Now()))
+		}
+		if a.shot.Swap(false) {
+			a.screenshot()
+		}
+		rlx.Poll()
+	}
+}
+
+// run ticks holding the tick lock, released even if a tick panics
+func (a *app) ticks(steps int) {
+	rlx.Lock(&a.tickMu)
+	defer a.tickMu.Unlock()
+	for range steps {
+		a.tick(a.clock.Dt())
+	}
+}
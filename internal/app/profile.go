@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"karalis/internal/rlx"
+	"karalis/pkg/console"
+	"karalis/pkg/input"
+	"karalis/pkg/profile"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	profileFontSize = 16
+	// spans listed by the overlay
+	profileRows = 24
+)
+
+// register the overlay toggle as a console variable
+func (a *app) registerProfiler() {
+	a.console.RegisterVar(console.BoolVar("r_profile", "show frame timings",
+		func() bool { return a.profiling.Load() },
+		func(on bool) error {
+			a.profiling.Store(on)
+			profile.Enable(on)
+			return nil
+		}))
+	a.console.Register(console.Command{
+		Name: "rlx_stats",
+		Help: "show the depth and counters of the gpu request queue",
+		Run: func(c *console.Console, args []string) error {
+			s := rlx.GetStats()
+			c.Printf("queue %d/%d, peak %d, full %d\n", s.Depth, s.Capacity, s.Peak, s.Full)
+			c.Printf("served %d, failed %d, recorded %d\n", s.Served, s.Failed, s.Recorded)
+			return nil
+		},
+	})
+}
+
+// register the action toggling the overlay, once the bindings are loaded
+func (a *app) registerProfilerAction() error {
+	toggle := func() {
+		on := !a.profiling.Load()
+		a.profiling.Store(on)
+		profile.Enable(on)
+	}
+	return input.RegisterAction("ToggleProfiler", &toggle, nil, true)
+}
+
+// draw the rolling stats over the frame
+func (a *app) drawProfile() {
+	stats := profile.GetStats()
+	step := int32(profileFontSize + 2)
+	rows := min(len(stats), profileRows) + 1
+	rlx.DrawRectangle(4, 4, 560, int32(rows)*step+8, rlx.Fade(rl.Black, 0.7))
+
+	x, y := int32(10), int32(8)
+	rlx.DrawText(fmt.Sprintf("%-32s %8s %8s %7s", "span", "avg ms", "max ms", "calls"), x, y, profileFontSize, rl.Yellow)
+	for i, s := range stats {
+		if i >= profileRows {
+			break
+		}
+		y += step
+		line := fmt.Sprintf("%-32.32s %8.2f %8.2f %7.1f", s.Name, ms(s.Avg), ms(s.Max), s.Calls)
+		if s.Counter {
+			line = fmt.Sprintf("%-32.32s %8.1f %8.1f %7s", s.Name, s.Value, s.Peak, "")
+		}
+		rlx.DrawText(line, x, y, profileFontSize, rl.RayWhite)
+	}
+}
+
+// duration in milliseconds
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"karalis/pkg/input"
+)
+
+// play back a replay once the game loads, must be set before the app starts
+// with out set the replay runs as fast as it can, then the final player position is written to out and the app quits
+func (a *app) SetReplay(r *input.Replay, out io.Writer) error {
+	err := r.Validate()
+	if err != nil {
+		return err
+	}
+	//ticks are only the same at the recorded rate
+	err = a.SetTickRate(r.TickRate)
+	if err != nil {
+		return err
+	}
+	a.replay = r
+	a.replayOut = out
+	return nil
+}
+
+// record input from the moment the game loads until the app quits, must be set before the app starts
+func (a *app) SetRecord(path string) {
+	a.record = path
+}
+
+// handle a replay playing back its last tick, called on the logic loop
+func (a *app) replayFinished() {
+	if a.replayOut != nil {
+		defer a.quit.Store(true)
+	}
+	player := a.stages.GetPlayer()
+	if player == nil {
+		logger.Warn("replay finished without a player")
+		return
+	}
+	pos := player.GetPos()
+	logger.Info("replay finished", "ticks", input.GetTick(), "x", pos.X, "y", pos.Y, "z", pos.Z)
+	if a.replayOut != nil {
+		fmt.Fprintf(a.replayOut, "%.4f %.4f %.4f\n", pos.X, pos.Y, pos.Z)
+	}
+}
+
+// save the recording started with SetRecord, called once both loops stopped
+func (a *app) saveRecord() {
+	r := input.StopRecording()
+	if r == nil || a.record == "" {
+		return
+	}
+	err := r.Save(a.record)
+	if err != nil {
+		logger.Error("save replay", "file", a.record, "err", err)
+		return
+	}
+	logger.Info("saved replay", "file", a.record, "ticks", r.Ticks)
+}
@@ -19,6 +19,20 @@ type Cam struct {
 	yaw   float32
 	pitch float32
 	roll  float32
+
+	//camera drawn with, blended between the previous and current tick
+	view  rl.Camera
+	prev  camState
+	saved bool
+}
+
+// orbit state of the camera at one tick
+type camState struct {
+	tar   rl.Vector3
+	dist  float32
+	yaw   float32
+	pitch float32
+	roll  float32
 }
 
 func NewCam() (s *Cam, err error) {
@@ -44,6 +58,7 @@ func (s *Cam) Init() error {
 	s.roll = 0
 	s.pitch = -30
 	s.yaw = 45
+	s.saved = false
 	s.UpdateCam()
 
 	return nil
@@ -68,7 +83,7 @@ func (s *Cam) Render() []func() {
 		return []func(){}
 	}
 
-	rlx.BeginMode3D(s.camera)
+	rlx.BeginMode3D(s.view)
 	return []func(){rlx.EndMode3D}
 }
 
@@ -142,7 +157,7 @@ func (s *Cam) GetCameraMatrix() rl.Matrix {
 		return rl.Matrix{}
 	}
 
-	return rlx.GetCameraMatrix(s.camera)
+	return rlx.GetCameraMatrix(s.view)
 }
 
 func (s *Cam) GetWorldToScreen(pos rl.Vector3) rl.Vector2 {
@@ -150,7 +165,7 @@ func (s *Cam) GetWorldToScreen(pos rl.Vector3) rl.Vector2 {
 		return rl.Vector2{}
 	}
 
-	return rlx.GetWorldToScreen(pos, s.camera)
+	return rlx.GetWorldToScreen(pos, s.view)
 }
 
 func (s *Cam) GetProjMatrix(width int32, height int32) rl.Matrix {
@@ -167,8 +182,8 @@ func (s *Cam) GetViewProjMatrix() rl.Matrix {
 	}
 
 	aspect := float32(rlx.GetScreenWidth()) / float32(rlx.GetScreenHeight())
-	view := rlx.GetCameraViewMatrix(&s.camera)
-	proj := rlx.GetCameraProjectionMatrix(&s.camera, aspect)
+	view := rlx.GetCameraViewMatrix(&s.view)
+	proj := rlx.GetCameraProjectionMatrix(&s.view, aspect)
 	return rl.MatrixMultiply(view, proj)
 }
 
@@ -236,6 +251,24 @@ func (s *Cam) SetRoll(r float32) {
 	s.roll = r
 }
 
+// get the vertical field of view in degrees
+func (s *Cam) GetFovy() float32 {
+	if s == nil {
+		return 0
+	}
+
+	return s.camera.Fovy
+}
+
+// set the vertical field of view in degrees
+func (s *Cam) SetFovy(fovy float32) {
+	if s == nil {
+		return
+	}
+
+	s.camera.Fovy = fovy
+}
+
 func (s *Cam) ZoomCam(zoom float32) {
 	if s == nil {
 		return
@@ -268,14 +301,57 @@ func (s *Cam) UpdateCam() {
 		return
 	}
 
+	s.camera.Position = s.orbit(s.getState())
+	s.view = s.camera
+}
+
+// remember the orbit at the start of a tick
+func (s *Cam) SaveState() {
+	if s == nil {
+		return
+	}
+
+	s.prev = s.getState()
+	s.saved = true
+}
+
+// place the drawn camera between the previous and current tick
+func (s *Cam) Lerp(alpha float32) {
+	if s == nil || !s.saved {
+		return
+	}
+
+	cur := s.getState()
+	st := camState{
+		tar:   rl.Vector3Lerp(s.prev.tar, cur.tar, alpha),
+		dist:  s.prev.dist + (cur.dist-s.prev.dist)*alpha,
+		yaw:   s.prev.yaw + (cur.yaw-s.prev.yaw)*alpha,
+		pitch: s.prev.pitch + (cur.pitch-s.prev.pitch)*alpha,
+		roll:  s.prev.roll + (cur.roll-s.prev.roll)*alpha,
+	}
+	s.view = s.camera
+	s.view.Target = st.tar
+	s.view.Position = s.orbit(st)
+}
+
+func (s *Cam) getState() camState {
+	return camState{
+		tar:   s.camera.Target,
+		dist:  s.dist,
+		yaw:   s.yaw,
+		pitch: s.pitch,
+		roll:  s.roll,
+	}
+}
+
+// get the camera position orbiting the target
+func (s *Cam) orbit(st camState) rl.Vector3 {
 	ql := lmath.Quat{}
-	ql = *ql.FromEuler(rl.Deg2rad*float64(s.pitch), rl.Deg2rad*float64(s.yaw), float64(s.roll))
+	ql = *ql.FromEuler(rl.Deg2rad*float64(st.pitch), rl.Deg2rad*float64(st.yaw), float64(st.roll))
 
-	view := ql.RotateVec3(lmath.Vec3{0, 0, float64(s.dist)})
+	view := ql.RotateVec3(lmath.Vec3{0, 0, float64(st.dist)})
 
-	s.camera.Position.X = float32(view.X) + s.camera.Target.X
-	s.camera.Position.Y = float32(view.Y) + s.camera.Target.Y
-	s.camera.Position.Z = float32(view.Z) + s.camera.Target.Z
+	return rl.NewVector3(float32(view.X)+st.tar.X, float32(view.Y)+st.tar.Y, float32(view.Z)+st.tar.Z)
 }
 
 func (s *Cam) OnAdd(obj pub_object.Object) {
@@ -14,7 +14,8 @@ import (
 )
 
 type Collider struct {
-	obj pub_object.Object
+	obj   pub_object.Object
+	shape string
 
 	touching      []pub_object.Object
 	last_touching []pub_object.Object
@@ -32,7 +33,8 @@ func NewCollider(obj pub_object.Object) (*Collider, error) {
 	}
 
 	col := Collider{
-		obj: obj,
+		obj:   obj,
+		shape: pub_object.ShapeAABB,
 	}
 
 	col.touching = []pub_object.Object{}
@@ -56,6 +58,28 @@ func (c *Collider) GetObj() pub_object.Object {
 	return c.obj
 }
 
+func (c *Collider) GetShape() string {
+	if c == nil {
+		return pub_object.ShapeNone
+	}
+
+	return c.shape
+}
+
+func (c *Collider) SetShape(shape string) error {
+	if c == nil {
+		return fmt.Errorf("Invalid collider")
+	}
+
+	switch shape {
+	case pub_object.ShapeAABB, pub_object.ShapeSphere, pub_object.ShapeOBB:
+		c.shape = shape
+		return nil
+	default:
+		return fmt.Errorf("Invalid collider shape: %s", shape)
+	}
+}
+
 func (c *Collider) Collide(data pub_object.CollisionData) {
 	if c == nil {
 		return
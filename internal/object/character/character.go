@@ -11,6 +11,8 @@ import (
 var ()
 
 type Character struct {
+	meta pub_object.Meta
+
 	parent pub_object.Object
 }
 
@@ -25,6 +27,7 @@ func (c *Character) Init() error {
 	if c == nil {
 		return nil
 	}
+	c.meta.SetName("character")
 	c.parent = nil
 	return nil
 }
@@ -85,6 +88,7 @@ func (c *Character) OnRemove() {
 		return
 	}
 	c.parent = nil
+	c.meta.Release()
 }
 
 func (c *Character) AddChild(obj object.Object) {
@@ -167,3 +171,11 @@ func (c *Character) GetParent() pub_object.Object {
 	}
 	return c.parent
 }
+
+func (c *Character) GetMeta() *pub_object.Meta {
+	if c == nil {
+		return nil
+	}
+
+	return &c.meta
+}
@@ -15,9 +15,14 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-var ()
+var (
+	// scales how far the camera turns per pixel of mouse movement
+	MouseSensitivity = float32(1)
+)
 
 type Player struct {
+	meta pub_object.Meta
+
 	cam  pub_object.Camera
 	char *Character
 
@@ -44,13 +49,15 @@ func (p *Player) Init() (err error) {
 	if p == nil {
 		return nil
 	}
+	p.meta.SetName("player")
 	p.parent = nil
 
 	p.pos = rl.NewVector3(0, 0, 0)
 	p.rot = rl.NewVector3(0, 0, 0)
 	p.scale = rl.NewVector3(1, 1, 1)
 
-	p.rchan = make(chan int)
+	//buffered so ticks never wait on a frame to recenter the mouse
+	p.rchan = make(chan int, 1)
 
 	p.char, err = NewCharacter()
 	if err != nil {
@@ -133,6 +140,17 @@ func (p *Player) Update(dt float32) {
 	p.char.Update(dt)
 }
 
+// keep the camera blending between ticks along with the player
+func (p *Player) SaveState() {
+	if p == nil {
+		return
+	}
+
+	if cam, ok := p.cam.(pub_object.Interpolated); ok {
+		cam.SaveState()
+	}
+}
+
 func (p *Player) GetCollider() object.Collider {
 	if p == nil {
 		return nil
@@ -153,6 +171,7 @@ func (p *Player) OnRemove() {
 		return
 	}
 	p.parent = nil
+	p.meta.Release()
 }
 
 func (p *Player) AddChild(obj object.Object) {
@@ -357,11 +376,19 @@ func (p *Player) CaptureMouse() {
 		return
 	}
 
-	p.rchan <- 1
+	p.recenter()
 
 	p.capture = true
 }
 
+// ask the next frame to move the mouse back to the window center
+func (p *Player) recenter() {
+	select {
+	case p.rchan <- 1:
+	default:
+	}
+}
+
 func (p *Player) ReleaseMouse() {
 	if p == nil {
 		return
@@ -442,21 +469,69 @@ func (p *Player) OnInput(dt float32) {
 		move.Z *= 3
 	}
 
-	if p.MouseCaptured() {
+	//a replay brings its own mouse movement
+	//the mouse is only recentered when a frame is drawn
+	//ticks catching up before that would apply the same offset again
+	if rz, rdx, rdy, ok := input.PlaybackLook(); ok {
+		zoom, dx, dy = rz, rdx, rdy
+	} else if p.MouseCaptured() && len(p.rchan) == 0 {
 		mpos := rlx.GetMousePosition()
 		rlx.DisableCursor()
 		rlx.HideCursor()
 
 		zoom = float32(rlx.GetMouseWheelMove()) * dt * 20
 
-		dx = dt * 200 * rl.Deg2rad * (float32(app.CurApp.GetWidth())/2 - mpos.X)
-		dy = dt * 200 * rl.Deg2rad * (float32(app.CurApp.GetHeight())/2 - mpos.Y)
+		dx = dt * 200 * MouseSensitivity * rl.Deg2rad * (float32(app.CurApp.GetWidth())/2 - mpos.X)
+		dy = dt * 200 * MouseSensitivity * rl.Deg2rad * (float32(app.CurApp.GetHeight())/2 - mpos.Y)
 
-		p.rchan <- 1
+		p.recenter()
+		input.RecordLook(zoom, dx, dy)
 	}
 	p.updateCam(move, zoom, dx, dy)
 }
 
+// get the camera mode, 0 first person and 1 orbiting
+func (p *Player) GetMode() int {
+	if p == nil {
+		return 0
+	}
+
+	return p.mode
+}
+
+// set the camera mode, the distance is picked up on the next SetPose or input
+func (p *Player) SetMode(mode int) {
+	if p == nil {
+		return
+	}
+
+	p.mode = mode
+}
+
+// get where the player is and looks, replays start from it
+func (p *Player) GetPose() input.Pose {
+	if p == nil || p.cam == nil {
+		return input.Pose{}
+	}
+
+	return input.Pose{Pos: p.cam.GetTar(), Yaw: p.cam.GetYaw(), Pitch: p.cam.GetPitch(), Dist: p.cam.GetDist()}
+}
+
+// move the player and camera to a pose
+func (p *Player) SetPose(pose input.Pose) {
+	if p == nil || p.cam == nil {
+		return
+	}
+
+	p.cam.SetYaw(pose.Yaw)
+	p.cam.SetPitch(pose.Pitch)
+	p.cam.SetDist(pose.Dist)
+	//the camera target leads and the player position follows it
+	p.cam.SetTar(pose.Pos)
+	p.SetPos(pose.Pos)
+	p.updateCam(lmath.Vec3{}, 0, 0, 0)
+}
+
 func (p *Player) updateCam(move lmath.Vec3, zoom, dx, dy float32) {
 	if p == nil {
 		return
@@ -502,3 +577,11 @@ func (p *Player) GetParent() pub_object.Object {
 	}
 	return p.parent
 }
+
+func (p *Player) GetMeta() *pub_object.Meta {
+	if p == nil {
+		return nil
+	}
+
+	return &p.meta
+}
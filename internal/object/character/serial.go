@@ -0,0 +1,52 @@
+package character
+
+import (
+	"fmt"
+
+	pub_scene "karalis/pkg/scene"
+)
+
+func init() {
+	pub_scene.Register("Player", func(n pub_scene.Node) (*Player, error) {
+		return NewPlayer()
+	})
+}
+
+// keep how the camera looks at the player, its target is the player position
+func (p *Player) Serialize() map[string]interface{} {
+	if p == nil || p.cam == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"yaw":   p.cam.GetYaw(),
+		"pitch": p.cam.GetPitch(),
+		"dist":  p.cam.GetDist(),
+	}
+}
+
+// move the camera onto the decoded position, which is set before props are read
+func (p *Player) Deserialize(props map[string]interface{}) error {
+	if p == nil || p.cam == nil {
+		return fmt.Errorf("Invalid player")
+	}
+
+	pose := p.GetPose()
+	pose.Pos = p.GetPos()
+	pose.Yaw = propFloat(props, "yaw", pose.Yaw)
+	pose.Pitch = propFloat(props, "pitch", pose.Pitch)
+	pose.Dist = propFloat(props, "dist", pose.Dist)
+	p.SetPose(pose)
+	return nil
+}
+
+// read a number from props, which are float64 once decoded from json
+func propFloat(props map[string]interface{}, key string, def float32) float32 {
+	switch v := props[key].(type) {
+	case float64:
+		return float32(v)
+	case float32:
+		return v
+	}
+	return def
+}
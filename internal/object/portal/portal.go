@@ -2,22 +2,24 @@ package portal
 
 import (
 	"image/color"
-	"log"
-	"runtime"
 	"slices"
 
 	"karalis/internal/object/prim"
 	"karalis/internal/rlx"
 	"karalis/internal/scene"
+	"karalis/internal/target"
 	"karalis/pkg/app"
 	"karalis/pkg/lmath"
+	"karalis/pkg/logging"
 
 	pub_object "karalis/pkg/object"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-var ()
+var (
+	logger = logging.For(logging.Shader)
+)
 
 type Portal struct {
 	parent pub_object.Object
@@ -25,10 +27,10 @@ type Portal struct {
 
 	exit *Portal
 
-	cleaner *runtime.Cleanup
-	target  *rl.RenderTexture2D
-	obj     pub_object.Object
-	cam     pub_object.Camera
+	//pooled target the view through the portal is drawn into, resized by the target manager
+	target *target.Target
+	obj    pub_object.Object
+	cam    pub_object.Camera
 
 	touching []pub_object.Object
 
@@ -76,17 +78,10 @@ func (p *Portal) Init(scene pub_object.Cell, exit *Portal, cam pub_object.Camera
 		}
 	}
 
-	text := rlx.LoadRenderTexture(app.CurApp.GetWidth(), app.CurApp.GetHeight())
-	rlx.SetTextureFilter(text.Texture, rl.FilterBilinear)
-	rlx.SetTextureWrap(text.Texture, rl.WrapRepeat)
-	p.target = &text
-	if p.cleaner != nil {
-		p.cleaner.Stop()
+	err := p.acquireTarget()
+	if err != nil {
+		return err
 	}
-	cleaner := runtime.AddCleanup(p, func(text rl.RenderTexture2D) {
-		rlx.UnloadRenderTexture(text)
-	}, text)
-	p.cleaner = &cleaner
 
 	p.touching = []pub_object.Object{}
 
@@ -306,22 +301,35 @@ func (p *Portal) GetMaterials() *rl.Material {
 	return &rl.Material{}
 }
 
-// set portal render texture
+// set portal render texture, the portal draws into its own target so this does nothing
 func (p *Portal) SetTexture(tex rl.Texture2D) {
 	if p == nil {
 		return
 	}
-
-	p.target.Texture = tex
 }
 
-// get portal render texture
+// get portal render texture, fetched every frame as the target manager swaps it on resize
 func (p *Portal) GetTexture() *rl.Texture2D {
 	if p == nil {
 		return nil
 	}
 
-	return &p.target.Texture
+	tex := p.target.Texture()
+	return &tex
+}
+
+// take a target from the pool to draw the view through the portal into
+func (p *Portal) acquireTarget() error {
+	if p.target != nil {
+		return nil
+	}
+
+	rt, err := target.Acquire(target.Options{})
+	if err != nil {
+		return err
+	}
+	p.target = rt
+	return nil
 }
 
 // set portal render object
@@ -336,8 +344,6 @@ func (p *Portal) SetPortal(obj pub_object.Portal) {
 	p.obj = obj
 	p.obj.OnAdd(p)
 	p.obj.SetTexture(p.GetTexture())
-	rlx.SetTextureFilter(p.target.Texture, rl.FilterBilinear)
-	rlx.SetTextureWrap(p.target.Texture, rl.WrapRepeat)
 }
 
 // get portal render object
@@ -420,23 +426,29 @@ func (p *Portal) Prerender(cam pub_object.Camera) []func() {
 		p.cam.SetPos(rl.Vector3Transform(pos, mat))
 		p.cam.SetTar(rl.Vector3Transform(tar, mat))
 
-		//render from portals perspective
-		rlx.BeginTextureMode(*p.target)
+		//render from portals perspective, the texture is set up again as a resize swaps it
+		rt := p.target.RenderTexture()
+		rlx.SetTextureFilter(rt.Texture, rl.FilterBilinear)
+		rlx.SetTextureWrap(rt.Texture, rl.WrapRepeat)
+		if p.obj != nil {
+			p.obj.SetTexture(rt.Texture)
+		}
+		rlx.BeginTextureMode(rt)
 		rlx.ClearBackground(color.RGBA{255, 255, 255, 255})
 		sh := app.CurApp.GetShader()
 		err := sh.SetDefine("PORTAL_SCN", true)
 		if err != nil {
-			log.Printf("%+v\n", err)
+			logger.Error("set portal define", "define", "PORTAL_SCN", "err", err)
 			p.visible = false
 		}
 		err = sh.SetUniform("portalPos", p.exit.obj.GetPos())
 		if err != nil {
-			log.Printf("%+v\n", err)
+			logger.Error("set portal uniform", "uniform", "portalPos", "err", err)
 			p.visible = false
 		}
 		err = sh.SetUniform("portalNorm", p.exit.GetNormal())
 		if err != nil {
-			log.Printf("%+v\n", err)
+			logger.Error("set portal uniform", "uniform", "portalNorm", "err", err)
 			p.visible = false
 		}
 
@@ -450,7 +462,7 @@ func (p *Portal) Prerender(cam pub_object.Camera) []func() {
 
 		err = sh.SetUniform("portalMat", mat)
 		if err != nil {
-			log.Printf("%+v\n", err)
+			logger.Error("set portal uniform", "uniform", "portalMat", "err", err)
 			p.visible = false
 		}
 		for _, obj := range p.exit.touching {
@@ -458,7 +470,7 @@ func (p *Portal) Prerender(cam pub_object.Camera) []func() {
 		}
 		err = sh.SetUniform("portalMat", rl.MatrixIdentity())
 		if err != nil {
-			log.Printf("%+v\n", err)
+			logger.Error("set portal uniform", "uniform", "portalMat", "err", err)
 			p.visible = false
 		}
 
@@ -475,7 +487,7 @@ func (p *Portal) Prerender(cam pub_object.Camera) []func() {
 
 		err = sh.SetDefine("PORTAL_SCN", false)
 		if err != nil {
-			log.Printf("%+v\n", err)
+			logger.Error("set portal define", "define", "PORTAL_SCN", "err", err)
 			p.visible = false
 		}
 		rlx.EndTextureMode()
@@ -504,13 +516,13 @@ func (p *Portal) Render(cam pub_object.Camera) []func() {
 			sh := app.CurApp.GetShader()
 			err := sh.SetDefine("PORTAL_OBJ", true)
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal define", "define", "PORTAL_OBJ", "err", err)
 				p.visible = false
 			}
 			cmds = p.obj.Render(cam)
 			err = sh.SetDefine("PORTAL_OBJ", false)
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal define", "define", "PORTAL_OBJ", "err", err)
 				p.visible = false
 			}
 		}
@@ -521,17 +533,17 @@ func (p *Portal) Render(cam pub_object.Camera) []func() {
 			sh := app.CurApp.GetShader()
 			err := sh.SetDefine("PORTAL_SCN", true)
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal define", "define", "PORTAL_SCN", "err", err)
 				p.visible = false
 			}
 			err = sh.SetUniform("portalPos", p.obj.GetPos())
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal uniform", "uniform", "portalPos", "err", err)
 				p.visible = false
 			}
 			err = sh.SetUniform("portalNorm", p.GetNormal())
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal uniform", "uniform", "portalNorm", "err", err)
 				p.visible = false
 			}
 
@@ -544,7 +556,7 @@ func (p *Portal) Render(cam pub_object.Camera) []func() {
 			mat := rl.MatrixInvert(p.GetTransform())
 			err = sh.SetUniform("portalMat", mat)
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal uniform", "uniform", "portalMat", "err", err)
 				p.visible = false
 			}
 			for _, obj := range p.touching {
@@ -552,7 +564,7 @@ func (p *Portal) Render(cam pub_object.Camera) []func() {
 			}
 			err = sh.SetUniform("portalMat", rl.MatrixIdentity())
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal uniform", "uniform", "portalMat", "err", err)
 				p.visible = false
 			}
 
@@ -563,7 +575,7 @@ func (p *Portal) Render(cam pub_object.Camera) []func() {
 
 			err = sh.SetDefine("PORTAL_SCN", false)
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set portal define", "define", "PORTAL_SCN", "err", err)
 				p.visible = false
 			}
 		}
@@ -640,6 +652,10 @@ func (p *Portal) OnAdd(obj pub_object.Object) {
 		return
 	}
 	p.parent = obj
+	err := p.acquireTarget()
+	if err != nil {
+		logger.Error("portal target", "err", err)
+	}
 }
 
 // handle remove event
@@ -648,6 +664,9 @@ func (p *Portal) OnRemove() {
 		return
 	}
 	p.parent = nil
+	//back to the pool for the next portal
+	p.target.Release()
+	p.target = nil
 }
 
 // handle resize event
@@ -656,20 +675,7 @@ func (p *Portal) OnResize(w int32, h int32) {
 		return
 	}
 
-	if p.target != nil {
-		rlx.UnloadRenderTexture(*p.target)
-		p.target = nil
-	}
-	text := rlx.LoadRenderTexture(app.CurApp.GetWidth(), app.CurApp.GetHeight())
-	rlx.SetTextureFilter(text.Texture, rl.FilterBilinear)
-	rlx.SetTextureWrap(text.Texture, rl.WrapRepeat)
-	p.target = &text
-	if p.cleaner != nil {
-		p.cleaner.Stop()
-	}
-	p.cleaner = &runtime.AddCleanup(p, func(text rl.RenderTexture2D) {
-		rlx.UnloadRenderTexture(text)
-	}, text)
+	//the target manager resizes the render target
 }
 
 // add child to object
@@ -15,6 +15,8 @@ import (
 var ()
 
 type Grid struct {
+	meta pub_object.Meta
+
 	spacing float32
 	size    int32
 
@@ -32,6 +34,7 @@ func (g *Grid) Init() error {
 	if g == nil {
 		return fmt.Errorf("Invalid grid")
 	}
+	g.meta.SetName("grid")
 
 	g.parent = nil
 	g.spacing = 1
@@ -246,6 +249,7 @@ func (g *Grid) OnRemove() {
 		return
 	}
 	g.parent = nil
+	g.meta.Release()
 }
 
 func (g *Grid) AddChild(obj pub_object.Object) {
@@ -274,3 +278,11 @@ func (g *Grid) GetParent() pub_object.Object {
 	}
 	return g.parent
 }
+
+func (g *Grid) GetMeta() *pub_object.Meta {
+	if g == nil {
+		return nil
+	}
+
+	return &g.meta
+}
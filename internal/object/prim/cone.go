@@ -15,6 +15,9 @@ var ()
 func NewCone(r, h float32, n int) (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "cone"
+	p.args = []float32{r, h, float32(n)}
+	p.meta.SetName(p.shape)
 
 	mesh := rlx.GenMeshCone(r, h, n)
 	p.mdl = rlx.LoadModelFromMesh(mesh)
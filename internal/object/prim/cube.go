@@ -17,6 +17,9 @@ var ()
 func NewCubeMdl() (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "cubemdl"
+	p.args = []float32{}
+	p.meta.SetName(p.shape)
 
 	mdl, err := res.GetRes("mdl/cube.obj")
 	if err != nil {
@@ -39,6 +42,7 @@ func NewCubeMdl() (p *Prim, err error) {
 
 	sh := app.CurApp.GetShader()
 	p.mdl.Materials.Shader = *sh.GetShader()
+	p.mdlRes = "mdl/cube.obj"
 
 	col, err := collider.NewCollider(p)
 	if err != nil {
@@ -52,6 +56,9 @@ func NewCubeMdl() (p *Prim, err error) {
 func NewCube(l, w, h float32) (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "cube"
+	p.args = []float32{l, w, h}
+	p.meta.SetName(p.shape)
 
 	mesh := rlx.GenMeshCube(l, w, h)
 	p.mdl = rlx.LoadModelFromMesh(mesh)
@@ -15,6 +15,9 @@ var ()
 func NewCylinder(r, h float32, n int) (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "cylinder"
+	p.args = []float32{r, h, float32(n)}
+	p.meta.SetName(p.shape)
 
 	mesh := rlx.GenMeshCylinder(r, h, n)
 	p.mdl = rlx.LoadModelFromMesh(mesh)
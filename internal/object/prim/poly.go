@@ -15,6 +15,9 @@ var ()
 func NewPoly(r float32, n int) (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "poly"
+	p.args = []float32{r, float32(n)}
+	p.meta.SetName(p.shape)
 
 	mesh := rlx.GenMeshPoly(n, r)
 	p.mdl = rlx.LoadModelFromMesh(mesh)
@@ -5,35 +5,59 @@ import (
 	"image/color"
 	"reflect"
 	"runtime"
-	"slices"
 	"unsafe"
 
 	"karalis/internal/collider"
 	"karalis/internal/rlx"
-	"karalis/pkg/lmath"
+	"karalis/pkg/event"
+	"karalis/pkg/logging"
+	"karalis/pkg/render"
 
 	pub_object "karalis/pkg/object"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+var (
+	logger = logging.For(logging.Res)
+)
+
 type Prim struct {
+	meta pub_object.Meta
+
 	mdl   rl.Model
 	pos   rl.Vector3
 	rot   rl.Vector3
 	scale rl.Vector3
 	color color.RGBA
 
+	shape string
+	args  []float32
+	tex   string
+	//a texture set without a resource path, which scene files can not keep
+	texRaw bool
+	//model resource the shape was loaded from
+	mdlRes string
+
 	parent  pub_object.Object
 	childs  []pub_object.Object
 	col     pub_object.Collider
 	cleaner *runtime.Cleanup
+
+	//reloads of the texture and model resources while developing
+	texSub *event.Subscription
+	mdlSub *event.Subscription
+
+	//transform at the previous tick for drawing between ticks
+	prev  pub_object.Transform
+	saved bool
 }
 
 func (p *Prim) init() error {
 	if p == nil {
 		return fmt.Errorf("Invalid prim")
 	}
+	p.meta.SetName("prim")
 
 	p.pos = rl.NewVector3(0, 0, 0)
 	p.rot = rl.NewVector3(0, 0, 0)
@@ -79,6 +103,14 @@ func (p *Prim) Render(cam pub_object.Camera) []func() {
 	return []func(){}
 }
 
+func (p *Prim) Submit(cam pub_object.Camera, q *render.Queue) {
+	if p == nil {
+		return
+	}
+
+	q.Add(render.MeshItem(*p.mdl.Meshes, *p.mdl.Materials, p.GetLerpMatrix(q.Alpha), rlx.DrawMesh))
+}
+
 func (p *Prim) Postrender(cam pub_object.Camera) []func() {
 	if p == nil {
 		return []func(){}
@@ -108,14 +140,37 @@ func (p *Prim) GetModelMatrix() rl.Matrix {
 		return rl.Matrix{}
 	}
 
-	matScale := rl.MatrixScale(p.scale.X, p.scale.Y, p.scale.Z)
-	Quat := lmath.Quat{}
-	Quat = *Quat.FromEuler(float64(p.GetPitch()), float64(p.GetYaw()), float64(p.GetRoll()))
-	matRotation := rl.QuaternionToMatrix(rl.NewQuaternion(float32(Quat.X), float32(Quat.Y), float32(Quat.Z), float32(Quat.W)))
-	matTranslation := rl.MatrixTranslate(p.pos.X, p.pos.Y, p.pos.Z)
-	matTransform := rl.MatrixMultiply(rl.MatrixMultiply(matScale, matRotation), matTranslation)
-	matTransform = rl.MatrixMultiply(p.mdl.Transform, matTransform)
-	return matTransform
+	return rl.MatrixMultiply(p.mdl.Transform, p.getTransform().Matrix())
+}
+
+// get the model matrix blended between the previous and current tick
+func (p *Prim) GetLerpMatrix(alpha float32) rl.Matrix {
+	if p == nil {
+		return rl.Matrix{}
+	}
+
+	cur := p.getTransform()
+	if !p.saved {
+		return rl.MatrixMultiply(p.mdl.Transform, cur.Matrix())
+	}
+	return rl.MatrixMultiply(p.mdl.Transform, p.prev.Lerp(cur, alpha).Matrix())
+}
+
+func (p *Prim) SaveState() {
+	if p == nil {
+		return
+	}
+
+	p.prev = p.getTransform()
+	p.saved = true
+}
+
+func (p *Prim) getTransform() pub_object.Transform {
+	return pub_object.Transform{
+		Pos:   p.pos,
+		Rot:   p.rot,
+		Scale: p.scale,
+	}
 }
 
 func (p *Prim) GetModel() *rl.Model {
@@ -283,7 +338,7 @@ func (p *Prim) SetUVs(uvs []rl.Vector2) {
 		mdluvs[i*2] = uvs[i].X
 		mdluvs[i*2+1] = uvs[i].Y
 	}
-	pub_object.UpdateModelUVs(&p.mdl)
+	rlx.UpdateModelUVs(&p.mdl)
 }
 
 func (p *Prim) GetMaterials() *rl.Material {
@@ -294,7 +349,20 @@ func (p *Prim) GetMaterials() *rl.Material {
 	return p.mdl.Materials
 }
 
+// set a texture that did not come from a resource, it is left out of scene files
 func (p *Prim) SetTexture(tex rl.Texture2D) {
+	if p == nil {
+		return
+	}
+
+	p.setTexture(tex)
+	p.texSub.Cancel()
+	p.texSub = nil
+	p.tex = ""
+	p.texRaw = true
+}
+
+func (p *Prim) setTexture(tex rl.Texture2D) {
 	rlx.SetMaterialTexture(p.mdl.Materials, rl.MapDiffuse, tex)
 }
 
@@ -319,6 +387,7 @@ func (p *Prim) OnAdd(obj pub_object.Object) {
 		return
 	}
 	p.parent = obj
+	p.watch()
 }
 
 func (p *Prim) OnRemove() {
@@ -326,6 +395,8 @@ func (p *Prim) OnRemove() {
 		return
 	}
 	p.parent = nil
+	p.unwatch()
+	p.meta.Release()
 }
 
 func (p *Prim) AddChild(obj pub_object.Object) {
@@ -345,13 +416,7 @@ func (p *Prim) GetChilds() []pub_object.Object {
 		return []pub_object.Object{}
 	}
 
-	childs := p.childs
-	grandchilds := []pub_object.Object{}
-	for _, child := range childs {
-		grandchilds = append(grandchilds, child.GetChilds()...)
-	}
-
-	return slices.Concat(grandchilds, childs)
+	return p.childs
 }
 
 func (p *Prim) GetParent() pub_object.Object {
@@ -360,3 +425,11 @@ func (p *Prim) GetParent() pub_object.Object {
 	}
 	return p.parent
 }
+
+func (p *Prim) GetMeta() *pub_object.Meta {
+	if p == nil {
+		return nil
+	}
+
+	return &p.meta
+}
@@ -0,0 +1,105 @@
+package prim
+
+import (
+	"fmt"
+	"runtime"
+
+	"karalis/internal/rlx"
+	"karalis/res"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// follow reloads of the model and texture while the prim is in a tree and a watcher runs
+// the subscriptions hold the prim, so they are made when it is added and cancelled when it is removed
+func (p *Prim) watch() {
+	if p == nil {
+		return
+	}
+
+	p.watchModel()
+	p.watchTexture()
+}
+
+func (p *Prim) unwatch() {
+	if p == nil {
+		return
+	}
+
+	p.texSub.Cancel()
+	p.texSub = nil
+	p.mdlSub.Cancel()
+	p.mdlSub = nil
+}
+
+// load the texture again when it changes on disk
+func (p *Prim) watchTexture() {
+	if p == nil {
+		return
+	}
+
+	p.texSub.Cancel()
+	p.texSub = nil
+	path := p.tex
+	if path == "" || p.parent == nil || !res.Watching() {
+		return
+	}
+	p.texSub = res.OnChange(nil, path, func() {
+		old := *p.GetTexture()
+		err := p.loadTextureRes(path)
+		if err != nil {
+			logger.Error("reload texture", "path", path, "err", err)
+			return
+		}
+		rlx.UnloadTexture(old)
+	})
+}
+
+// load the model again when it changes on disk
+// the new meshes keep the shader and texture of the old ones
+func (p *Prim) watchModel() {
+	if p == nil {
+		return
+	}
+
+	p.mdlSub.Cancel()
+	p.mdlSub = nil
+	path := p.mdlRes
+	if path == "" || p.parent == nil || !res.Watching() {
+		return
+	}
+	p.mdlSub = res.OnChange(nil, path, func() {
+		err := p.reloadModel(path)
+		if err != nil {
+			logger.Error("reload model", "path", path, "err", err)
+		}
+	})
+}
+
+func (p *Prim) reloadModel(path string) error {
+	mdl, err := res.GetRes(path)
+	if err != nil {
+		return err
+	}
+	next, ok := mdl.(rl.Model)
+	if !ok || next.MeshCount == 0 {
+		return fmt.Errorf("Invalid model object: %s", path)
+	}
+
+	old := p.mdl
+	next.Transform = old.Transform
+	if next.Materials != nil && old.Materials != nil {
+		next.Materials.Shader = old.Materials.Shader
+		rlx.SetMaterialTexture(next.Materials, rl.MapDiffuse, *p.GetTexture())
+	}
+	p.mdl = next
+	if p.cleaner != nil {
+		p.cleaner.Stop()
+	}
+	cleaner := runtime.AddCleanup(p, func(mdl rl.Model) {
+		rlx.UnloadModel(mdl)
+	}, p.mdl)
+	p.cleaner = &cleaner
+	rlx.UnloadModel(old)
+	return nil
+}
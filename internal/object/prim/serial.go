@@ -0,0 +1,156 @@
+package prim
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"karalis/internal/collider"
+	"karalis/internal/rlx"
+	"karalis/res"
+
+	pub_object "karalis/pkg/object"
+	pub_scene "karalis/pkg/scene"
+)
+
+func init() {
+	pub_scene.Register("Prim", newPrimNode)
+	pub_scene.Register("Grid", func(n pub_scene.Node) (*Grid, error) {
+		return NewGrid()
+	})
+}
+
+// rebuild a prim from the shape and mesh arguments it was created with
+func newPrimNode(n pub_scene.Node) (*Prim, error) {
+	shape, _ := n.Props["shape"].(string)
+	vals, _ := n.Props["args"].([]interface{})
+	args := []float32{}
+	for _, val := range vals {
+		if f, ok := val.(float64); ok {
+			args = append(args, float32(f))
+		}
+	}
+	arg := func(i int) float32 {
+		if i < len(args) {
+			return args[i]
+		}
+		return 1
+	}
+
+	switch shape {
+	case "cone":
+		return NewCone(arg(0), arg(1), int(arg(2)))
+	case "cubemdl":
+		return NewCubeMdl()
+	case "cube":
+		return NewCube(arg(0), arg(1), arg(2))
+	case "cylinder":
+		return NewCylinder(arg(0), arg(1), int(arg(2)))
+	case "hsphere":
+		return NewHalfSphere(arg(0), int(arg(1)), int(arg(2)))
+	case "poly":
+		return NewPoly(arg(0), int(arg(1)))
+	case "sphere":
+		return NewSphere(arg(0), int(arg(1)), int(arg(2)))
+	case "squaremdl":
+		return NewSquareMdl()
+	case "square":
+		return NewSquare(arg(0), arg(1), int(arg(2)), int(arg(3)))
+	case "torus":
+		return NewTorus(arg(0), arg(1), int(arg(2)), int(arg(3)))
+	default:
+		return nil, fmt.Errorf("Invalid prim shape: %s", shape)
+	}
+}
+
+func (p *Prim) Serialize() map[string]interface{} {
+	if p == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"shape": p.shape,
+		"args":  p.args,
+	}
+}
+
+func (p *Prim) Deserialize(props map[string]interface{}) error {
+	if p == nil {
+		return fmt.Errorf("Invalid prim")
+	}
+
+	//shape and args are consumed when the prim is built
+	return nil
+}
+
+func (p *Prim) GetTextureRes() string {
+	if p == nil {
+		return ""
+	}
+
+	if p.texRaw {
+		logger.Warn("prim texture not saved, it was not loaded from a resource", "name", p.meta.GetName(), "shape", p.shape)
+	}
+	return p.tex
+}
+
+// load a texture from a resource path onto the prim
+func (p *Prim) SetTextureRes(path string) error {
+	if p == nil {
+		return fmt.Errorf("Invalid prim")
+	}
+
+	err := p.loadTextureRes(path)
+	if err != nil {
+		return err
+	}
+	p.tex = path
+	p.texRaw = false
+	p.watchTexture()
+
+	return nil
+}
+
+func (p *Prim) loadTextureRes(path string) error {
+	data, err := res.GetRes(path)
+	if err != nil {
+		return err
+	}
+	buf, ok := data.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid texture resource: %s", path)
+	}
+
+	img := rlx.LoadImageFromMemory(filepath.Ext(path), buf, int32(len(buf)))
+	tex := rlx.LoadTextureFromImage(img)
+	rlx.UnloadImage(img)
+	p.setTexture(tex)
+
+	return nil
+}
+
+func (p *Prim) GetColliderShape() string {
+	if p == nil || p.col == nil {
+		return pub_object.ShapeNone
+	}
+
+	return p.col.GetShape()
+}
+
+func (p *Prim) SetColliderShape(shape string) error {
+	if p == nil {
+		return fmt.Errorf("Invalid prim")
+	}
+
+	if shape == pub_object.ShapeNone {
+		p.col = nil
+		return nil
+	}
+	if p.col == nil {
+		col, err := collider.NewCollider(p)
+		if err != nil {
+			return err
+		}
+		p.col = col
+	}
+	return p.col.(*collider.Collider).SetShape(shape)
+}
@@ -15,6 +15,9 @@ var ()
 func NewSphere(r float32, n, s int) (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "sphere"
+	p.args = []float32{r, float32(n), float32(s)}
+	p.meta.SetName(p.shape)
 
 	mesh := rlx.GenMeshSphere(r, n, s)
 	p.mdl = rlx.LoadModelFromMesh(mesh)
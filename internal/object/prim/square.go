@@ -17,6 +17,9 @@ var ()
 func NewSquareMdl() (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "squaremdl"
+	p.args = []float32{}
+	p.meta.SetName(p.shape)
 
 	mdl, err := res.GetRes("mdl/square.obj")
 	if err != nil {
@@ -39,6 +42,7 @@ func NewSquareMdl() (p *Prim, err error) {
 
 	sh := app.CurApp.GetShader()
 	p.mdl.Materials.Shader = *sh.GetShader()
+	p.mdlRes = "mdl/square.obj"
 
 	col, err := collider.NewCollider(p)
 	if err != nil {
@@ -52,6 +56,9 @@ func NewSquareMdl() (p *Prim, err error) {
 func NewSquare(l, w float32, dl, dw int) (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "square"
+	p.args = []float32{l, w, float32(dl), float32(dw)}
+	p.meta.SetName(p.shape)
 
 	mesh := rlx.GenMeshPlane(l, w, dl, dw)
 	p.mdl = rlx.LoadModelFromMesh(mesh)
@@ -59,7 +66,7 @@ func NewSquare(l, w float32, dl, dw int) (p *Prim, err error) {
 		p.cleaner.Stop()
 	}
 	cleaner := runtime.AddCleanup(p, func(mdl rl.Model) {
-		rl.UnloadModel(mdl)
+		rlx.UnloadModel(mdl)
 	}, p.mdl)
 	p.cleaner = &cleaner
 
@@ -15,6 +15,9 @@ var ()
 func NewTorus(r, z float32, n, s int) (p *Prim, err error) {
 	p = &Prim{}
 	p.init()
+	p.shape = "torus"
+	p.args = []float32{r, z, float32(n), float32(s)}
+	p.meta.SetName(p.shape)
 
 	mesh := rlx.GenMeshTorus(r, z, n, s)
 	p.mdl = rlx.LoadModelFromMesh(mesh)
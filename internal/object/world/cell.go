@@ -3,7 +3,8 @@ package world
 import (
 	"errors"
 	"image/color"
-	"slices"
+
+	"karalis/pkg/render"
 
 	pub_object "karalis/pkg/object"
 
@@ -15,9 +16,14 @@ var (
 )
 
 type Cell struct {
+	meta pub_object.Meta
+
 	parent  pub_object.Object
 	terrain pub_object.Object
 	childs  []pub_object.Object
+
+	//children were added or removed since the cell was generated
+	modified bool
 }
 
 func NewTerrainCell(pos, sc rl.Vector3, seed int64) (*Cell, error) {
@@ -81,6 +87,7 @@ func (c *Cell) Init() error {
 	if c == nil {
 		return errors.New("Invalid cell")
 	}
+	c.meta.SetName("cell")
 	c.parent = nil
 	c.terrain = nil
 	c.childs = []pub_object.Object{}
@@ -124,6 +131,20 @@ func (c *Cell) Render(cam pub_object.Camera) []func() {
 	return cmds
 }
 
+func (c *Cell) Submit(cam pub_object.Camera, q *render.Queue) {
+	if c == nil {
+		return
+	}
+
+	//the cell bounds are the terrain, children may reach out of it and are culled by their own
+	if q.IsVisible(c) {
+		render.Collect(q, cam, c.terrain)
+	}
+	for _, child := range c.childs {
+		render.Collect(q, cam, child)
+	}
+}
+
 func (c *Cell) Postrender(cam pub_object.Camera) []func() {
 	if c == nil {
 		return []func(){}
@@ -179,6 +200,8 @@ func (c *Cell) OnRemove() {
 		return
 	}
 	c.parent = nil
+	c.meta.Release()
+	pub_object.RemoveChilds(c)
 }
 
 func (c *Cell) AddChild(obj pub_object.Object) {
@@ -187,7 +210,9 @@ func (c *Cell) AddChild(obj pub_object.Object) {
 	}
 
 	c.childs = append(c.childs, obj)
+	c.modified = true
 	obj.OnAdd(c)
+	pub_object.Attached(c, obj)
 }
 
 func (c *Cell) RemChild(obj pub_object.Object) {
@@ -205,27 +230,51 @@ func (c *Cell) RemChild(obj pub_object.Object) {
 
 	//remove child
 	if index >= 0 {
+		pub_object.Detached(c, obj)
 		c.childs[index] = c.childs[len(c.childs)-1]
 		c.childs = c.childs[:len(c.childs)-1]
+		c.modified = true
 		obj.OnRemove()
 	}
 }
 
+// check if children were added or removed since the cell was generated
+func (c *Cell) IsModified() bool {
+	if c == nil {
+		return false
+	}
+
+	return c.modified
+}
+
 func (c *Cell) GetChilds() []pub_object.Object {
 	if c == nil {
 		return []pub_object.Object{}
 	}
 
-	childs := c.childs
-	if c.terrain != nil {
-		childs = append(c.childs, c.terrain)
-	}
-	grandchilds := []pub_object.Object{}
-	for _, child := range childs {
-		grandchilds = append(grandchilds, child.GetChilds()...)
+	childs := make([]pub_object.Object, 0, len(c.childs)+1)
+	c.EachChild(func(child pub_object.Object) bool {
+		childs = append(childs, child)
+		return true
+	})
+	return childs
+}
+
+// visit the terrain and then the added children
+func (c *Cell) EachChild(fn func(pub_object.Object) bool) bool {
+	if c == nil {
+		return true
 	}
 
-	return slices.Concat(grandchilds, childs)
+	if c.terrain != nil && !fn(c.terrain) {
+		return false
+	}
+	for _, child := range c.childs {
+		if !fn(child) {
+			return false
+		}
+	}
+	return true
 }
 
 func (c *Cell) GetCollider() pub_object.Collider {
@@ -236,6 +285,16 @@ func (c *Cell) GetCollider() pub_object.Collider {
 	return nil
 }
 
+// get the box the cell terrain spans, from its position up to its scale
+func (c *Cell) GetBounds() rl.BoundingBox {
+	if c == nil || c.terrain == nil {
+		return rl.BoundingBox{}
+	}
+
+	pos := c.terrain.GetPos()
+	return rl.NewBoundingBox(pos, rl.Vector3Add(pos, c.terrain.GetScale()))
+}
+
 func (c *Cell) GetModelMatrix() rl.Matrix {
 	if c == nil || c.terrain == nil {
 		return rl.Matrix{}
@@ -391,3 +450,11 @@ func (c *Cell) GetParent() pub_object.Object {
 	}
 	return c.parent
 }
+
+func (c *Cell) GetMeta() *pub_object.Meta {
+	if c == nil {
+		return nil
+	}
+
+	return &c.meta
+}
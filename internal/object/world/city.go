@@ -7,7 +7,6 @@ import (
 	"image/color"
 	"image/jpeg"
 	"image/png"
-	"log"
 	"math/rand"
 	"reflect"
 	"runtime"
@@ -17,6 +16,8 @@ import (
 	"karalis/internal/rlx"
 	"karalis/pkg/app"
 	"karalis/pkg/lmath"
+	"karalis/pkg/profile"
+	"karalis/pkg/render"
 	"karalis/pkg/rng"
 	"karalis/res"
 
@@ -26,6 +27,8 @@ import (
 )
 
 type City struct {
+	meta pub_object.Meta
+
 	tex *rl.Texture2D
 	mdl *rl.Model
 
@@ -78,6 +81,7 @@ func (c *City) Init() error {
 	if c == nil {
 		return fmt.Errorf("Invalid City")
 	}
+	c.meta.SetName("terrain")
 	c.parent = nil
 	c.pos = rl.NewVector3(0, 0, 0)
 	c.rot = rl.NewVector3(0, 0, 0)
@@ -183,6 +187,7 @@ func (c *City) RandMap(offx, offy, width, height int) error {
 	if c == nil {
 		return fmt.Errorf("Invalid City")
 	}
+	defer profile.Begin(profile.TrackLogic, "City.RandMap").End()
 
 	h := rng.GenerateCityTile(width, height, c.seed, float64(offx), float64(offy), 10)
 	hm := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -203,7 +208,7 @@ func (c *City) LoadMap(m string) error {
 
 	tex, err := res.GetRes(m)
 	if err != nil && strings.Compare(m, "") != 0 {
-		log.Printf("Error retrieving image (%s): %+v\n", m, err)
+		logger.Error("load map", "path", m, "err", err)
 		m = ""
 	}
 	var goimg image.Image
@@ -290,6 +295,14 @@ func (c *City) Render(cam pub_object.Camera) []func() {
 	return cmds
 }
 
+func (c *City) Submit(cam pub_object.Camera, q *render.Queue) {
+	if c == nil {
+		return
+	}
+
+	q.Add(render.MeshItem(*c.mdl.Meshes, *c.mdl.Materials, c.GetModelMatrix(), rlx.DrawMesh))
+}
+
 func (c *City) Postrender(cam pub_object.Camera) []func() {
 	cmds := []func(){}
 	if c == nil {
@@ -331,6 +344,7 @@ func (c *City) OnRemove() {
 		return
 	}
 	c.parent = nil
+	c.meta.Release()
 }
 
 func (c *City) AddChild(obj pub_object.Object) {
@@ -528,7 +542,7 @@ func (c *City) SetUVs(uvs []rl.Vector2) {
 		mdluvs[i*2] = uvs[i].X
 		mdluvs[i*2+1] = uvs[i].Y
 	}
-	pub_object.UpdateModelUVs(c.mdl)
+	rlx.UpdateModelUVs(c.mdl)
 }
 
 func (c *City) GetMaterials() *rl.Material {
@@ -589,3 +603,11 @@ func (c *City) GetParent() pub_object.Object {
 	}
 	return c.parent
 }
+
+func (c *City) GetMeta() *pub_object.Meta {
+	if c == nil {
+		return nil
+	}
+
+	return &c.meta
+}
@@ -7,7 +7,6 @@ import (
 	"image/color"
 	"image/jpeg"
 	"image/png"
-	"log"
 	"math/rand"
 	"reflect"
 	"runtime"
@@ -17,6 +16,8 @@ import (
 	"karalis/internal/rlx"
 	"karalis/pkg/app"
 	"karalis/pkg/lmath"
+	"karalis/pkg/profile"
+	"karalis/pkg/render"
 	"karalis/pkg/rng"
 	"karalis/res"
 
@@ -26,6 +27,8 @@ import (
 )
 
 type Dungeon struct {
+	meta pub_object.Meta
+
 	tex *rl.Texture2D
 	mdl *rl.Model
 
@@ -78,6 +81,7 @@ func (d *Dungeon) Init() error {
 	if d == nil {
 		return fmt.Errorf("Invalid Dungeon")
 	}
+	d.meta.SetName("terrain")
 	d.parent = nil
 	d.pos = rl.NewVector3(0, 0, 0)
 	d.rot = rl.NewVector3(0, 0, 0)
@@ -184,6 +188,7 @@ func (d *Dungeon) RandMap(offx, offy, width, height int) error {
 	if d == nil {
 		return fmt.Errorf("Invalid Dungeon")
 	}
+	defer profile.Begin(profile.TrackLogic, "Dungeon.RandMap").End()
 
 	h := rng.GenerateDungeonTile(width, height, d.seed, offx, offy)
 	hm := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -213,7 +218,7 @@ func (d *Dungeon) LoadMap(m string) error {
 
 	tex, err := res.GetRes(m)
 	if err != nil && strings.Compare(m, "") != 0 {
-		log.Printf("Error retrieving image (%s): %+v\n", m, err)
+		logger.Error("load map", "path", m, "err", err)
 		m = ""
 	}
 	var goimg image.Image
@@ -300,6 +305,14 @@ func (d *Dungeon) Render(cam pub_object.Camera) []func() {
 	return cmds
 }
 
+func (d *Dungeon) Submit(cam pub_object.Camera, q *render.Queue) {
+	if d == nil {
+		return
+	}
+
+	q.Add(render.MeshItem(*d.mdl.Meshes, *d.mdl.Materials, d.GetModelMatrix(), rlx.DrawMesh))
+}
+
 func (d *Dungeon) Postrender(cam pub_object.Camera) []func() {
 	cmds := []func(){}
 	if d == nil {
@@ -341,6 +354,7 @@ func (d *Dungeon) OnRemove() {
 		return
 	}
 	d.parent = nil
+	d.meta.Release()
 }
 
 func (d *Dungeon) AddChild(obj pub_object.Object) {
@@ -538,7 +552,7 @@ func (d *Dungeon) SetUVs(uvs []rl.Vector2) {
 		mdluvs[i*2] = uvs[i].X
 		mdluvs[i*2+1] = uvs[i].Y
 	}
-	pub_object.UpdateModelUVs(d.mdl)
+	rlx.UpdateModelUVs(d.mdl)
 }
 
 func (d *Dungeon) GetMaterials() *rl.Material {
@@ -599,3 +613,11 @@ func (d *Dungeon) GetParent() pub_object.Object {
 	}
 	return d.parent
 }
+
+func (d *Dungeon) GetMeta() *pub_object.Meta {
+	if d == nil {
+		return nil
+	}
+
+	return &d.meta
+}
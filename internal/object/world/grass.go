@@ -3,13 +3,13 @@ package world
 import (
 	"fmt"
 	"image/color"
-	"log"
 	"math"
 	"runtime"
 	"unsafe"
 
 	"karalis/internal/rlx"
 	"karalis/internal/shader"
+	"karalis/pkg/render"
 	"karalis/pkg/rng"
 	"karalis/res"
 
@@ -24,10 +24,12 @@ var (
 )
 
 type Grass struct {
+	meta pub_object.Meta
+
 	parent  pub_object.Object
 	cleaner *runtime.Cleanup
 
-	compute shader.Compute
+	compute *shader.Compute
 	shader  pub_shader.Shader
 
 	inSSBO      uint32
@@ -64,6 +66,7 @@ func (g *Grass) Init(parent *Terrain, seed uint32) error {
 	if g == nil {
 		return fmt.Errorf("Invalid grass")
 	}
+	g.meta.SetName("grass")
 	g.seed = seed
 	g.parent = parent
 
@@ -129,19 +132,19 @@ func (g *Grass) Render(cam pub_object.Camera) []func() {
 	// Upload uniforms to compute
 	err := g.compute.SetUniform("uCameraPos", cam.GetPos())
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uCameraPos", "err", err)
 	}
 	err = g.compute.SetUniform("uMaxVisible", []uint32{g.maxVisible})
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uMaxVisible", "err", err)
 	}
 	err = g.compute.SetUniform("uSeed", []uint32{g.seed})
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uSeed", "err", err)
 	}
 	err = g.compute.SetUniform("uRadius", []float32{g.radius})
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uRadius", "err", err)
 	}
 	groups := uint32((g.blades + 256 - 1) / 256)
 	rlx.ComputeShaderDispatch(groups, 1, 1)
@@ -152,7 +155,7 @@ func (g *Grass) Render(cam pub_object.Camera) []func() {
 	rlx.BindShaderBuffer(g.visibleSSBO, 1)
 	err = g.shader.SetUniform("uTime", float32(rlx.GetTime()))
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uTime", "err", err)
 	}
 
 	instances := int(visibleCount)
@@ -162,6 +165,22 @@ func (g *Grass) Render(cam pub_object.Camera) []func() {
 	return cmds
 }
 
+// grass blades are alpha blended so they are drawn with the transparent items
+func (g *Grass) Submit(cam pub_object.Camera, q *render.Queue) {
+	if g == nil || g.parent == nil {
+		return
+	}
+
+	scale := g.parent.GetScale()
+	q.Add(render.Item{
+		Draw: func() {
+			g.Render(cam)
+		},
+		Pos:         rl.Vector3Add(g.parent.GetPos(), rl.Vector3Scale(scale, 0.5)),
+		Transparent: true,
+	})
+}
+
 func (g *Grass) Postrender(cam pub_object.Camera) []func() {
 	cmds := []func(){}
 	if g == nil {
@@ -284,6 +303,7 @@ func (g *Grass) OnRemove() {
 		return
 	}
 	g.parent = nil
+	g.meta.Release()
 }
 
 func (g *Grass) AddChild(obj pub_object.Object) {
@@ -466,3 +486,11 @@ func (g *Grass) GetParent() pub_object.Object {
 	}
 	return g.parent
 }
+
+func (g *Grass) GetMeta() *pub_object.Meta {
+	if g == nil {
+		return nil
+	}
+
+	return &g.meta
+}
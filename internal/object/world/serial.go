@@ -0,0 +1,167 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+
+	pub_scene "karalis/pkg/scene"
+)
+
+func init() {
+	pub_scene.Register("World", func(n pub_scene.Node) (*World, error) {
+		kind, _ := n.Props["kind"].(string)
+		switch kind {
+		case "city":
+			return NewCityWorld()
+		case "dungeon":
+			return NewDungeonWorld()
+		case "terrain", "":
+			return NewTerrainWorld()
+		default:
+			return nil, fmt.Errorf("Invalid world kind: %s", kind)
+		}
+	})
+}
+
+func (w *World) Serialize() map[string]interface{} {
+	if w == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"kind": w.kind,
+	}
+}
+
+func (w *World) Deserialize(props map[string]interface{}) error {
+	if w == nil {
+		return fmt.Errorf("Invalid world")
+	}
+
+	//kind is consumed when the world is built
+	return nil
+}
+
+func (w *World) GetSeed() int64 {
+	if w == nil {
+		return 0
+	}
+
+	return w.seed
+}
+
+// get the kind of world, terrain, city or dungeon
+func (w *World) GetKind() string {
+	if w == nil {
+		return ""
+	}
+
+	return w.kind
+}
+
+// change the world seed, regenerating cells on the next frame
+func (w *World) SetSeed(seed int64) {
+	if w == nil {
+		return
+	}
+
+	if w.seed == seed {
+		return
+	}
+	for _, key := range w.keys {
+		w.dropCell(key)
+	}
+	w.keys = []string{}
+
+	//edits only make sense for the seed the cells were generated with
+	if w.seedEdits == nil {
+		w.seedEdits = map[int64]map[string][]pub_scene.Node{}
+	}
+	if len(w.edits) > 0 {
+		w.seedEdits[w.seed] = w.edits
+	}
+	w.edits = w.seedEdits[seed]
+	delete(w.seedEdits, seed)
+	w.seed = seed
+}
+
+// get the children of every modified cell by cell key, loaded or not
+// children of unregistered types are runtime only and are left out
+func (w *World) GetCellEdits() map[string][]pub_scene.Node {
+	if w == nil {
+		return map[string][]pub_scene.Node{}
+	}
+
+	edits := make(map[string][]pub_scene.Node, len(w.edits))
+	for key, nodes := range w.edits {
+		edits[key] = slices.Clone(nodes)
+	}
+	for _, key := range w.keys {
+		if cell := w.cells[key]; cell.IsModified() {
+			edits[key] = encodeChilds(cell)
+		}
+	}
+	return edits
+}
+
+// replace the modified cells, cells already generated are rebuilt with them
+func (w *World) SetCellEdits(edits map[string][]pub_scene.Node) {
+	if w == nil {
+		return
+	}
+
+	w.edits = make(map[string][]pub_scene.Node, len(edits))
+	for key, nodes := range edits {
+		w.edits[key] = slices.Clone(nodes)
+	}
+	for _, key := range w.keys {
+		cell := w.cells[key]
+		for _, child := range slices.Clone(cell.childs) {
+			cell.RemChild(child)
+		}
+		cell.modified = false
+		w.applyEdits(key, cell)
+	}
+}
+
+// add the saved children of a cell once it is generated
+func (w *World) applyEdits(key string, cell *Cell) {
+	nodes, ok := w.edits[key]
+	if !ok {
+		return
+	}
+
+	delete(w.edits, key)
+	for _, n := range nodes {
+		obj, err := pub_scene.Decode(n)
+		if err != nil {
+			logger.Error("restore cell child", "cell", key, "type", n.Type, "err", err)
+			continue
+		}
+		cell.AddChild(obj)
+	}
+	//a cell emptied by the player stays empty
+	cell.modified = true
+}
+
+// remember the children of a modified cell dropping out of range
+func (w *World) keepEdits(key string, cell *Cell) {
+	if !cell.IsModified() {
+		return
+	}
+
+	if w.edits == nil {
+		w.edits = map[string][]pub_scene.Node{}
+	}
+	w.edits[key] = encodeChilds(cell)
+}
+
+func encodeChilds(cell *Cell) []pub_scene.Node {
+	nodes := []pub_scene.Node{}
+	for _, child := range cell.childs {
+		if n, ok := pub_scene.Encode(child); ok {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
@@ -21,6 +21,8 @@ import (
 )
 
 type Skybox struct {
+	meta pub_object.Meta
+
 	parent  pub_object.Object
 	cleaner *runtime.Cleanup
 
@@ -46,6 +48,7 @@ func (s *Skybox) Init() error {
 	if s == nil {
 		return fmt.Errorf("Invalid skybox")
 	}
+	s.meta.SetName("sky")
 	s.parent = nil
 	s.LoadImage(nil)
 
@@ -412,6 +415,7 @@ func (s *Skybox) OnRemove() {
 		return
 	}
 	s.parent = nil
+	s.meta.Release()
 }
 
 func (s *Skybox) AddChild(obj pub_object.Object) {
@@ -440,3 +444,11 @@ func (s *Skybox) GetParent() pub_object.Object {
 	}
 	return s.parent
 }
+
+func (s *Skybox) GetMeta() *pub_object.Meta {
+	if s == nil {
+		return nil
+	}
+
+	return &s.meta
+}
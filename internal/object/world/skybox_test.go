@@ -0,0 +1,33 @@
+package world
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"karalis/internal/rlx"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// objects only draw on the null backend
+func TestMain(m *testing.M) {
+	rlx.SetHeadless(true)
+	os.Exit(m.Run())
+}
+
+func TestSkyboxRender(t *testing.T) {
+	s, err := NewSkybox(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.OnRemove()
+
+	rec := rlx.StartRecorder()
+	s.Render(nil)
+	rec.Stop()
+	err = rec.CompareGolden("testdata/skybox_render.golden", *update)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"image/color"
 	"image/jpeg"
 	"image/png"
-	"log"
 	"math/rand"
 	"reflect"
 	"runtime"
@@ -17,6 +16,8 @@ import (
 	"karalis/internal/rlx"
 	"karalis/pkg/app"
 	"karalis/pkg/lmath"
+	"karalis/pkg/profile"
+	"karalis/pkg/render"
 	"karalis/pkg/rng"
 	"karalis/res"
 
@@ -26,6 +27,8 @@ import (
 )
 
 type Terrain struct {
+	meta pub_object.Meta
+
 	tex *rl.Texture2D
 	mdl *rl.Model
 
@@ -80,6 +83,7 @@ func (t *Terrain) Init() error {
 	if t == nil {
 		return fmt.Errorf("Invalid terrain")
 	}
+	t.meta.SetName("terrain")
 	t.parent = nil
 	t.pos = rl.NewVector3(0, 0, 0)
 	t.rot = rl.NewVector3(0, 0, 0)
@@ -197,6 +201,7 @@ func (t *Terrain) RandMap(offx, offy float64, width, height int) error {
 	if t == nil {
 		return fmt.Errorf("Invalid terrain")
 	}
+	defer profile.Begin(profile.TrackLogic, "Terrain.RandMap").End()
 
 	h := rng.GenerateHeightmapTiledWorldSize(width, height, t.seed, offx, offy, 10)
 	hm := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -226,7 +231,7 @@ func (t *Terrain) LoadMap(m string) error {
 
 	tex, err := res.GetRes(m)
 	if err != nil && strings.Compare(m, "") != 0 {
-		log.Printf("Error retrieving image (%s): %+v\n", m, err)
+		logger.Error("load map", "path", m, "err", err)
 		m = ""
 	}
 	var goimg image.Image
@@ -322,6 +327,16 @@ func (t *Terrain) Render(cam pub_object.Camera) []func() {
 	return cmds
 }
 
+func (t *Terrain) Submit(cam pub_object.Camera, q *render.Queue) {
+	if t == nil {
+		return
+	}
+
+	q.Add(render.MeshItem(*t.mdl.Meshes, *t.mdl.Materials, t.GetModelMatrix(), rlx.DrawMesh))
+	t.grs.Submit(cam, q)
+	t.wtr.Submit(cam, q)
+}
+
 func (t *Terrain) Postrender(cam pub_object.Camera) []func() {
 	cmds := []func(){}
 	if t == nil {
@@ -367,6 +382,9 @@ func (t *Terrain) OnRemove() {
 		return
 	}
 	t.parent = nil
+	t.meta.Release()
+	t.grs.OnRemove()
+	t.wtr.OnRemove()
 }
 
 func (t *Terrain) AddChild(obj pub_object.Object) {
@@ -582,7 +600,7 @@ func (t *Terrain) SetUVs(uvs []rl.Vector2) {
 		mdluvs[i*2] = uvs[i].X
 		mdluvs[i*2+1] = uvs[i].Y
 	}
-	pub_object.UpdateModelUVs(t.mdl)
+	rlx.UpdateModelUVs(t.mdl)
 }
 
 func (t *Terrain) GetMaterials() *rl.Material {
@@ -643,3 +661,11 @@ func (t *Terrain) GetParent() pub_object.Object {
 	}
 	return t.parent
 }
+
+func (t *Terrain) GetMeta() *pub_object.Meta {
+	if t == nil {
+		return nil
+	}
+
+	return &t.meta
+}
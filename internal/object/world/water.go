@@ -3,15 +3,16 @@ package world
 import (
 	"fmt"
 	"image/color"
-	"log"
 	"reflect"
 	"runtime"
 	"unsafe"
 
 	"karalis/internal/rlx"
 	"karalis/internal/shader"
+	"karalis/internal/target"
 	"karalis/pkg/app"
 	"karalis/pkg/lmath"
+	"karalis/pkg/render"
 	"karalis/pkg/rng"
 
 	pub_object "karalis/pkg/object"
@@ -22,11 +23,16 @@ import (
 
 var (
 	WaterDetail  = float32(2.0)
-	UnderWaterRT = rl.LoadRenderTexture(int32(rl.GetRenderWidth()), int32(rl.GetRenderHeight()))
+	UnderWaterRT *target.Target
 	UnderWaterSh = &shader.Shader{}
+
+	//waters holding UnderWaterRT, the last one to be removed releases it
+	underwaterHolds int
 )
 
 type Water struct {
+	meta pub_object.Meta
+
 	parent  pub_object.Object
 	cleaner *runtime.Cleanup
 
@@ -35,6 +41,8 @@ type Water struct {
 	volume pub_shader.Shader
 
 	resize bool
+	//whether the water holds the shared underwater target
+	underwater bool
 
 	waterColor rl.Vector4
 	waveAmp    float32
@@ -67,6 +75,7 @@ func (w *Water) Init() error {
 	if w == nil {
 		return fmt.Errorf("Invalid Water")
 	}
+	w.meta.SetName("water")
 
 	siz := w.GetScale()
 	w.waterColor = rl.NewVector4(0.05, 0.18, 0.25, 0.75)
@@ -128,47 +137,47 @@ func (w *Water) DrawWater(cam pub_object.Camera, time float32) []func() {
 	//update water shader uniforms
 	err := w.shader.SetUniform("uCameraPos", cam.GetPos())
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uCameraPos", "err", err)
 	}
 	err = w.shader.SetUniform("uTime", time)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uTime", "err", err)
 	}
 	err = w.shader.SetUniform("uTexSize", float32(TerrainDetail))
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uTexSize", "err", err)
 	}
 	err = w.shader.SetUniform("uWaterColor", w.waterColor)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaterColor", "err", err)
 	}
 	err = w.shader.SetUniform("uWaveSpeed", w.waveSpeed)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaveSpeed", "err", err)
 	}
 	err = w.shader.SetUniform("uFresnelPower", w.fresPow)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uFresnelPower", "err", err)
 	}
 	err = w.shader.SetUniform("uSpecPower", w.specPow)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uSpecPower", "err", err)
 	}
 	err = w.shader.SetUniform("uDetailStrength", w.detailStr)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uDetailStrength", "err", err)
 	}
 	err = w.shader.SetUniform("uWaterHeight", w.depth+float32(rng.SandBand))
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaterHeight", "err", err)
 	}
 	err = w.shader.SetUniform("uWaveAmp", w.waveAmp)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaveAmp", "err", err)
 	}
 	err = w.shader.SetUniform("uWaveFreq", w.waveFreq)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaveFreq", "err", err)
 	}
 
 	//render water
@@ -196,33 +205,33 @@ func (w *Water) DrawUnderwater(cam pub_object.Camera, time float32) []func() {
 		return []func(){}
 	}
 	//render to texture so we can sample for underwater effect
-	rlx.BeginTextureMode(UnderWaterRT)
+	rlx.BeginTextureMode(UnderWaterRT.RenderTexture())
 	cmds := cam.Render()
 
 	//update water shader uniforms
 	err := w.volume.SetUniform("uTime", time)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uTime", "err", err)
 	}
 	err = w.volume.SetUniform("uTexSize", float32(TerrainDetail))
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uTexSize", "err", err)
 	}
 	err = w.volume.SetUniform("uWaveSpeed", w.waveSpeed)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaveSpeed", "err", err)
 	}
 	err = w.volume.SetUniform("uWaterHeight", w.depth+float32(rng.SandBand))
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaterHeight", "err", err)
 	}
 	err = w.volume.SetUniform("uWaveAmp", w.waveAmp)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaveAmp", "err", err)
 	}
 	err = w.volume.SetUniform("uWaveFreq", w.waveFreq)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("set uniform", "name", "uWaveFreq", "err", err)
 	}
 
 	//render water volume
@@ -251,7 +260,7 @@ func DrawUnderwater(world *World) []func() {
 	if UnderWaterSh.GetShader() == nil {
 		shd, err := shader.NewShader("underwater")
 		if err != nil {
-			log.Printf("%+v\n", err)
+			logger.Error("load shader", "name", "underwater", "err", err)
 		}
 		UnderWaterSh = shd.(*shader.Shader)
 	}
@@ -261,10 +270,15 @@ func DrawUnderwater(world *World) []func() {
 		cmds = append(cmds, func() {
 			//find current water cell
 			cpos := rl.Vector2{float32(lmath.Round(world.GetPos().X / CellScale.X)), float32(lmath.Round(world.GetPos().Z / CellScale.Z))}
-			spos := fmt.Sprintf("%d %d", cpos.X, cpos.Y)
+			spos := fmt.Sprintf("%d %d", int(cpos.X), int(cpos.Y))
+			//only terrain cells have water
 			var water *Water
 			if cell, ok := world.cells[spos]; ok {
-				water = cell.terrain.(*Terrain).wtr
+				ter, ok := cell.terrain.(*Terrain)
+				if !ok {
+					return
+				}
+				water = ter.wtr
 			} else {
 				return
 			}
@@ -272,38 +286,61 @@ func DrawUnderwater(world *World) []func() {
 			//draw underwater texture
 			err := UnderWaterSh.SetUniform("uWaterColor", water.waterColor)
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set uniform", "name", "uWaterColor", "err", err)
 			}
 			err = UnderWaterSh.SetUniform("uStrength", 1.0)
 			if err != nil {
-				log.Printf("%+v\n", err)
+				logger.Error("set uniform", "name", "uStrength", "err", err)
 			}
 			UnderWaterSh.Begin()
+			tex := UnderWaterRT.Texture()
 			rlx.DrawTexturePro(
-				UnderWaterRT.Texture,
-				rl.Rectangle{0, 0, float32(UnderWaterRT.Texture.Width), -float32(UnderWaterRT.Texture.Height)},
-				rl.Rectangle{0, 0, float32(UnderWaterRT.Texture.Width), float32(UnderWaterRT.Texture.Height)},
+				tex,
+				rl.Rectangle{0, 0, float32(tex.Width), -float32(tex.Height)},
+				rl.Rectangle{0, 0, float32(tex.Width), float32(tex.Height)},
 				rl.Vector2{0, 0}, 0.0, rl.RayWhite)
 			UnderWaterSh.End()
 		})
 	}
 
-	//update render texture
-	width := int32(rlx.GetRenderWidth())
-	height := int32(rlx.GetRenderHeight())
-	if UnderWaterRT.Texture.Width != width || UnderWaterRT.Texture.Height != height {
-		rlx.UnloadRenderTexture(UnderWaterRT)
-		UnderWaterRT = rlx.LoadRenderTexture(width, height)
-	}
 	return cmds
 }
 
+// hold the underwater target shared by every water, created by the first and resized by the target manager
+func (w *Water) holdUnderwater() {
+	if w.underwater {
+		return
+	}
+	rt, err := target.Get("underwater", target.Options{})
+	if err != nil {
+		logger.Error("underwater target", "err", err)
+		return
+	}
+	UnderWaterRT = rt
+	w.underwater = true
+	underwaterHolds++
+}
+
+// give back the waters hold on the underwater target, it is unloaded once no water holds it
+func (w *Water) releaseUnderwater() {
+	if !w.underwater {
+		return
+	}
+	UnderWaterRT.Release()
+	w.underwater = false
+	underwaterHolds--
+	if underwaterHolds == 0 {
+		UnderWaterRT = nil
+	}
+}
+
 func (w *Water) Prerender(cam pub_object.Camera) []func() {
 	if w == nil {
 		return []func(){}
 	}
 
-	rlx.BeginTextureMode(UnderWaterRT)
+	w.holdUnderwater()
+	rlx.BeginTextureMode(UnderWaterRT.RenderTexture())
 	rlx.ClearBackground(rl.Black)
 	rlx.EndTextureMode()
 
@@ -324,6 +361,23 @@ func (w *Water) Render(cam pub_object.Camera) []func() {
 	}
 }
 
+// water is drawn after opaque geometry so it blends over what lies below
+func (w *Water) Submit(cam pub_object.Camera, q *render.Queue) {
+	if w == nil {
+		return
+	}
+
+	time := float32(rlx.GetTime())
+	scale := w.GetScale()
+	q.Add(render.Item{
+		Draw: func() {
+			w.DrawWater(cam, time)
+		},
+		Pos:         rl.Vector3Add(w.GetPos(), rl.Vector3Scale(scale, 0.5)),
+		Transparent: true,
+	})
+}
+
 func (w *Water) Postrender(cam pub_object.Camera) []func() {
 	if w == nil {
 		return []func(){}
@@ -574,7 +628,7 @@ func (w *Water) SetUVs(uvs []rl.Vector2) {
 		mdluvs[i*2] = uvs[i].X
 		mdluvs[i*2+1] = uvs[i].Y
 	}
-	pub_object.UpdateModelUVs(w.sfc)
+	rlx.UpdateModelUVs(w.sfc)
 }
 
 func (w *Water) GetMaterials() *rl.Material {
@@ -617,6 +671,8 @@ func (w *Water) OnRemove() {
 		return
 	}
 	w.parent = nil
+	w.releaseUnderwater()
+	w.meta.Release()
 }
 
 func (w *Water) AddChild(obj pub_object.Object) {
@@ -645,3 +701,11 @@ func (w *Water) GetParent() pub_object.Object {
 	}
 	return w.parent
 }
+
+func (w *Water) GetMeta() *pub_object.Meta {
+	if w == nil {
+		return nil
+	}
+
+	return &w.meta
+}
@@ -4,13 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
-	"log"
 	"slices"
 
 	"karalis/pkg/app"
+	"karalis/pkg/event"
 	"karalis/pkg/lmath"
+	"karalis/pkg/logging"
+	"karalis/pkg/profile"
+	"karalis/pkg/render"
 
 	pub_object "karalis/pkg/object"
+	pub_scene "karalis/pkg/scene"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -20,15 +24,28 @@ var (
 	CellOffset = rl.Vector3{-5, 0, -5}
 	CellRender = 1
 	Seed       = int64(1234567)
+
+	logger = logging.For(logging.World)
 )
 
 type World struct {
+	meta pub_object.Meta
+
 	parent  pub_object.Object
 	childs  []pub_object.Object
 	cells   map[string]*Cell
+	keys    []string
+	cur     string
 	sky     *Skybox
 	pos     rl.Vector2
+	seed    int64
+	kind    string
 	creator func(rl.Vector3, rl.Vector3, int64) (*Cell, error)
+
+	//children of modified cells while they are not generated, by cell key
+	edits map[string][]pub_scene.Node
+	//edits of the seeds not in use, kept to switch back to
+	seedEdits map[int64]map[string][]pub_scene.Node
 }
 
 func NewTerrainWorld() (*World, error) {
@@ -37,6 +54,7 @@ func NewTerrainWorld() (*World, error) {
 	if err != nil {
 		return nil, err
 	}
+	w.kind = "terrain"
 	w.creator = NewTerrainCell
 
 	return w, nil
@@ -48,6 +66,7 @@ func NewCityWorld() (*World, error) {
 	if err != nil {
 		return nil, err
 	}
+	w.kind = "city"
 	w.creator = NewCityCell
 
 	return w, nil
@@ -59,6 +78,7 @@ func NewDungeonWorld() (*World, error) {
 	if err != nil {
 		return nil, err
 	}
+	w.kind = "dungeon"
 	w.creator = NewDungeonCell
 
 	return w, nil
@@ -68,9 +88,12 @@ func (w *World) Init() error {
 	if w == nil {
 		return errors.New("Invalid world")
 	}
+	w.meta.SetName("world")
 	w.parent = nil
 	w.childs = []pub_object.Object{}
 	w.cells = map[string]*Cell{}
+	w.keys = []string{}
+	w.seed = Seed
 
 	sky, err := NewSkybox(nil)
 	if err != nil {
@@ -86,7 +109,6 @@ func (w *World) Prerender(cam pub_object.Camera) []func() {
 	if w == nil {
 		return []func(){}
 	}
-	w.GenCells()
 
 	cmds := []func(){}
 	if w.sky != nil {
@@ -119,6 +141,23 @@ func (w *World) Render(cam pub_object.Camera) []func() {
 	return cmds
 }
 
+func (w *World) Submit(cam pub_object.Camera, q *render.Queue) {
+	if w == nil {
+		return
+	}
+
+	if w.sky != nil {
+		q.AddCmds(w.sky.Render(cam))
+	}
+	//cells cull their terrain and children on their own
+	for _, cell := range w.cells {
+		cell.Submit(cam, q)
+	}
+	for _, child := range w.childs {
+		render.Collect(q, cam, child)
+	}
+}
+
 func (w *World) Postrender(cam pub_object.Camera) []func() {
 	if w == nil {
 		return []func(){}
@@ -168,6 +207,7 @@ func (w *World) Update(dt float32) {
 		pos := ply.GetPos()
 		w.pos = rl.Vector2{pos.X, pos.Z}
 	}
+	w.GenCells()
 
 	//perform update on objects
 	if w.sky != nil {
@@ -181,10 +221,24 @@ func (w *World) Update(dt float32) {
 	}
 }
 
+// rebuild the grass of the loaded terrain cells, new cells pick up GrassLOD on their own
+func (w *World) RebuildGrass() {
+	if w == nil {
+		return
+	}
+
+	for _, cell := range w.cells {
+		if ter, ok := cell.terrain.(*Terrain); ok && ter.grs != nil {
+			ter.grs.Update(0.0)
+		}
+	}
+}
+
 func (w *World) GenCells() {
 	if w == nil {
 		return
 	}
+	defer profile.Begin(profile.TrackLogic, "World.GenCells").End()
 
 	ply := app.CurApp.GetStage().GetPlayer()
 	plypos := rl.Vector2{float32(lmath.Round(ply.GetPos().X / CellScale.X)), float32(lmath.Round(ply.GetPos().Z / CellScale.Z))}
@@ -195,30 +249,53 @@ func (w *World) GenCells() {
 		cpos := rl.Vector2{float32(lmath.Round((cell.GetPos().X + CellScale.X/2) / CellScale.X)), float32(lmath.Round((cell.GetPos().Z + CellScale.Z/2) / CellScale.Z))}
 		diff := rl.Vector2{lmath.Abs(plypos.X - cpos.X), lmath.Abs(plypos.Y - cpos.Y)}
 		if diff.X > float32(CellRender) || diff.Y > float32(CellRender) {
-			spos := fmt.Sprintf("%d %d", cpos.X, cpos.Y)
+			spos := fmt.Sprintf("%d %d", int(cpos.X), int(cpos.Y))
 			remove = append(remove, spos)
 		}
 	}
 	for _, spos := range remove {
-		delete(w.cells, spos)
+		w.dropCell(spos)
 	}
+	w.keys = slices.DeleteFunc(w.keys, func(key string) bool {
+		return slices.Contains(remove, key)
+	})
 
 	//add cells as needed
 	for x := range CellRender*2 + 1 {
 		for y := range CellRender*2 + 1 {
 			cpos := rl.Vector2{float32(x-CellRender) + plypos.X, float32(y-CellRender) + plypos.Y}
-			spos := fmt.Sprintf("%d %d", cpos.X, cpos.Y)
+			spos := fmt.Sprintf("%d %d", int(cpos.X), int(cpos.Y))
 			if _, ok := w.cells[spos]; !ok {
 				offset := rl.Vector3{cpos.X*CellScale.X + CellOffset.X, CellOffset.Y, cpos.Y*CellScale.Z + CellOffset.Z}
-				cell, err := w.creator(offset, CellScale, Seed)
+				cell, err := w.creator(offset, CellScale, w.seed)
 				if err != nil {
-					log.Printf("%+v\n", err)
+					logger.Error("create cell", "cell", spos, "err", err)
 				}
+				cell.GetMeta().SetName(fmt.Sprintf("cell_%d_%d", int(cpos.X), int(cpos.Y)))
 				w.cells[spos] = cell
+				w.keys = append(w.keys, spos)
 				cell.OnAdd(w)
+				w.applyEdits(spos, cell)
+				pub_object.Attached(w, cell)
 			}
 		}
 	}
+
+	//let listeners know when the player crosses into another cell
+	spos := fmt.Sprintf("%d %d", int(plypos.X), int(plypos.Y))
+	if spos != w.cur {
+		w.cur = spos
+		event.Emit(event.CellEntered{World: w, Cell: w.cells[spos], X: int(plypos.X), Z: int(plypos.Y)})
+	}
+}
+
+// remove a generated cell, keeping its children if it was modified
+func (w *World) dropCell(key string) {
+	cell := w.cells[key]
+	w.keepEdits(key, cell)
+	pub_object.Detached(w, cell)
+	cell.OnRemove()
+	delete(w.cells, key)
 }
 
 func (w *World) OnAdd(obj pub_object.Object) {
@@ -233,6 +310,8 @@ func (w *World) OnRemove() {
 		return
 	}
 	w.parent = nil
+	w.meta.Release()
+	pub_object.RemoveChilds(w)
 }
 
 func (w *World) AddChild(obj pub_object.Object) {
@@ -242,6 +321,7 @@ func (w *World) AddChild(obj pub_object.Object) {
 
 	w.childs = append(w.childs, obj)
 	obj.OnAdd(w)
+	pub_object.Attached(w, obj)
 }
 
 func (w *World) RemChild(obj pub_object.Object) {
@@ -259,6 +339,7 @@ func (w *World) RemChild(obj pub_object.Object) {
 
 	//remove child
 	if index >= 0 {
+		pub_object.Detached(w, obj)
 		w.childs[index] = w.childs[len(w.childs)-1]
 		w.childs = w.childs[:len(w.childs)-1]
 		obj.OnRemove()
@@ -270,16 +351,34 @@ func (w *World) GetChilds() []pub_object.Object {
 		return []pub_object.Object{}
 	}
 
-	childs := append(w.childs, w.sky)
-	grandchilds := []pub_object.Object{}
-	for _, cell := range w.cells {
-		childs = append(w.childs, cell)
-	}
-	for _, child := range childs {
-		grandchilds = append(grandchilds, child.GetChilds()...)
+	childs := make([]pub_object.Object, 0, len(w.childs)+len(w.cells)+1)
+	w.EachChild(func(child pub_object.Object) bool {
+		childs = append(childs, child)
+		return true
+	})
+	return childs
+}
+
+// visit the skybox, the generated cells in creation order and then the added children
+func (w *World) EachChild(fn func(pub_object.Object) bool) bool {
+	if w == nil {
+		return true
 	}
 
-	return slices.Concat(grandchilds, childs)
+	if w.sky != nil && !fn(w.sky) {
+		return false
+	}
+	for _, key := range w.keys {
+		if !fn(w.cells[key]) {
+			return false
+		}
+	}
+	for _, child := range w.childs {
+		if !fn(child) {
+			return false
+		}
+	}
+	return true
 }
 
 func (w *World) GetCollider() pub_object.Collider {
@@ -438,3 +537,11 @@ func (w *World) GetParent() pub_object.Object {
 	}
 	return w.parent
 }
+
+func (w *World) GetMeta() *pub_object.Meta {
+	if w == nil {
+		return nil
+	}
+
+	return &w.meta
+}
@@ -0,0 +1,136 @@
+package world
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"karalis/internal/rlx"
+	"karalis/internal/scene"
+	"karalis/pkg/app"
+
+	pub_object "karalis/pkg/object"
+	pub_stage "karalis/pkg/stage"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// running app objects ask for the stage and its player
+type testApp struct {
+	app.App
+	stage *testStage
+}
+
+func (a *testApp) GetStage() pub_stage.Stage { return a.stage }
+
+type testStage struct {
+	pub_stage.Stage
+	player pub_object.Object
+}
+
+func (s *testStage) GetPlayer() pub_object.Object { return s.player }
+
+// player only giving a position
+type testPlayer struct {
+	pub_object.Object
+	pos rl.Vector3
+}
+
+func (p *testPlayer) GetPos() rl.Vector3 { return p.pos }
+
+// install a fake running app for the test with the player at the origin
+func useTestApp(t *testing.T) *testApp {
+	prev := app.CurApp
+	a := &testApp{stage: &testStage{player: &testPlayer{}}}
+	app.CurApp = a
+	t.Cleanup(func() { app.CurApp = prev })
+	return a
+}
+
+// cells without terrain, so no resources are needed
+func newTestCell(pos, sc rl.Vector3, seed int64) (*Cell, error) {
+	c := &Cell{}
+	err := c.Init()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func TestSetSeed(t *testing.T) {
+	useTestApp(t)
+	render := CellRender
+	CellRender = 1
+	defer func() { CellRender = render }()
+
+	s := &scene.Scene{}
+	err := s.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewTerrainWorld()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.creator = newTestCell
+	w.SetSeed(1)
+	s.AddChild(w)
+	w.GenCells()
+
+	all := rl.NewBoundingBox(rl.NewVector3(-1000, -1000, -1000), rl.NewVector3(1000, 1000, 1000))
+	if len(w.cells) != 9 || len(s.QueryBox(all)) != 9 {
+		t.Fatal(fmt.Sprintf("TestSetSeed expected 9 indexed cells, got %d with %d indexed", len(w.cells), len(s.QueryBox(all))))
+	}
+	released := 0
+	for _, cell := range w.cells {
+		cell.GetMeta().OnRelease(func() { released++ })
+	}
+	w.cells[w.keys[0]].modified = true
+
+	w.SetSeed(2)
+	if released != 9 || len(w.cells) != 0 || len(w.keys) != 0 {
+		t.Error(fmt.Sprintf("TestSetSeed expected 9 cells released, got %d with %d left", released, len(w.cells)))
+	}
+	if n := len(s.QueryBox(all)); n != 0 {
+		t.Error(fmt.Sprintf("TestSetSeed expected an empty index, got %d objects", n))
+	}
+	if n := len(w.GetCellEdits()); n != 0 {
+		t.Error(fmt.Sprintf("TestSetSeed expected no edits for the new seed, got %d", n))
+	}
+
+	w.SetSeed(1)
+	if n := len(w.GetCellEdits()); n != 1 {
+		t.Error(fmt.Sprintf("TestSetSeed expected the edits of the old seed back, got %d", n))
+	}
+}
+
+// camera only giving a position
+type testCam struct {
+	pub_object.Camera
+	pos rl.Vector3
+}
+
+func (c *testCam) GetPos() rl.Vector3 { return c.pos }
+
+func TestWaterRender(t *testing.T) {
+	useTestApp(t)
+	render := CellRender
+	CellRender = 1
+	defer func() { CellRender = render }()
+
+	//the player stands a cell away so the edge facing it is drawn too
+	ter := &Terrain{pos: rl.NewVector3(CellOffset.X-CellScale.X, 0, CellOffset.Z), scale: CellScale, hm: image.NewRGBA(image.Rect(0, 0, 4, 4))}
+	w, err := NewWater(ter, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.OnRemove()
+
+	rec := rlx.StartRecorder()
+	w.DrawWater(&testCam{pos: rl.NewVector3(0, 10, 0)}, 1)
+	rec.Stop()
+	err = rec.CompareGolden("testdata/water_render.golden", *update)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -3,60 +3,70 @@ package rlx
 import rl "github.com/gen2brain/raylib-go/raylib"
 
 func InitAudioDevice() {
+	logCall("InitAudioDevice")
 	Do(func() {
 		rl.InitAudioDevice()
 	})
 }
 
 func CloseAudioDevice() {
+	logCall("CloseAudioDevice")
 	Do(func() {
 		rl.CloseAudioDevice()
 	})
 }
 
 func IsAudioDeviceReady() bool {
+	logCall("IsAudioDeviceReady")
 	return Call(func() bool {
 		return rl.IsAudioDeviceReady()
 	})
 }
 
 func SetMasterVolume(volume float32) {
+	logCall("SetMasterVolume", volume)
 	Do(func() {
 		rl.SetMasterVolume(volume)
 	})
 }
 
 func GetMasterVolume() float32 {
+	logCall("GetMasterVolume")
 	return Call(func() float32 {
 		return rl.GetMasterVolume()
 	})
 }
 
 func LoadSound(fileName string) rl.Sound {
+	logCall("LoadSound", fileName)
 	return Call(func() rl.Sound {
 		return rl.LoadSound(fileName)
 	})
 }
 
 func LoadSoundFromWave(wave rl.Wave) rl.Sound {
+	logCall("LoadSoundFromWave", wave)
 	return Call(func() rl.Sound {
 		return rl.LoadSoundFromWave(wave)
 	})
 }
 
 func LoadSoundAlias(source rl.Sound) rl.Sound {
+	logCall("LoadSoundAlias", source)
 	return Call(func() rl.Sound {
 		return rl.LoadSoundAlias(source)
 	})
 }
 
 func IsSoundValid(sound rl.Sound) bool {
+	logCall("IsSoundValid", sound)
 	return Call(func() bool {
 		return rl.IsSoundValid(sound)
 	})
 }
 
 func UpdateSound(sound rl.Sound, data []byte, sampleCount int32) {
+	logCall("UpdateSound", sound, data, sampleCount)
 	buf := append([]byte(nil), data...)
 	Do(func() {
 		rl.UpdateSound(sound, buf, sampleCount)
@@ -64,66 +74,77 @@ func UpdateSound(sound rl.Sound, data []byte, sampleCount int32) {
 }
 
 func UnloadWave(wave rl.Wave) {
+	logCall("UnloadWave", wave)
 	Do(func() {
 		rl.UnloadWave(wave)
 	})
 }
 
 func UnloadSound(sound rl.Sound) {
+	logCall("UnloadSound", sound)
 	Do(func() {
 		rl.UnloadSound(sound)
 	})
 }
 
 func UnloadSoundAlias(alias rl.Sound) {
+	logCall("UnloadSoundAlias", alias)
 	Do(func() {
 		rl.UnloadSoundAlias(alias)
 	})
 }
 
 func PlaySound(sound rl.Sound) {
+	logCall("PlaySound", sound)
 	Do(func() {
 		rl.PlaySound(sound)
 	})
 }
 
 func StopSound(sound rl.Sound) {
+	logCall("StopSound", sound)
 	Do(func() {
 		rl.StopSound(sound)
 	})
 }
 
 func PauseSound(sound rl.Sound) {
+	logCall("PauseSound", sound)
 	Do(func() {
 		rl.PauseSound(sound)
 	})
 }
 
 func ResumeSound(sound rl.Sound) {
+	logCall("ResumeSound", sound)
 	Do(func() {
 		rl.ResumeSound(sound)
 	})
 }
 
 func IsSoundPlaying(sound rl.Sound) bool {
+	logCall("IsSoundPlaying", sound)
 	return Call(func() bool {
 		return rl.IsSoundPlaying(sound)
 	})
 }
 
 func SetSoundVolume(sound rl.Sound, volume float32) {
+	logCall("SetSoundVolume", sound, volume)
 	Do(func() {
 		rl.SetSoundVolume(sound, volume)
 	})
 }
 
 func SetSoundPitch(sound rl.Sound, pitch float32) {
+	logCall("SetSoundPitch", sound, pitch)
 	Do(func() {
 		rl.SetSoundPitch(sound, pitch)
 	})
 }
 
 func SetSoundPan(sound rl.Sound, pan float32) {
+	logCall("SetSoundPan", sound, pan)
 	Do(func() {
 		rl.SetSoundPan(sound, pan)
 	})
@@ -0,0 +1,230 @@
+package rlx
+
+import (
+	"image/color"
+	"sync"
+
+	"karalis/pkg/profile"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// what a recorded command calls
+type op uint8
+
+const (
+	opFunc op = iota
+	opClearBackground
+	opBeginMode3D
+	opEndMode3D
+	opBeginTextureMode
+	opEndTextureMode
+	opBeginShaderMode
+	opEndShaderMode
+	opBeginBlendMode
+	opEndBlendMode
+	opBeginScissorMode
+	opEndScissorMode
+	opDrawRectangle
+	opDrawRectangleRec
+	opDrawTexturePro
+	opDrawText
+	opDrawMesh
+	opSetUniform
+	opSetUniformMatrix
+	opSetUniformSampler
+	opSetShaderValue
+	opSetShaderValueV
+	opSetShaderValueMatrix
+	opSetShaderValueTexture
+	opEnableShader
+	opDisableShader
+	opEnableDepthMask
+	opDisableDepthMask
+	opEnableDepthTest
+	opDisableDepthTest
+	opPushMatrix
+	opPopMatrix
+	opBegin
+	opEnd
+	opColor4ub
+	opTexCoord2f
+	opVertex3f
+	opEnableTextureCubemap
+	opDisableTextureCubemap
+)
+
+// most floats a command keeps inline, larger uniform values are copied into an Async closure
+const commandFloats = 16
+
+// a call recorded off the main thread
+// arguments are kept by value in the buffer so recording a command does not allocate
+type command struct {
+	op op
+	//only for opFunc
+	fn  func()
+	i   [4]int32
+	id  uint32
+	f   [commandFloats]float32
+	col color.RGBA
+	str string
+	val any
+
+	tex      rl.Texture2D
+	target   rl.RenderTexture2D
+	shader   rl.Shader
+	camera   rl.Camera3D
+	mat      rl.Matrix
+	mesh     rl.Mesh
+	material rl.Material
+}
+
+var (
+	batchMu sync.Mutex
+	//commands recorded since the last run, in the order they were made
+	pending []command
+	//the last run buffer, kept to record the next frame into
+	spare []command
+	//command run directly on the main thread, one passed to run would move to the heap on every call
+	direct command
+)
+
+// record a command for the main thread and return, it runs directly on the main thread and is skipped when headless
+// recorded commands run in order when the main thread polls or ahead of the next blocking call
+func record(c command) {
+	if headless.Load() {
+		return
+	}
+	if isMainThread() {
+		direct = c
+		direct.run()
+		direct = command{}
+		return
+	}
+	//nothing polls after the shutdown
+	if down.Load() {
+		return
+	}
+
+	batchMu.Lock()
+	pending = append(pending, c)
+	batchMu.Unlock()
+}
+
+// run the recorded commands on the main thread
+func runBatch() {
+	batchMu.Lock()
+	cmds := pending
+	pending = spare
+	spare = nil
+	batchMu.Unlock()
+	if len(cmds) == 0 {
+		return
+	}
+
+	sp := profile.Begin(profile.TrackMain, "rlx batch")
+	profile.Count(profile.TrackMain, "rlx batch", float64(len(cmds)))
+	for i := range cmds {
+		cmds[i].run()
+		//drop references so the buffer does not keep closures and values alive
+		cmds[i] = command{}
+	}
+	sp.End()
+
+	batchMu.Lock()
+	spare = cmds[:0]
+	batchMu.Unlock()
+}
+
+// drop the recorded commands without running them
+func dropBatch() {
+	batchMu.Lock()
+	defer batchMu.Unlock()
+	clear(pending)
+	pending = pending[:0]
+}
+
+func (c *command) run() {
+	switch c.op {
+	case opFunc:
+		c.fn()
+	case opClearBackground:
+		rl.ClearBackground(c.col)
+	case opBeginMode3D:
+		rl.BeginMode3D(c.camera)
+	case opEndMode3D:
+		rl.EndMode3D()
+	case opBeginTextureMode:
+		beginTextureMode(c.target)
+	case opEndTextureMode:
+		endTextureMode()
+	case opBeginShaderMode:
+		rl.BeginShaderMode(currentShader(c.shader))
+	case opEndShaderMode:
+		rl.EndShaderMode()
+	case opBeginBlendMode:
+		rl.BeginBlendMode(rl.BlendMode(c.i[0]))
+	case opEndBlendMode:
+		rl.EndBlendMode()
+	case opBeginScissorMode:
+		rl.BeginScissorMode(c.i[0], c.i[1], c.i[2], c.i[3])
+	case opEndScissorMode:
+		rl.EndScissorMode()
+	case opDrawRectangle:
+		rl.DrawRectangle(c.i[0], c.i[1], c.i[2], c.i[3], c.col)
+	case opDrawRectangleRec:
+		rl.DrawRectangleRec(rl.NewRectangle(c.f[0], c.f[1], c.f[2], c.f[3]), c.col)
+	case opDrawTexturePro:
+		src := rl.NewRectangle(c.f[0], c.f[1], c.f[2], c.f[3])
+		dst := rl.NewRectangle(c.f[4], c.f[5], c.f[6], c.f[7])
+		rl.DrawTexturePro(c.tex, src, dst, rl.NewVector2(c.f[8], c.f[9]), c.f[10], c.col)
+	case opDrawText:
+		rl.DrawText(c.str, c.i[0], c.i[1], c.i[2], c.col)
+	case opDrawMesh:
+		rl.DrawMesh(c.mesh, currentMaterial(c.material), c.mat)
+	case opSetUniform:
+		rl.SetUniform(c.i[0], c.val, c.i[1], c.i[2])
+	case opSetUniformMatrix:
+		rl.SetUniformMatrix(c.i[0], c.mat)
+	case opSetUniformSampler:
+		rl.SetUniformSampler(c.i[0], c.id)
+	case opSetShaderValue:
+		rl.SetShaderValue(currentShader(c.shader), c.i[0], c.f[:c.i[2]], rl.ShaderUniformDataType(c.i[1]))
+	case opSetShaderValueV:
+		rl.SetShaderValueV(currentShader(c.shader), c.i[0], c.f[:c.i[2]], rl.ShaderUniformDataType(c.i[1]), c.i[3])
+	case opSetShaderValueMatrix:
+		rl.SetShaderValueMatrix(currentShader(c.shader), c.i[0], c.mat)
+	case opSetShaderValueTexture:
+		rl.SetShaderValueTexture(currentShader(c.shader), c.i[0], c.tex)
+	case opEnableShader:
+		rl.EnableShader(c.id)
+	case opDisableShader:
+		rl.DisableShader()
+	case opEnableDepthMask:
+		rl.EnableDepthMask()
+	case opDisableDepthMask:
+		rl.DisableDepthMask()
+	case opEnableDepthTest:
+		rl.EnableDepthTest()
+	case opDisableDepthTest:
+		rl.DisableDepthTest()
+	case opPushMatrix:
+		rl.PushMatrix()
+	case opPopMatrix:
+		rl.PopMatrix()
+	case opBegin:
+		rl.Begin(c.i[0])
+	case opEnd:
+		rl.End()
+	case opColor4ub:
+		rl.Color4ub(c.col.R, c.col.G, c.col.B, c.col.A)
+	case opTexCoord2f:
+		rl.TexCoord2f(c.f[0], c.f[1])
+	case opVertex3f:
+		rl.Vertex3f(c.f[0], c.f[1], c.f[2])
+	case opEnableTextureCubemap:
+		rl.EnableTextureCubemap(c.id)
+	case opDisableTextureCubemap:
+		rl.DisableTextureCubemap()
+	}
+}
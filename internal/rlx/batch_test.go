@@ -0,0 +1,173 @@
+package rlx
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// stand in for the main loop, serving requests until stop is closed
+func serveMain(stop chan struct{}) chan struct{} {
+	ready := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		EnterMainThread()
+		defer ExitMainThread()
+		close(ready)
+		for {
+			select {
+			case req := <-reqCh:
+				serve(req)
+			case <-stop:
+				Poll()
+				return
+			}
+		}
+	}()
+	<-ready
+	return exited
+}
+
+// count the recorded commands waiting for the main thread
+func recorded() int {
+	batchMu.Lock()
+	defer batchMu.Unlock()
+	return len(pending)
+}
+
+// record the typed wrappers, as a frame does
+// they are dropped rather than run, raylib needs a window the tests do not open
+func recordTyped(vals []float32) {
+	DrawRectangle(1, 2, 300, 400, rl.Red)
+	SetShaderValue(rl.Shader{ID: 3}, 4, vals, rl.ShaderUniformVec4)
+}
+
+func TestBatch(t *testing.T) {
+	stop := make(chan struct{})
+	exited := serveMain(stop)
+
+	//recorded calls do not wait and run ahead of the next blocking one
+	order := []int{}
+	for i := range 3 {
+		Async(func() { order = append(order, i) })
+	}
+	if len(order) != 0 {
+		t.Error(fmt.Sprintf("TestBatch ran recorded calls early: %v", order))
+	}
+	got := Call(func() []int { return slices.Clone(order) })
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Error(fmt.Sprintf("TestBatch expected [0 1 2] before the call, got %v", got))
+	}
+
+	//left over calls run when the main thread polls
+	Async(func() { order = append(order, 3) })
+	close(stop)
+	<-exited
+	if !slices.Equal(order, []int{0, 1, 2, 3}) {
+		t.Error(fmt.Sprintf("TestBatch expected [0 1 2 3] after the poll, got %v", order))
+	}
+	if len(pending) != 0 || cap(spare) == 0 {
+		t.Error(fmt.Sprintf("TestBatch did not keep the run buffer: %d pending, %d spare", len(pending), cap(spare)))
+	}
+
+	//calls made on the main thread run right away
+	EnterMainThread()
+	ran := false
+	Async(func() { ran = true })
+	ExitMainThread()
+	if !ran {
+		t.Error("TestBatch did not run a main thread call directly")
+	}
+}
+
+// a call waiting on the main thread each time, as every wrapper used to
+func BenchmarkDo(b *testing.B) {
+	stop := make(chan struct{})
+	exited := serveMain(stop)
+	fn := func() {}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		Do(fn)
+	}
+	b.StopTimer()
+	close(stop)
+	<-exited
+}
+
+func BenchmarkCall(b *testing.B) {
+	stop := make(chan struct{})
+	exited := serveMain(stop)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		_ = Call(func() int { return i })
+	}
+	b.StopTimer()
+	close(stop)
+	<-exited
+}
+
+// calls recorded into the buffer, flushed by one blocking call every frame of 256
+func BenchmarkRecord(b *testing.B) {
+	stop := make(chan struct{})
+	exited := serveMain(stop)
+	fn := func() {}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		Async(fn)
+		if i%256 == 255 {
+			Do(fn)
+		}
+	}
+	Do(fn)
+	b.StopTimer()
+	close(stop)
+	<-exited
+}
+
+// typed wrappers record without allocating, also while a recorder is stopped
+func TestRecordAllocs(t *testing.T) {
+	vals := []float32{1, 2, 3, 4}
+	var cases = []struct {
+		name     string
+		recorder func()
+	}{
+		{"no recorder", func() {}},
+		{"stopped recorder", func() { StartRecorder().Stop() }},
+	}
+	for _, c := range cases {
+		c.recorder()
+		recordTyped(vals)
+		if n := recorded(); n != 2 {
+			t.Fatal(fmt.Sprintf("TestRecordAllocs %s expected 2 recorded commands, got %d", c.name, n))
+		}
+		dropBatch()
+		allocs := testing.AllocsPerRun(100, func() {
+			recordTyped(vals)
+			dropBatch()
+		})
+		if allocs != 0 {
+			t.Error(fmt.Sprintf("TestRecordAllocs %s: %v allocations per frame", c.name, allocs))
+		}
+	}
+}
+
+// typed wrappers recorded as commands instead of closures, dropped every 256 as a frame would run them
+func BenchmarkRecordTyped(b *testing.B) {
+	vals := []float32{1, 2, 3, 4}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		recordTyped(vals)
+		if i%256 == 255 {
+			dropBatch()
+		}
+	}
+	b.StopTimer()
+	dropBatch()
+}
@@ -3,12 +3,18 @@ package rlx
 import rl "github.com/gen2brain/raylib-go/raylib"
 
 func GetCameraMatrix(camera rl.Camera) rl.Matrix {
-	return Call(func() rl.Matrix {
+	logCall("GetCameraMatrix", camera)
+	return CallCPU(func() rl.Matrix {
 		return rl.GetCameraMatrix(camera)
 	})
 }
 
 func GetWorldToScreen(position rl.Vector3, camera rl.Camera) rl.Vector2 {
+	logCall("GetWorldToScreen", position, camera)
+	if IsHeadless() {
+		width, height := nullGetSize()
+		return rl.GetWorldToScreenEx(position, camera, width, height)
+	}
 	return Call(func() rl.Vector2 {
 		return rl.GetWorldToScreen(position, camera)
 	})
@@ -16,13 +22,15 @@ func GetWorldToScreen(position rl.Vector3, camera rl.Camera) rl.Vector2 {
 
 // If your raylib-go version exposes these:
 func GetCameraProjectionMatrix(camera *rl.Camera, aspect float32) rl.Matrix {
-	return Call(func() rl.Matrix {
+	logCall("GetCameraProjectionMatrix", camera, aspect)
+	return CallCPU(func() rl.Matrix {
 		return rl.GetCameraProjectionMatrix(camera, aspect)
 	})
 }
 
 func GetCameraViewMatrix(camera *rl.Camera) rl.Matrix {
-	return Call(func() rl.Matrix {
+	logCall("GetCameraViewMatrix", camera)
+	return CallCPU(func() rl.Matrix {
 		return rl.GetCameraViewMatrix(camera)
 	})
 }
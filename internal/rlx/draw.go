@@ -2,119 +2,146 @@ package rlx
 
 import (
 	"image/color"
+	"sync"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+var (
+	targetMu sync.Mutex
+	targets  []rl.RenderTexture2D
+)
+
 func ClearBackground(col color.RGBA) {
-	Do(func() {
-		rl.ClearBackground(col)
-	})
+	logCall("ClearBackground", col)
+	record(command{op: opClearBackground, col: col})
 }
 
 func BeginDrawing() {
+	logCall("BeginDrawing")
 	Do(func() {
 		rl.BeginDrawing()
 	})
 }
 
 func EndDrawing() {
+	logCall("EndDrawing")
+	if IsHeadless() {
+		nullEndFrame()
+		return
+	}
 	Do(func() {
 		rl.EndDrawing()
 	})
 }
 
 func BeginMode2D(camera rl.Camera2D) {
-	Do(func() {
+	logCall("BeginMode2D", camera)
+	Async(func() {
 		rl.BeginMode2D(camera)
 	})
 }
 
 func EndMode2D() {
-	Do(func() {
+	logCall("EndMode2D")
+	Async(func() {
 		rl.EndMode2D()
 	})
 }
 
 func BeginMode3D(camera rl.Camera3D) {
-	Do(func() {
-		rl.BeginMode3D(camera)
-	})
+	logCall("BeginMode3D", camera)
+	record(command{op: opBeginMode3D, camera: camera})
 }
 
 func EndMode3D() {
-	Do(func() {
-		rl.EndMode3D()
-	})
+	logCall("EndMode3D")
+	record(command{op: opEndMode3D})
 }
 
+// texture modes nest, ending one goes back to drawing into the target active before it
 func BeginTextureMode(target rl.RenderTexture2D) {
-	Do(func() {
-		rl.BeginTextureMode(target)
-	})
+	logCall("BeginTextureMode", target)
+	record(command{op: opBeginTextureMode, target: target})
 }
 
 func EndTextureMode() {
-	Do(func() {
-		rl.EndTextureMode()
-	})
+	logCall("EndTextureMode")
+	record(command{op: opEndTextureMode})
+}
+
+func beginTextureMode(target rl.RenderTexture2D) {
+	targetMu.Lock()
+	targets = append(targets, target)
+	targetMu.Unlock()
+	rl.BeginTextureMode(target)
+}
+
+func endTextureMode() {
+	rl.EndTextureMode()
+	targetMu.Lock()
+	defer targetMu.Unlock()
+	if len(targets) > 0 {
+		targets = targets[:len(targets)-1]
+	}
+	if len(targets) > 0 {
+		rl.BeginTextureMode(targets[len(targets)-1])
+	}
 }
 
 func BeginShaderMode(shader rl.Shader) {
-	Do(func() {
-		rl.BeginShaderMode(shader)
-	})
+	logCall("BeginShaderMode", shader)
+	record(command{op: opBeginShaderMode, shader: shader})
 }
 
 func EndShaderMode() {
-	Do(func() {
-		rl.EndShaderMode()
-	})
+	logCall("EndShaderMode")
+	record(command{op: opEndShaderMode})
 }
 
 func BeginBlendMode(mode rl.BlendMode) {
-	Do(func() {
-		rl.BeginBlendMode(mode)
-	})
+	logCall("BeginBlendMode", mode)
+	record(command{op: opBeginBlendMode, i: [4]int32{int32(mode)}})
 }
 
 func EndBlendMode() {
-	Do(func() {
-		rl.EndBlendMode()
-	})
+	logCall("EndBlendMode")
+	record(command{op: opEndBlendMode})
 }
 
 func BeginScissorMode(x int32, y int32, width int32, height int32) {
-	Do(func() {
-		rl.BeginScissorMode(x, y, width, height)
-	})
+	logCall("BeginScissorMode", x, y, width, height)
+	record(command{op: opBeginScissorMode, i: [4]int32{x, y, width, height}})
 }
 
 func EndScissorMode() {
-	Do(func() {
-		rl.EndScissorMode()
-	})
+	logCall("EndScissorMode")
+	record(command{op: opEndScissorMode})
 }
 
 func BeginVrStereoMode(config rl.VrStereoConfig) {
-	Do(func() {
+	logCall("BeginVrStereoMode", config)
+	Async(func() {
 		rl.BeginVrStereoMode(config)
 	})
 }
 
 func EndVrStereoMode() {
-	Do(func() {
+	logCall("EndVrStereoMode")
+	Async(func() {
 		rl.EndVrStereoMode()
 	})
 }
 
 func LoadVrStereoConfig(device rl.VrDeviceInfo) rl.VrStereoConfig {
+	logCall("LoadVrStereoConfig", device)
 	return Call(func() rl.VrStereoConfig {
 		return rl.LoadVrStereoConfig(device)
 	})
 }
 
 func UnloadVrStereoConfig(config rl.VrStereoConfig) {
+	logCall("UnloadVrStereoConfig", config)
 	Do(func() {
 		rl.UnloadVrStereoConfig(config)
 	})
@@ -8,103 +8,127 @@ import (
 )
 
 func LoadImageFromTexture(texture rl.Texture2D) *rl.Image {
+	logCall("LoadImageFromTexture", texture)
 	return Call(func() *rl.Image {
 		return rl.LoadImageFromTexture(texture)
 	})
 }
 
 func LoadImageFromScreen() *rl.Image {
+	logCall("LoadImageFromScreen")
 	return Call(func() *rl.Image {
 		return rl.LoadImageFromScreen()
 	})
 }
 
 func LoadImage(fileName string) *rl.Image {
+	logCall("LoadImage", fileName)
 	return rl.LoadImage(fileName)
 }
 
 func LoadImageRaw(fileName string, width int32, height int32, format rl.PixelFormat, headerSize int32) *rl.Image {
+	logCall("LoadImageRaw", fileName, width, height, format, headerSize)
 	return rl.LoadImageRaw(fileName, width, height, format, headerSize)
 }
 
 func LoadImageAnim(fileName string, frames *int32) *rl.Image {
+	logCall("LoadImageAnim", fileName, frames)
 	return rl.LoadImageAnim(fileName, frames)
 }
 
 func LoadImageAnimFromMemory(fileType string, fileData []byte, dataSize int32, frames *int32) *rl.Image {
+	logCall("LoadImageAnimFromMemory", fileType, fileData, dataSize, frames)
 	buf := append([]byte(nil), fileData...)
 	return rl.LoadImageAnimFromMemory(fileType, buf, dataSize, frames)
 }
 
 func LoadImageFromMemory(fileType string, fileData []byte, dataSize int32) *rl.Image {
+	logCall("LoadImageFromMemory", fileType, fileData, dataSize)
 	buf := append([]byte(nil), fileData...)
 	return rl.LoadImageFromMemory(fileType, buf, dataSize)
 }
 
 func IsImageValid(image *rl.Image) bool {
+	logCall("IsImageValid", image)
 	return rl.IsImageValid(image)
 }
 
 func UnloadImage(image *rl.Image) {
+	logCall("UnloadImage", image)
 	rl.UnloadImage(image)
 }
 
 func ExportImage(image rl.Image, fileName string) bool {
+	logCall("ExportImage", image, fileName)
 	return rl.ExportImage(image, fileName)
 }
 
 func ExportImageToMemory(image rl.Image, fileType string) []byte {
+	logCall("ExportImageToMemory", image, fileType)
 	return rl.ExportImageToMemory(image, fileType)
 }
 
 func GenImageColor(width int, height int, col color.RGBA) *rl.Image {
+	logCall("GenImageColor", width, height, col)
 	return rl.GenImageColor(width, height, col)
 }
 
 func ImageCopy(image *rl.Image) *rl.Image {
+	logCall("ImageCopy", image)
 	return rl.ImageCopy(image)
 }
 
 func ImageFormat(image *rl.Image, newFormat rl.PixelFormat) {
+	logCall("ImageFormat", image, newFormat)
 	rl.ImageFormat(image, newFormat)
 }
 
 func ImageCrop(image *rl.Image, crop rl.Rectangle) {
+	logCall("ImageCrop", image, crop)
 	rl.ImageCrop(image, crop)
 }
 
 func ImageResize(image *rl.Image, newWidth int32, newHeight int32) {
+	logCall("ImageResize", image, newWidth, newHeight)
 	rl.ImageResize(image, newWidth, newHeight)
 }
 
 func ImageResizeNN(image *rl.Image, newWidth int32, newHeight int32) {
+	logCall("ImageResizeNN", image, newWidth, newHeight)
 	rl.ImageResizeNN(image, newWidth, newHeight)
 }
 
 func ImageFlipVertical(image *rl.Image) {
+	logCall("ImageFlipVertical", image)
 	rl.ImageFlipVertical(image)
 }
 
 func ImageFlipHorizontal(image *rl.Image) {
+	logCall("ImageFlipHorizontal", image)
 	rl.ImageFlipHorizontal(image)
 }
 
 func ImageRotate(image *rl.Image, degrees int32) {
+	logCall("ImageRotate", image, degrees)
 	rl.ImageRotate(image, degrees)
 }
 
 func ImageColorTint(image *rl.Image, col color.RGBA) {
+	logCall("ImageColorTint", image, col)
 	rl.ImageColorTint(image, col)
 }
 
 func LoadImageColors(image *rl.Image) []color.RGBA {
+	logCall("LoadImageColors", image)
 	return rl.LoadImageColors(image)
 }
 
 func UnloadImageColors(colors []color.RGBA) {
+	logCall("UnloadImageColors", colors)
 	rl.UnloadImageColors(colors)
 }
 
 func NewImageFromImage(img image.Image) *rl.Image {
+	logCall("NewImageFromImage", img.Bounds())
 	return rl.NewImageFromImage(img)
 }
@@ -3,210 +3,245 @@ package rlx
 import rl "github.com/gen2brain/raylib-go/raylib"
 
 func IsKeyPressed(key int32) bool {
+	logCall("IsKeyPressed", key)
 	return Call(func() bool {
 		return rl.IsKeyPressed(key)
 	})
 }
 
 func IsKeyPressedRepeat(key int32) bool {
+	logCall("IsKeyPressedRepeat", key)
 	return Call(func() bool {
 		return rl.IsKeyPressedRepeat(key)
 	})
 }
 
 func IsKeyDown(key int32) bool {
+	logCall("IsKeyDown", key)
 	return Call(func() bool {
 		return rl.IsKeyDown(key)
 	})
 }
 
 func IsKeyReleased(key int32) bool {
+	logCall("IsKeyReleased", key)
 	return Call(func() bool {
 		return rl.IsKeyReleased(key)
 	})
 }
 
 func IsKeyUp(key int32) bool {
+	logCall("IsKeyUp", key)
 	return Call(func() bool {
 		return rl.IsKeyUp(key)
 	})
 }
 
 func GetKeyPressed() int32 {
+	logCall("GetKeyPressed")
 	return Call(func() int32 {
 		return rl.GetKeyPressed()
 	})
 }
 
 func GetCharPressed() int32 {
+	logCall("GetCharPressed")
 	return Call(func() int32 {
 		return rl.GetCharPressed()
 	})
 }
 
 func SetExitKey(key int32) {
+	logCall("SetExitKey", key)
 	Do(func() {
 		rl.SetExitKey(key)
 	})
 }
 
 func IsMouseButtonPressed(button rl.MouseButton) bool {
+	logCall("IsMouseButtonPressed", button)
 	return Call(func() bool {
 		return rl.IsMouseButtonPressed(button)
 	})
 }
 
 func IsMouseButtonDown(button rl.MouseButton) bool {
+	logCall("IsMouseButtonDown", button)
 	return Call(func() bool {
 		return rl.IsMouseButtonDown(button)
 	})
 }
 
 func IsMouseButtonReleased(button rl.MouseButton) bool {
+	logCall("IsMouseButtonReleased", button)
 	return Call(func() bool {
 		return rl.IsMouseButtonReleased(button)
 	})
 }
 
 func IsMouseButtonUp(button rl.MouseButton) bool {
+	logCall("IsMouseButtonUp", button)
 	return Call(func() bool {
 		return rl.IsMouseButtonUp(button)
 	})
 }
 
 func GetMouseX() int32 {
+	logCall("GetMouseX")
 	return Call(func() int32 {
 		return rl.GetMouseX()
 	})
 }
 
 func GetMouseY() int32 {
+	logCall("GetMouseY")
 	return Call(func() int32 {
 		return rl.GetMouseY()
 	})
 }
 
 func GetMousePosition() rl.Vector2 {
+	logCall("GetMousePosition")
 	return Call(func() rl.Vector2 {
 		return rl.GetMousePosition()
 	})
 }
 
 func GetMouseDelta() rl.Vector2 {
+	logCall("GetMouseDelta")
 	return Call(func() rl.Vector2 {
 		return rl.GetMouseDelta()
 	})
 }
 
 func SetMousePosition(x int, y int) {
+	logCall("SetMousePosition", x, y)
 	Do(func() {
 		rl.SetMousePosition(x, y)
 	})
 }
 
 func SetMouseOffset(offsetX int, offsetY int) {
+	logCall("SetMouseOffset", offsetX, offsetY)
 	Do(func() {
 		rl.SetMouseOffset(offsetX, offsetY)
 	})
 }
 
 func SetMouseScale(scaleX float32, scaleY float32) {
+	logCall("SetMouseScale", scaleX, scaleY)
 	Do(func() {
 		rl.SetMouseScale(scaleX, scaleY)
 	})
 }
 
 func GetMouseWheelMove() float32 {
+	logCall("GetMouseWheelMove")
 	return Call(func() float32 {
 		return rl.GetMouseWheelMove()
 	})
 }
 
 func GetMouseWheelMoveV() rl.Vector2 {
+	logCall("GetMouseWheelMoveV")
 	return Call(func() rl.Vector2 {
 		return rl.GetMouseWheelMoveV()
 	})
 }
 
 func SetMouseCursor(cursor int32) {
+	logCall("SetMouseCursor", cursor)
 	Do(func() {
 		rl.SetMouseCursor(cursor)
 	})
 }
 
 func GetTouchX() int32 {
+	logCall("GetTouchX")
 	return Call(func() int32 {
 		return rl.GetTouchX()
 	})
 }
 
 func GetTouchY() int32 {
+	logCall("GetTouchY")
 	return Call(func() int32 {
 		return rl.GetTouchY()
 	})
 }
 
 func GetTouchPosition(index int32) rl.Vector2 {
+	logCall("GetTouchPosition", index)
 	return Call(func() rl.Vector2 {
 		return rl.GetTouchPosition(index)
 	})
 }
 
 func GetTouchPointId(index int32) int32 {
+	logCall("GetTouchPointId", index)
 	return Call(func() int32 {
 		return rl.GetTouchPointId(index)
 	})
 }
 
 func GetTouchPointCount() int32 {
+	logCall("GetTouchPointCount")
 	return Call(func() int32 {
 		return rl.GetTouchPointCount()
 	})
 }
 
 func SetGesturesEnabled(flags uint32) {
+	logCall("SetGesturesEnabled", flags)
 	Do(func() {
 		rl.SetGesturesEnabled(flags)
 	})
 }
 
 func IsGestureDetected(gesture rl.Gestures) bool {
+	logCall("IsGestureDetected", gesture)
 	return Call(func() bool {
 		return rl.IsGestureDetected(gesture)
 	})
 }
 
 func GetGestureDetected() rl.Gestures {
+	logCall("GetGestureDetected")
 	return Call(func() rl.Gestures {
 		return rl.GetGestureDetected()
 	})
 }
 
 func GetGestureHoldDuration() float32 {
+	logCall("GetGestureHoldDuration")
 	return Call(func() float32 {
 		return rl.GetGestureHoldDuration()
 	})
 }
 
 func GetGestureDragVector() rl.Vector2 {
+	logCall("GetGestureDragVector")
 	return Call(func() rl.Vector2 {
 		return rl.GetGestureDragVector()
 	})
 }
 
 func GetGestureDragAngle() float32 {
+	logCall("GetGestureDragAngle")
 	return Call(func() float32 {
 		return rl.GetGestureDragAngle()
 	})
 }
 
 func GetGesturePinchVector() rl.Vector2 {
+	logCall("GetGesturePinchVector")
 	return Call(func() rl.Vector2 {
 		return rl.GetGesturePinchVector()
 	})
 }
 
 func GetGesturePinchAngle() float32 {
+	logCall("GetGesturePinchAngle")
 	return Call(func() float32 {
 		return rl.GetGesturePinchAngle()
 	})
@@ -2,217 +2,301 @@ package rlx
 
 import (
 	"image/color"
+	"unsafe"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// model files upload their meshes as they load, headless gets a unit cube in their place
 func LoadModel(fileName string) rl.Model {
+	logCall("LoadModel", fileName)
+	if IsHeadless() {
+		return LoadModelFromMesh(GenMeshCubeData(1, 1, 1))
+	}
 	return Call(func() rl.Model {
 		return rl.LoadModel(fileName)
 	})
 }
 
 func LoadModelFromMesh(mesh rl.Mesh) rl.Model {
-	return Call(func() rl.Model {
+	logCall("LoadModelFromMesh", mesh)
+	return CallCPU(func() rl.Model {
 		return rl.LoadModelFromMesh(mesh)
 	})
 }
 
 func IsModelValid(model rl.Model) bool {
+	logCall("IsModelValid", model)
 	return Call(func() bool {
 		return rl.IsModelValid(model)
 	})
 }
 
+// models built while headless never reach the gpu so unloading only frees their cpu data
 func UnloadModel(model rl.Model) {
-	Do(func() {
+	logCall("UnloadModel", model)
+	DoCPU(func() {
 		rl.UnloadModel(model)
 	})
 }
 
 func GetModelBoundingBox(model rl.Model) rl.BoundingBox {
-	return Call(func() rl.BoundingBox {
+	logCall("GetModelBoundingBox", model)
+	return CallCPU(func() rl.BoundingBox {
 		return rl.GetModelBoundingBox(model)
 	})
 }
 
 func DrawModel(model rl.Model, position rl.Vector3, scale float32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawModel", model, position, scale, tint)
+	Async(func() {
+		currentModel(model)
 		rl.DrawModel(model, position, scale, tint)
 	})
 }
 
 func DrawModelEx(model rl.Model, position rl.Vector3, rotationAxis rl.Vector3, rotationAngle float32, scale rl.Vector3, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawModelEx", model, position, rotationAxis, rotationAngle, scale, tint)
+	Async(func() {
+		currentModel(model)
 		rl.DrawModelEx(model, position, rotationAxis, rotationAngle, scale, tint)
 	})
 }
 
 func DrawModelWires(model rl.Model, position rl.Vector3, scale float32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawModelWires", model, position, scale, tint)
+	Async(func() {
+		currentModel(model)
 		rl.DrawModelWires(model, position, scale, tint)
 	})
 }
 
 func DrawBoundingBox(box rl.BoundingBox, col color.RGBA) {
-	Do(func() {
+	logCall("DrawBoundingBox", box, col)
+	Async(func() {
 		rl.DrawBoundingBox(box, col)
 	})
 }
 
 func UploadMesh(mesh *rl.Mesh, dynamic bool) {
+	logCall("UploadMesh", mesh, dynamic)
 	Do(func() {
 		rl.UploadMesh(mesh, dynamic)
 	})
 }
 
 func UpdateMeshBuffer(mesh rl.Mesh, index int, data []byte, offset int) {
+	logCall("UpdateMeshBuffer", mesh, index, data, offset)
 	buf := append([]byte(nil), data...)
 	Do(func() {
 		rl.UpdateMeshBuffer(mesh, index, buf, offset)
 	})
 }
 
+// upload the texture coordinates of a models first mesh after changing them on the cpu
+func UpdateModelUVs(model *rl.Model) {
+	logCall("UpdateModelUVs", model)
+	if model == nil || model.Meshes == nil || model.Meshes.Texcoords == nil {
+		return
+	}
+
+	mesh := *model.Meshes
+	uvs := unsafe.Slice((*byte)(unsafe.Pointer(mesh.Texcoords)), int(mesh.VertexCount)*2*int(unsafe.Sizeof(float32(0))))
+	UpdateMeshBuffer(mesh, 1, uvs, 0)
+}
+
 func UnloadMesh(mesh *rl.Mesh) {
-	Do(func() {
+	logCall("UnloadMesh", mesh)
+	DoCPU(func() {
 		rl.UnloadMesh(mesh)
 	})
 }
 
 func DrawMesh(mesh rl.Mesh, material rl.Material, transform rl.Matrix) {
-	Do(func() {
-		rl.DrawMesh(mesh, material, transform)
-	})
+	logCall("DrawMesh", mesh, material, transform)
+	record(command{op: opDrawMesh, mesh: mesh, material: material, mat: transform})
 }
 
 func DrawMeshInstanced(mesh rl.Mesh, material rl.Material, transforms []rl.Matrix, instances int) {
+	logCall("DrawMeshInstanced", mesh, material, transforms, instances)
 	buf := append([]rl.Matrix(nil), transforms...)
-	Do(func() {
-		rl.DrawMeshInstanced(mesh, material, buf, instances)
+	Async(func() {
+		rl.DrawMeshInstanced(mesh, currentMaterial(material), buf, instances)
 	})
 }
 
 func DrawGrid(slices int32, spacing float32) {
-	Do(func() {
+	logCall("DrawGrid", slices, spacing)
+	Async(func() {
 		rl.DrawGrid(slices, spacing)
 	})
 }
 
 func GenMeshCone(radius float32, height float32, slices int) rl.Mesh {
+	logCall("GenMeshCone", radius, height, slices)
+	if IsHeadless() {
+		return GenMeshConeData(radius, height, slices)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshCone(radius, height, slices)
 	})
 }
 
 func GenMeshConeData(radius float32, height float32, slices int) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshConeData", radius, height, slices)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshConeData(radius, height, slices)
 	})
 }
 
 func GenMeshCube(width float32, height float32, length float32) rl.Mesh {
+	logCall("GenMeshCube", width, height, length)
+	if IsHeadless() {
+		return GenMeshCubeData(width, height, length)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshCube(width, height, length)
 	})
 }
 
 func GenMeshCubeData(width float32, height float32, length float32) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshCubeData", width, height, length)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshCubeData(width, height, length)
 	})
 }
 
 func GenMeshCylinder(radius float32, height float32, slices int) rl.Mesh {
+	logCall("GenMeshCylinder", radius, height, slices)
+	if IsHeadless() {
+		return GenMeshCylinderData(radius, height, slices)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshCylinder(radius, height, slices)
 	})
 }
 
 func GenMeshCylinderData(radius float32, height float32, slices int) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshCylinderData", radius, height, slices)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshCylinderData(radius, height, slices)
 	})
 }
 
 func GenMeshHemiSphere(radius float32, rings int, slices int) rl.Mesh {
+	logCall("GenMeshHemiSphere", radius, rings, slices)
+	if IsHeadless() {
+		return GenMeshHemiSphereData(radius, rings, slices)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshHemiSphere(radius, rings, slices)
 	})
 }
 
 func GenMeshHemiSphereData(radius float32, rings int, slices int) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshHemiSphereData", radius, rings, slices)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshHemiSphereData(radius, rings, slices)
 	})
 }
 
 func GenMeshPoly(sides int, radius float32) rl.Mesh {
+	logCall("GenMeshPoly", sides, radius)
+	if IsHeadless() {
+		return GenMeshPolyData(sides, radius)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshPoly(sides, radius)
 	})
 }
 
 func GenMeshPolyData(sides int, radius float32) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshPolyData", sides, radius)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshPolyData(sides, radius)
 	})
 }
 
 func GenMeshSphere(radius float32, rings int, slices int) rl.Mesh {
+	logCall("GenMeshSphere", radius, rings, slices)
+	if IsHeadless() {
+		return GenMeshSphereData(radius, rings, slices)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshSphere(radius, rings, slices)
 	})
 }
 
 func GenMeshSphereData(radius float32, rings int, slices int) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshSphereData", radius, rings, slices)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshSphereData(radius, rings, slices)
 	})
 }
 
 func GenMeshPlane(width float32, length float32, resX int, resZ int) rl.Mesh {
+	logCall("GenMeshPlane", width, length, resX, resZ)
+	if IsHeadless() {
+		return GenMeshPlaneData(width, length, resX, resZ)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshPlane(width, length, resX, resZ)
 	})
 }
 
 func GenMeshPlaneData(width float32, length float32, resX int, resZ int) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshPlaneData", width, length, resX, resZ)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshPlaneData(width, length, resX, resZ)
 	})
 }
 
 func GenMeshPlaneEx(origin, axisU, axisV rl.Vector3, resU, resV int) rl.Mesh {
+	logCall("GenMeshPlaneEx", origin, axisU, axisV, resU, resV)
+	if IsHeadless() {
+		return GenMeshPlaneExData(origin, axisU, axisV, resU, resV)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshPlaneEx(origin, axisU, axisV, resU, resV)
 	})
 }
 
 func GenMeshPlaneExData(origin, axisU, axisV rl.Vector3, resU, resV int) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshPlaneExData", origin, axisU, axisV, resU, resV)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshPlaneExData(origin, axisU, axisV, resU, resV)
 	})
 }
 
 func GenMeshTorus(radius float32, size float32, radSeg int, sides int) rl.Mesh {
+	logCall("GenMeshTorus", radius, size, radSeg, sides)
+	if IsHeadless() {
+		return GenMeshTorusData(radius, size, radSeg, sides)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshTorus(radius, size, radSeg, sides)
 	})
 }
 
 func GenMeshTorusData(radius float32, size float32, radSeg int, sides int) rl.Mesh {
-	return Call(func() rl.Mesh {
+	logCall("GenMeshTorusData", radius, size, radSeg, sides)
+	return CallCPU(func() rl.Mesh {
 		return rl.GenMeshTorusData(radius, size, radSeg, sides)
 	})
 }
 
 func SetMaterialTexture(material *rl.Material, mapType int32, texture rl.Texture2D) {
-	Do(func() {
+	logCall("SetMaterialTexture", material, mapType, texture)
+	DoCPU(func() {
 		rl.SetMaterialTexture(material, mapType, texture)
 	})
 }
 
 func GenMeshHeightmap(heightmap rl.Image, size rl.Vector3) rl.Mesh {
+	logCall("GenMeshHeightmap", heightmap, size)
+	if IsHeadless() {
+		return nullGenMeshHeightmap(heightmap, size)
+	}
 	return Call(func() rl.Mesh {
 		return rl.GenMeshHeightmap(heightmap, size)
 	})
@@ -0,0 +1,234 @@
+package rlx
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// window and frame state kept by the null backend in place of a real window
+type nullWindow struct {
+	mu     sync.Mutex
+	width  int32
+	height int32
+	closed bool
+	fps    int32
+	start  time.Time
+	frame  time.Time
+	dt     float32
+}
+
+var (
+	null   = nullWindow{}
+	nullID atomic.Uint32
+)
+
+// hand out ids for gpu resources that are never created
+func nextNullID() uint32 {
+	return nullID.Add(1)
+}
+
+func nullInitWindow(width int32, height int32) {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	null.width = width
+	null.height = height
+	null.closed = false
+	null.start = time.Now()
+	null.frame = null.start
+}
+
+func nullCloseWindow() {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	null.closed = true
+}
+
+func nullShouldClose() bool {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	return null.closed
+}
+
+func nullSetSize(width int32, height int32) {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	null.width = width
+	null.height = height
+}
+
+func nullGetSize() (int32, int32) {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	return null.width, null.height
+}
+
+func nullSetFPS(fps int32) {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	null.fps = fps
+}
+
+func nullGetFPS() int32 {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	if null.dt <= 0 {
+		return null.fps
+	}
+	return int32(1 / null.dt)
+}
+
+func nullGetFrameTime() float32 {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	return null.dt
+}
+
+func nullGetTime() float64 {
+	null.mu.Lock()
+	defer null.mu.Unlock()
+
+	if null.start.IsZero() {
+		return 0
+	}
+	return time.Since(null.start).Seconds()
+}
+
+// end a frame, waiting out the rest of it like raylib does for the target fps
+func nullEndFrame() {
+	null.mu.Lock()
+	fps := null.fps
+	frame := null.frame
+	null.mu.Unlock()
+
+	if fps > 0 && !frame.IsZero() {
+		time.Sleep(time.Until(frame.Add(time.Second / time.Duration(fps))))
+	}
+
+	now := time.Now()
+	null.mu.Lock()
+	if !frame.IsZero() {
+		null.dt = float32(now.Sub(frame).Seconds())
+	}
+	null.frame = now
+	null.mu.Unlock()
+}
+
+func nullTexture(width int32, height int32, format rl.PixelFormat) rl.Texture2D {
+	return rl.Texture2D{
+		ID:      nextNullID(),
+		Width:   width,
+		Height:  height,
+		Mipmaps: 1,
+		Format:  format,
+	}
+}
+
+func nullRenderTexture(width int32, height int32) rl.RenderTexture2D {
+	return rl.RenderTexture2D{
+		ID:      nextNullID(),
+		Texture: nullTexture(width, height, rl.UncompressedR8g8b8a8),
+		Depth:   nullTexture(width, height, rl.UncompressedR32),
+	}
+}
+
+func nullShader() rl.Shader {
+	locs := make([]int32, rl.MaxShaderLocations)
+	for i := range locs {
+		locs[i] = -1
+	}
+	return rl.Shader{
+		ID:   nextNullID(),
+		Locs: &locs[0],
+	}
+}
+
+// build a heightmap mesh on the cpu the way raylib does, without uploading it
+// the buffers are allocated in c so the mesh unloads like any other
+func nullGenMeshHeightmap(heightmap rl.Image, size rl.Vector3) rl.Mesh {
+	mesh := rl.Mesh{}
+	mapX := int(heightmap.Width)
+	mapZ := int(heightmap.Height)
+	if mapX < 2 || mapZ < 2 {
+		return mesh
+	}
+	pixels := rl.LoadImageColors(&heightmap)
+	defer rl.UnloadImageColors(pixels)
+
+	mesh.TriangleCount = int32((mapX - 1) * (mapZ - 1) * 2)
+	mesh.VertexCount = mesh.TriangleCount * 3
+	vertices := cFloats(int(mesh.VertexCount) * 3)
+	normals := cFloats(int(mesh.VertexCount) * 3)
+	texcoords := cFloats(int(mesh.VertexCount) * 2)
+	mesh.Vertices = &vertices[0]
+	mesh.Normals = &normals[0]
+	mesh.Texcoords = &texcoords[0]
+
+	scale := rl.NewVector3(size.X/float32(mapX-1), size.Y/255, size.Z/float32(mapZ-1))
+	gray := func(x, z int) float32 {
+		c := pixels[x+z*mapX]
+		return float32(int(c.R)+int(c.G)+int(c.B)) / 3
+	}
+	u := func(x int) float32 {
+		return float32(x) / float32(mapX-1)
+	}
+	v := func(z int) float32 {
+		return float32(z) / float32(mapZ-1)
+	}
+
+	vc := 0
+	tc := 0
+	for z := 0; z < mapZ-1; z++ {
+		for x := 0; x < mapX-1; x++ {
+			//two triangles per heightmap quad
+			quad := [6][2]int{{x, z}, {x, z + 1}, {x + 1, z}, {x + 1, z}, {x, z + 1}, {x + 1, z + 1}}
+			for i, p := range quad {
+				vertices[vc+i*3] = float32(p[0]) * scale.X
+				vertices[vc+i*3+1] = gray(p[0], p[1]) * scale.Y
+				vertices[vc+i*3+2] = float32(p[1]) * scale.Z
+				texcoords[tc+i*2] = u(p[0])
+				texcoords[tc+i*2+1] = v(p[1])
+			}
+
+			//flat normals per triangle
+			for i := 0; i < 18; i += 9 {
+				a := rl.NewVector3(vertices[vc+i], vertices[vc+i+1], vertices[vc+i+2])
+				b := rl.NewVector3(vertices[vc+i+3], vertices[vc+i+4], vertices[vc+i+5])
+				c := rl.NewVector3(vertices[vc+i+6], vertices[vc+i+7], vertices[vc+i+8])
+				n := rl.Vector3Normalize(rl.Vector3CrossProduct(rl.Vector3Subtract(b, a), rl.Vector3Subtract(c, a)))
+				for j := 0; j < 9; j += 3 {
+					normals[vc+i+j] = n.X
+					normals[vc+i+j+1] = n.Y
+					normals[vc+i+j+2] = n.Z
+				}
+			}
+
+			vc += 18
+			tc += 12
+		}
+	}
+
+	return mesh
+}
+
+// allocate a zeroed float buffer owned by c
+func cFloats(n int) []float32 {
+	ptr := C.calloc(C.size_t(n), C.size_t(unsafe.Sizeof(float32(0))))
+	return unsafe.Slice((*float32)(ptr), n)
+}
@@ -0,0 +1,114 @@
+package rlx
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+	"unsafe"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestHeadlessWindow(t *testing.T) {
+	SetHeadless(true)
+	defer SetHeadless(false)
+
+	InitWindow(320, 200, "test")
+	if GetScreenWidth() != 320 || GetRenderHeight() != 200 || !IsWindowReady() || WindowShouldClose() {
+		t.Error(fmt.Sprintf("TestHeadlessWindow init: %dx%d", GetScreenWidth(), GetRenderHeight()))
+	}
+	SetWindowSize(640, 480)
+	if GetRenderWidth() != 640 || GetScreenHeight() != 480 {
+		t.Error(fmt.Sprintf("TestHeadlessWindow resize: %dx%d", GetRenderWidth(), GetScreenHeight()))
+	}
+
+	//gpu calls are skipped, cpu calls still run
+	ran := false
+	Do(func() { ran = true })
+	if ran || Call(func() int { return 1 }) != 0 {
+		t.Error("TestHeadlessWindow gpu call ran")
+	}
+	DoCPU(func() { ran = true })
+	if !ran || CallCPU(func() int { return 1 }) != 1 {
+		t.Error("TestHeadlessWindow cpu call skipped")
+	}
+
+	CloseWindow()
+	if !WindowShouldClose() {
+		t.Error("TestHeadlessWindow not closed")
+	}
+}
+
+func TestHeadlessResources(t *testing.T) {
+	SetHeadless(true)
+	defer SetHeadless(false)
+
+	img := GenImageColor(8, 4, color.RGBA{255, 255, 255, 255})
+	defer UnloadImage(img)
+
+	tex := LoadTextureFromImage(img)
+	rt := LoadRenderTexture(16, 16)
+	sh := LoadShaderFromMemory("", "", "", "", "")
+	ids := []uint32{tex.ID, rt.ID, rt.Texture.ID, sh.ID, CompileShader("", rl.ComputeShader)}
+	seen := map[uint32]bool{}
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			t.Error(fmt.Sprintf("TestHeadlessResources id %d in %v", id, ids))
+		}
+		seen[id] = true
+	}
+	if tex.Width != 8 || tex.Height != 4 || rt.Texture.Width != 16 {
+		t.Error(fmt.Sprintf("TestHeadlessResources sizes %dx%d %d", tex.Width, tex.Height, rt.Texture.Width))
+	}
+	if sh.GetLocation(rl.ShaderLocMatrixMvp) != -1 {
+		t.Error("TestHeadlessResources shader locations set")
+	}
+}
+
+func TestHeadlessHeightmap(t *testing.T) {
+	SetHeadless(true)
+	defer SetHeadless(false)
+
+	tests := []struct {
+		w, h      int
+		gray      uint8
+		size      rl.Vector3
+		triangles int32
+		top       float32
+	}{
+		{2, 2, 255, rl.NewVector3(1, 1, 1), 2, 1},
+		{4, 3, 51, rl.NewVector3(6, 10, 4), 12, 2},
+		{1, 5, 0, rl.NewVector3(1, 1, 1), 0, 0},
+	}
+	for _, tt := range tests {
+		img := GenImageColor(tt.w, tt.h, color.RGBA{tt.gray, tt.gray, tt.gray, 255})
+		mesh := GenMeshHeightmap(*img, tt.size)
+		UnloadImage(img)
+
+		if mesh.TriangleCount != tt.triangles || mesh.VertexCount != tt.triangles*3 {
+			t.Error(fmt.Sprintf("TestHeadlessHeightmap %dx%d: %d triangles %d vertices", tt.w, tt.h, mesh.TriangleCount, mesh.VertexCount))
+			continue
+		}
+		if mesh.VertexCount == 0 {
+			continue
+		}
+
+		verts := unsafe.Slice(mesh.Vertices, mesh.VertexCount*3)
+		normals := unsafe.Slice(mesh.Normals, mesh.VertexCount*3)
+		box := rl.BoundingBox{Min: rl.NewVector3(verts[0], verts[1], verts[2]), Max: rl.NewVector3(verts[0], verts[1], verts[2])}
+		for i := 0; i < len(verts); i += 3 {
+			v := rl.NewVector3(verts[i], verts[i+1], verts[i+2])
+			box.Min = rl.Vector3Min(box.Min, v)
+			box.Max = rl.Vector3Max(box.Max, v)
+			if normals[i+1] < 0.999 {
+				t.Error(fmt.Sprintf("TestHeadlessHeightmap %dx%d: flat normal %f %f %f", tt.w, tt.h, normals[i], normals[i+1], normals[i+2]))
+				break
+			}
+		}
+		want := rl.BoundingBox{Min: rl.NewVector3(0, tt.top, 0), Max: rl.NewVector3(tt.size.X, tt.top, tt.size.Z)}
+		if rl.Vector3Distance(box.Min, want.Min) > 0.001 || rl.Vector3Distance(box.Max, want.Max) > 0.001 {
+			t.Error(fmt.Sprintf("TestHeadlessHeightmap %dx%d: bounds %+v, want %+v", tt.w, tt.h, box, want))
+		}
+		UnloadMesh(&mesh)
+	}
+}
@@ -0,0 +1,368 @@
+package rlx
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// a call made through rlx, arguments are formatted so logs compare as text
+// gpu resources show as ids numbered in the order they first show in the log, e.g. tex:1 or shader:2,
+// so a log does not depend on what was loaded before it started
+type Entry struct {
+	Name string
+	Args []string
+}
+
+func (e Entry) String() string {
+	return e.Name + "(" + strings.Join(e.Args, ", ") + ")"
+}
+
+// log of the calls made through rlx while it records, in the order they were made
+// calls still go to the backend in use, with the null backend this records what a frame would draw without a gpu
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+	//ids in the log by kind and real id
+	ids map[string]map[uint32]int
+}
+
+// raw ids wrappers take, logged like the resources they name
+type (
+	texRef    uint32
+	shaderRef uint32
+	bufferRef uint32
+)
+
+// strings longer than this, such as shader code, are logged by their size
+const maxLogString = 80
+
+var (
+	recorder atomic.Pointer[Recorder]
+)
+
+// start logging calls into a new recorder, replacing the one logging before
+func StartRecorder() *Recorder {
+	r := &Recorder{}
+	recorder.Store(r)
+	return r
+}
+
+// stop logging into the recorder, what it logged stays readable
+func (r *Recorder) Stop() {
+	if r == nil {
+		return
+	}
+	recorder.CompareAndSwap(r, nil)
+}
+
+// drop what was logged so far
+func (r *Recorder) Reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+	r.ids = nil
+}
+
+func (r *Recorder) Entries() []Entry {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// get the logged calls with one of the names, all of them without names
+func (r *Recorder) Filter(names ...string) []Entry {
+	out := []Entry{}
+	for _, e := range r.Entries() {
+		if len(names) == 0 || slices.Contains(names, e.Name) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// get the names of the logged calls
+func (r *Recorder) Names() []string {
+	out := []string{}
+	for _, e := range r.Entries() {
+		out = append(out, e.Name)
+	}
+	return out
+}
+
+// get the log with one call per line, the format golden files are kept in
+func (r *Recorder) String() string {
+	sb := strings.Builder{}
+	for _, e := range r.Entries() {
+		sb.WriteString(e.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// compare the log with a golden file, with update set the file is written instead
+// the error names the first call that differs
+func (r *Recorder) CompareGolden(path string, update bool) error {
+	got := r.String()
+	if update {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(got), 0644)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("No golden file %s, run the test with -update to write it", path)
+	}
+	if err != nil {
+		return err
+	}
+	want := strings.Split(string(data), "\n")
+	lines := strings.Split(got, "\n")
+	for i := range max(len(want), len(lines)) {
+		w, g := "", ""
+		if i < len(want) {
+			w = want[i]
+		}
+		if i < len(lines) {
+			g = lines[i]
+		}
+		if w != g {
+			return fmt.Errorf("Call %d differs from %s: expected %q, got %q", i+1, path, w, g)
+		}
+	}
+	return nil
+}
+
+// log a call with its arguments while a recorder is running
+// arguments are formatted straight away so values changed after the call do not change the log
+func logCall(name string, args ...any) {
+	r := recorder.Load()
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := Entry{Name: name, Args: make([]string, len(args))}
+	for i, arg := range args {
+		e.Args[i] = r.format(arg)
+	}
+	r.entries = append(r.entries, e)
+}
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', 5, 32)
+}
+
+func formatFloats(fs ...float32) string {
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		out[i] = formatFloat(f)
+	}
+	return "(" + strings.Join(out, " ") + ")"
+}
+
+// number an id in the log, 0 stays as it is no resource
+func (r *Recorder) formatID(kind string, id uint32) string {
+	if id == 0 {
+		return kind + ":0"
+	}
+	if r.ids == nil {
+		r.ids = map[string]map[uint32]int{}
+	}
+	if r.ids[kind] == nil {
+		r.ids[kind] = map[uint32]int{}
+	}
+	n, ok := r.ids[kind][id]
+	if !ok {
+		n = len(r.ids[kind]) + 1
+		r.ids[kind][id] = n
+	}
+	return kind + ":" + strconv.Itoa(n)
+}
+
+func (r *Recorder) formatMaterial(mat rl.Material) string {
+	out := "material(" + r.formatID("shader", mat.Shader.ID)
+	if mat.Maps != nil {
+		out += " " + r.formatID("tex", mat.Maps.Texture.ID)
+	}
+	return out + ")"
+}
+
+func formatInts[T int32 | uint32](vs []T) string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = strconv.FormatInt(int64(v), 10)
+	}
+	return "[" + strings.Join(out, " ") + "]"
+}
+
+func formatImage(width int, height int) string {
+	return "image(" + strconv.Itoa(width) + "x" + strconv.Itoa(height) + ")"
+}
+
+func formatCount(n int, kind string) string {
+	return "[" + strconv.Itoa(n) + " " + kind + "]"
+}
+
+// format an argument without reflection, so arguments stay on the stack while nothing records
+func (r *Recorder) format(arg any) string {
+	switch v := arg.(type) {
+	case nil:
+		return "nil"
+	case string:
+		if len(v) > maxLogString {
+			return "string(" + strconv.Itoa(len(v)) + " bytes)"
+		}
+		return strconv.Quote(v)
+	case texRef:
+		return r.formatID("tex", uint32(v))
+	case shaderRef:
+		return r.formatID("shader", uint32(v))
+	case bufferRef:
+		return r.formatID("buffer", uint32(v))
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case float32:
+		return formatFloat(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', 5, 64)
+	case rl.BlendMode:
+		return strconv.FormatInt(int64(v), 10)
+	case rl.MouseButton:
+		return strconv.FormatInt(int64(v), 10)
+	case rl.Gestures:
+		return strconv.FormatInt(int64(v), 10)
+	case rl.PixelFormat:
+		return strconv.FormatInt(int64(v), 10)
+	case rl.ShaderUniformDataType:
+		return strconv.FormatInt(int64(v), 10)
+	case rl.TextureFilterMode:
+		return strconv.FormatInt(int64(v), 10)
+	case rl.TextureWrapMode:
+		return strconv.FormatInt(int64(v), 10)
+	case color.RGBA:
+		return fmt.Sprintf("#%02x%02x%02x%02x", v.R, v.G, v.B, v.A)
+	case rl.Vector2:
+		return formatFloats(v.X, v.Y)
+	case rl.Vector3:
+		return formatFloats(v.X, v.Y, v.Z)
+	case rl.Vector4:
+		return formatFloats(v.X, v.Y, v.Z, v.W)
+	case rl.Rectangle:
+		return formatFloats(v.X, v.Y, v.Width, v.Height)
+	case rl.BoundingBox:
+		return "box(" + formatFloats(v.Min.X, v.Min.Y, v.Min.Z) + " " + formatFloats(v.Max.X, v.Max.Y, v.Max.Z) + ")"
+	case rl.Matrix:
+		return formatFloats(v.M0, v.M4, v.M8, v.M12, v.M1, v.M5, v.M9, v.M13, v.M2, v.M6, v.M10, v.M14, v.M3, v.M7, v.M11, v.M15)
+	case rl.Camera3D:
+		return "camera(" + formatFloats(v.Position.X, v.Position.Y, v.Position.Z) + " " + formatFloats(v.Target.X, v.Target.Y, v.Target.Z) + " " + formatFloat(v.Fovy) + ")"
+	case *rl.Camera3D:
+		if v == nil {
+			return "nil"
+		}
+		return r.format(*v)
+	case rl.Camera2D:
+		return "camera2d(" + formatFloats(v.Offset.X, v.Offset.Y) + " " + formatFloats(v.Target.X, v.Target.Y) + " " + formatFloat(v.Rotation) + " " + formatFloat(v.Zoom) + ")"
+	case rl.Texture2D:
+		return r.formatID("tex", v.ID)
+	case *rl.Texture2D:
+		if v == nil {
+			return "nil"
+		}
+		return r.formatID("tex", v.ID)
+	case rl.RenderTexture2D:
+		return r.formatID("target", v.ID)
+	case rl.Shader:
+		return r.formatID("shader", v.ID)
+	case rl.Font:
+		return "font(" + r.formatID("tex", v.Texture.ID) + ")"
+	case rl.Mesh:
+		return r.formatID("mesh", v.VaoID)
+	case *rl.Mesh:
+		if v == nil {
+			return "nil"
+		}
+		return r.formatID("mesh", v.VaoID)
+	case rl.Material:
+		return r.formatMaterial(v)
+	case *rl.Material:
+		if v == nil {
+			return "nil"
+		}
+		return r.formatMaterial(*v)
+	case rl.Model:
+		return "model(" + strconv.Itoa(int(v.MeshCount)) + " meshes)"
+	case *rl.Model:
+		if v == nil {
+			return "nil"
+		}
+		return "model(" + strconv.Itoa(int(v.MeshCount)) + " meshes)"
+	case rl.Image:
+		return formatImage(int(v.Width), int(v.Height))
+	case *rl.Image:
+		if v == nil {
+			return "nil"
+		}
+		return formatImage(int(v.Width), int(v.Height))
+	case image.Rectangle:
+		return formatImage(v.Dx(), v.Dy())
+	case rl.Sound:
+		return "sound(" + strconv.FormatUint(uint64(v.FrameCount), 10) + " frames)"
+	case rl.Wave:
+		return "wave(" + strconv.FormatUint(uint64(v.FrameCount), 10) + " frames)"
+	case unsafe.Pointer, *int32:
+		return "ptr"
+	case []float32:
+		return formatFloats(v...)
+	case []int32:
+		return formatInts(v)
+	case []uint32:
+		return formatInts(v)
+	case []rl.Vector2:
+		out := make([]string, len(v))
+		for i, vec := range v {
+			out[i] = formatFloats(vec.X, vec.Y)
+		}
+		return "[" + strings.Join(out, " ") + "]"
+	case []byte:
+		return formatCount(len(v), "bytes")
+	case []color.RGBA:
+		return formatCount(len(v), "colors")
+	case []rl.Matrix:
+		return formatCount(len(v), "matrices")
+	case []rl.Image:
+		return formatCount(len(v), "images")
+	}
+	//structs only their wrappers pass, such as vr configs
+	return "value"
+}
@@ -0,0 +1,69 @@
+package rlx
+
+import (
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestRecorder(t *testing.T) {
+	SetHeadless(true)
+	defer SetHeadless(false)
+
+	rt := LoadRenderTexture(64, 32)
+	sh := LoadShaderFromMemory("", "", "", "", "")
+	rec := StartRecorder()
+	BeginTextureMode(rt)
+	ClearBackground(color.RGBA{255, 0, 16, 255})
+	SetShaderValue(sh, 2, []float32{0.5, 1.25}, rl.ShaderUniformVec2)
+	DrawTexturePro(rt.Texture, rl.NewRectangle(0, 0, 64, -32), rl.NewRectangle(0, 0, 64, 32), rl.Vector2{}, 0, rl.White)
+	EndTextureMode()
+	rec.Stop()
+	DrawText("after", 0, 0, 10, rl.White)
+
+	expect := []string{
+		"BeginTextureMode(target:1)",
+		"ClearBackground(#ff0010ff)",
+		"SetShaderValue(shader:1, 2, (0.5 1.25), 1)",
+		"DrawTexturePro(tex:1, (0 0 64 -32), (0 0 64 32), (0 0), 0, #ffffffff)",
+		"EndTextureMode()",
+	}
+	got := []string{}
+	for _, e := range rec.Entries() {
+		got = append(got, e.String())
+	}
+	if !slices.Equal(got, expect) {
+		t.Error(fmt.Sprintf("TestRecorder expected %q, got %q", expect, got))
+	}
+	if names := rec.Names(); len(rec.Filter("EndTextureMode", "ClearBackground")) != 2 || names[0] != "BeginTextureMode" {
+		t.Error(fmt.Sprintf("TestRecorder unexpected names %v", names))
+	}
+
+	//golden files are written with update and compared without
+	path := filepath.Join(t.TempDir(), "testdata", "frame.golden")
+	if rec.CompareGolden(path, false) == nil {
+		t.Error("TestRecorder compared with a missing golden file")
+	}
+	if err := rec.CompareGolden(path, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.CompareGolden(path, false); err != nil {
+		t.Error(fmt.Sprintf("TestRecorder golden file differs: %v", err))
+	}
+	rec.Reset()
+	if rec.CompareGolden(path, false) == nil || len(rec.Entries()) != 0 {
+		t.Error("TestRecorder matched a golden file after reset")
+	}
+
+	//nothing is kept or allocated for calls made while not recording
+	allocs := testing.AllocsPerRun(100, func() {
+		DrawTexturePro(rt.Texture, rl.NewRectangle(0, 0, 64, -32), rl.NewRectangle(0, 0, 64, 32), rl.Vector2{}, 0, rl.White)
+	})
+	if allocs != 0 {
+		t.Error(fmt.Sprintf("TestRecorder %v allocations per call while not recording", allocs))
+	}
+}
@@ -0,0 +1,67 @@
+package rlx
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	//shaders replaced by a recompile, keyed by the id of the old program
+	//materials keep copies of the shader they were given so draws look them up here
+	remap   atomic.Pointer[map[uint32]rl.Shader]
+	remapMu sync.Mutex
+)
+
+// send everything drawn with old to next from now on
+func ReplaceShader(old rl.Shader, next rl.Shader) {
+	if old.ID == next.ID {
+		return
+	}
+
+	remapMu.Lock()
+	defer remapMu.Unlock()
+	table := map[uint32]rl.Shader{}
+	if cur := remap.Load(); cur != nil {
+		table = maps.Clone(*cur)
+	}
+	for id, sh := range table {
+		if sh.ID == old.ID {
+			table[id] = next
+		}
+	}
+	table[old.ID] = next
+	delete(table, next.ID)
+	remap.Store(&table)
+}
+
+// get the shader that replaced sh, or sh when it is current
+func currentShader(sh rl.Shader) rl.Shader {
+	table := remap.Load()
+	if table == nil {
+		return sh
+	}
+	if next, ok := (*table)[sh.ID]; ok {
+		return next
+	}
+	return sh
+}
+
+func currentMaterial(mat rl.Material) rl.Material {
+	mat.Shader = currentShader(mat.Shader)
+	return mat
+}
+
+// point the materials of a model at the current shaders, main thread only
+func currentModel(model rl.Model) {
+	if remap.Load() == nil || model.Materials == nil {
+		return
+	}
+	mats := unsafe.Slice(model.Materials, model.MaterialCount)
+	for i := range mats {
+		mats[i].Shader = currentShader(mats[i].Shader)
+	}
+}
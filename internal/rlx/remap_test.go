@@ -0,0 +1,39 @@
+package rlx
+
+import (
+	"fmt"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestReplaceShader(t *testing.T) {
+	defer remap.Store(nil)
+
+	a, b, c := rl.Shader{ID: 10}, rl.Shader{ID: 11}, rl.Shader{ID: 12}
+	other := rl.Shader{ID: 20}
+	tests := []struct {
+		old    rl.Shader
+		next   rl.Shader
+		expect map[uint32]uint32
+	}{
+		{a, b, map[uint32]uint32{10: 11, 11: 11, 20: 20}},
+		//a copy of a made before either reload ends up on the newest program
+		{b, c, map[uint32]uint32{10: 12, 11: 12, 12: 12, 20: 20}},
+		{c, c, map[uint32]uint32{10: 12, 11: 12, 12: 12}},
+		//going back to an older program stops it being replaced
+		{c, a, map[uint32]uint32{10: 10, 11: 10, 12: 10, 20: 20}},
+	}
+	for i, test := range tests {
+		ReplaceShader(test.old, test.next)
+		for from, to := range test.expect {
+			got := currentShader(rl.Shader{ID: from}).ID
+			if got != to {
+				t.Error(fmt.Sprintf("Unexpected shader for case %d: expected %d to map to %d, got %d", i, from, to, got))
+			}
+		}
+	}
+	if currentMaterial(rl.Material{Shader: b}).Shader.ID != a.ID || currentShader(other).ID != other.ID {
+		t.Error("Material was not pointed at the current shader")
+	}
+}
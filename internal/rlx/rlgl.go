@@ -1,93 +1,86 @@
 package rlx
 
-import rl "github.com/gen2brain/raylib-go/raylib"
+import (
+	"image/color"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
 
 func DisableDepthMask() {
-	Do(func() {
-		rl.DisableDepthMask()
-	})
+	logCall("DisableDepthMask")
+	record(command{op: opDisableDepthMask})
 }
 
 func EnableDepthMask() {
-	Do(func() {
-		rl.EnableDepthMask()
-	})
+	logCall("EnableDepthMask")
+	record(command{op: opEnableDepthMask})
 }
 
 func DisableDepthTest() {
-	Do(func() {
-		rl.DisableDepthTest()
-	})
+	logCall("DisableDepthTest")
+	record(command{op: opDisableDepthTest})
 }
 
 func EnableDepthTest() {
-	Do(func() {
-		rl.EnableDepthTest()
-	})
+	logCall("EnableDepthTest")
+	record(command{op: opEnableDepthTest})
 }
 
 func PushMatrix() {
-	Do(func() {
-		rl.PushMatrix()
-	})
+	logCall("PushMatrix")
+	record(command{op: opPushMatrix})
 }
 
 func PopMatrix() {
-	Do(func() {
-		rl.PopMatrix()
-	})
+	logCall("PopMatrix")
+	record(command{op: opPopMatrix})
 }
 
 func Begin(mode int32) {
-	Do(func() {
-		rl.Begin(mode)
-	})
+	logCall("Begin", mode)
+	record(command{op: opBegin, i: [4]int32{mode}})
 }
 
 func End() {
-	Do(func() {
-		rl.End()
-	})
+	logCall("End")
+	record(command{op: opEnd})
 }
 
 func EnableTextureCubemap(id uint32) {
-	Do(func() {
-		rl.EnableTextureCubemap(id)
-	})
+	logCall("EnableTextureCubemap", texRef(id))
+	record(command{op: opEnableTextureCubemap, id: id})
 }
 
 func DisableTextureCubemap() {
-	Do(func() {
-		rl.DisableTextureCubemap()
-	})
+	logCall("DisableTextureCubemap")
+	record(command{op: opDisableTextureCubemap})
 }
 
 func GetMatrixModelview() rl.Matrix {
+	logCall("GetMatrixModelview")
 	return Call(func() rl.Matrix {
 		return rl.GetMatrixModelview()
 	})
 }
 
 func GetMatrixProjection() rl.Matrix {
+	logCall("GetMatrixProjection")
 	return Call(func() rl.Matrix {
 		return rl.GetMatrixProjection()
 	})
 }
 
 func Color4ub(r uint8, g uint8, b uint8, a uint8) {
-	Do(func() {
-		rl.Color4ub(r, g, b, a)
-	})
+	logCall("Color4ub", r, g, b, a)
+	record(command{op: opColor4ub, col: color.RGBA{r, g, b, a}})
 }
 
 func TexCoord2f(x float32, y float32) {
-	Do(func() {
-		rl.TexCoord2f(x, y)
-	})
+	logCall("TexCoord2f", x, y)
+	record(command{op: opTexCoord2f, f: [commandFloats]float32{x, y}})
 }
 
 func Vertex3f(x float32, y float32, z float32) {
-	Do(func() {
-		rl.Vertex3f(x, y, z)
-	})
+	logCall("Vertex3f", x, y, z)
+	record(command{op: opVertex3f, f: [commandFloats]float32{x, y, z}})
 }
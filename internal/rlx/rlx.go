@@ -1,63 +1,356 @@
 package rlx
 
-import "sync/atomic"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"karalis/pkg/profile"
+)
+
+const (
+	// requests that can wait for the main thread before senders block
+	QueueSize = 1024
+)
+
+// request states, a request runs or is given up on, never both
+const (
+	reqWaiting int32 = iota
+	reqRunning
+	reqCanceled
+)
+
+// returned for requests the main thread will no longer serve
+var ErrShutdown = errors.New("Main thread stopped serving requests")
 
 type request struct {
-	fn   func()
-	done chan struct{}
+	fn    func()
+	done  chan struct{}
+	state atomic.Int32
+	//set before done is closed
+	err error
+	//when the request was queued, zero while not profiling
+	queued time.Time
+}
+
+// state of the request queue since the main thread was entered, for spotting a main thread falling behind
+type Stats struct {
+	Depth    int
+	Peak     int
+	Capacity int
+	//requests that found the queue full and waited to be queued
+	Full   uint64
+	Served uint64
+	//requests given up on by a timeout, a cancel or the shutdown
+	Failed uint64
+	//calls recorded for the next poll
+	Recorded int
+	Stopped  bool
 }
 
 var (
-	reqCh        = make(chan request, 1024)
-	onMainThread atomic.Bool
+	reqCh = make(chan *request, QueueSize)
+	//os thread gpu calls are made on, zero until EnterMainThread
+	mainThread atomic.Uint64
+	headless   atomic.Bool
+
+	//closed by Shutdown and replaced when a main thread is entered again
+	stopMu sync.Mutex
+	stopCh = make(chan struct{})
+	down   atomic.Bool
+
+	peak   atomic.Int64
+	full   atomic.Uint64
+	served atomic.Uint64
+	failed atomic.Uint64
 )
 
+// switch to the null backend, gpu and window calls are skipped or stood in for on the cpu
+// must be set before the first call into rlx
+func SetHeadless(on bool) {
+	headless.Store(on)
+}
+
+func IsHeadless() bool {
+	return headless.Load()
+}
+
+// run a gpu call on the main thread and wait for it, skipped when headless
+// calls recorded before it run first, in the same round trip
+// the call is dropped once the main thread shut down, DoContext reports it
 func Do(fn func()) {
-	if onMainThread.Load() {
+	_ = DoContext(context.Background(), fn)
+}
+
+// run a gpu call on the main thread and wait for it until ctx ends
+// a call that has not started by then never runs and the error of ctx is returned, one that started is waited for
+// ErrShutdown is returned once the main thread stopped serving requests
+func DoContext(ctx context.Context, fn func()) error {
+	if headless.Load() {
+		return nil
+	}
+	if isMainThread() {
 		fn()
-		return
+		return nil
 	}
 
-	done := make(chan struct{})
-	reqCh <- request{fn: fn, done: done}
-	<-done
+	stop := stopped()
+	if down.Load() {
+		failed.Add(1)
+		return ErrShutdown
+	}
+	req := newRequest(fn)
+	//counted with the request itself, the main thread may take it before the queue is looked at again
+	notePeak(len(reqCh) + 1)
+	select {
+	case reqCh <- req:
+	default:
+		full.Add(1)
+		select {
+		case reqCh <- req:
+		case <-ctx.Done():
+			failed.Add(1)
+			return ctx.Err()
+		case <-stop:
+			failed.Add(1)
+			return ErrShutdown
+		}
+	}
+
+	select {
+	case <-req.done:
+		return req.err
+	case <-ctx.Done():
+		return req.abandon(ctx.Err())
+	case <-stop:
+		return req.abandon(ErrShutdown)
+	}
 }
 
+// run a gpu call on the main thread without waiting for it, skipped when headless
+// it is recorded with the frame's other calls, the closure allocates unlike the typed commands
 func Async(fn func()) {
-	if onMainThread.Load() {
-		fn()
-		return
+	record(command{op: opFunc, fn: fn})
+}
+
+func newRequest(fn func()) *request {
+	req := &request{fn: fn, done: make(chan struct{})}
+	if profile.Enabled() {
+		req.queued = time.Now()
+	}
+	return req
+}
+
+// give up on a request that has not started, one that started is waited for
+func (req *request) abandon(err error) error {
+	if req.state.CompareAndSwap(reqWaiting, reqCanceled) {
+		failed.Add(1)
+		return err
 	}
+	<-req.done
+	return req.err
+}
 
-	reqCh <- request{fn: fn}
+func notePeak(depth int) {
+	for {
+		p := peak.Load()
+		if int64(depth) <= p || peak.CompareAndSwap(p, int64(depth)) {
+			return
+		}
+	}
 }
 
+// run a gpu call on the main thread and get its result, the zero value when headless or shut down
 func Call[T any](fn func() T) T {
+	out, _ := CallContext(context.Background(), fn)
+	return out
+}
+
+// run a gpu call on the main thread and get its result, waiting until ctx ends as DoContext does
+func CallContext[T any](ctx context.Context, fn func() T) (T, error) {
 	var out T
-	Do(func() {
+	err := DoContext(ctx, func() {
+		out = fn()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return out, nil
+}
+
+// run a call that needs no gpu, on the main thread or directly when headless
+func DoCPU(fn func()) {
+	if headless.Load() {
+		fn()
+		return
+	}
+	Do(fn)
+}
+
+func CallCPU[T any](fn func() T) T {
+	var out T
+	DoCPU(func() {
 		out = fn()
 	})
 	return out
 }
 
+// make the calling goroutine the one gpu calls run on, it stays on its os thread until ExitMainThread
+// a main thread shut down before starts serving requests again
 func EnterMainThread() {
-	onMainThread.Store(true)
+	runtime.LockOSThread()
+	mainThread.Store(threadID())
+
+	stopMu.Lock()
+	defer stopMu.Unlock()
+	if down.Load() {
+		stopCh = make(chan struct{})
+		down.Store(false)
+	}
+	peak.Store(0)
+	full.Store(0)
+	served.Store(0)
+	failed.Store(0)
 }
 
 func ExitMainThread() {
-	onMainThread.Store(false)
+	mainThread.Store(0)
+	runtime.UnlockOSThread()
 }
 
-func Poll() {
+// whether the caller runs on the main thread, other goroutines are never scheduled on a locked thread
+func isMainThread() bool {
+	id := mainThread.Load()
+	return id != 0 && id == threadID()
+}
+
+// channel closed once the main thread shut down
+func stopped() chan struct{} {
+	stopMu.Lock()
+	defer stopMu.Unlock()
+	return stopCh
+}
+
+// stop serving requests, waiting and new ones fail with ErrShutdown and recorded calls are dropped
+// calls made on the main thread itself still run, so resources can be freed after it
+func Shutdown() {
+	stopMu.Lock()
+	if down.Load() {
+		stopMu.Unlock()
+		return
+	}
+	down.Store(true)
+	close(stopCh)
+	stopMu.Unlock()
+
 	for {
 		select {
 		case req := <-reqCh:
-			req.fn()
-			if req.done != nil {
+			if req.state.CompareAndSwap(reqWaiting, reqCanceled) {
+				req.err = ErrShutdown
+				failed.Add(1)
 				close(req.done)
 			}
 		default:
+			dropBatch()
+			return
+		}
+	}
+}
+
+// get the state of the request queue
+func GetStats() Stats {
+	batchMu.Lock()
+	recorded := len(pending)
+	batchMu.Unlock()
+	return Stats{
+		Depth:    len(reqCh),
+		Peak:     int(peak.Load()),
+		Capacity: cap(reqCh),
+		Full:     full.Load(),
+		Served:   served.Load(),
+		Failed:   failed.Load(),
+		Recorded: recorded,
+		Stopped:  down.Load(),
+	}
+}
+
+func serve(req *request) {
+	//given up on while queued
+	if !req.state.CompareAndSwap(reqWaiting, reqRunning) {
+		return
+	}
+	if !req.queued.IsZero() {
+		profile.Observe(profile.TrackMain, "rlx wait", req.queued, time.Since(req.queued))
+	}
+	//the waiting goroutine is let go even if the call panics
+	ok := false
+	defer func() {
+		if !ok {
+			req.err = fmt.Errorf("Main thread call panicked")
+		}
+		served.Add(1)
+		close(req.done)
+	}()
+	runBatch()
+	sp := profile.Begin(profile.TrackMain, "rlx request")
+	req.fn()
+	sp.End()
+	ok = true
+}
+
+// serve waiting requests and run the calls recorded since the last poll, called once a frame on the main thread
+func Poll() {
+	profile.Count(profile.TrackMain, "rlx queue", float64(len(reqCh)))
+	for {
+		select {
+		case req := <-reqCh:
+			serve(req)
+		default:
+			runBatch()
 			return
 		}
 	}
 }
+
+// serve requests until done is closed or timeout passes, so goroutines finishing up are not left waiting
+// reports whether done was closed
+func Drain(done <-chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-done:
+			Poll()
+			return true
+		case <-timer.C:
+			Poll()
+			return false
+		case req := <-reqCh:
+			serve(req)
+		}
+	}
+}
+
+// lock a mutex shared with other goroutines
+// on the main thread requests keep being served while waiting so holders calling Do cannot deadlock
+func Lock(mu *sync.Mutex) {
+	if !isMainThread() {
+		mu.Lock()
+		return
+	}
+
+	for !mu.TryLock() {
+		select {
+		case req := <-reqCh:
+			serve(req)
+		default:
+			time.Sleep(50 * time.Microsecond)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package rlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDrain(t *testing.T) {
+	//a goroutine finishing up still gets its calls served
+	done := make(chan struct{})
+	served := 0
+	go func() {
+		defer close(done)
+		for range 3 {
+			Do(func() { served++ })
+		}
+	}()
+	if !Drain(done, time.Second) || served != 3 {
+		t.Error(fmt.Sprintf("TestDrain served %d calls", served))
+	}
+
+	//a goroutine that never finishes is given up on
+	stuck := make(chan struct{})
+	if Drain(stuck, 10*time.Millisecond) {
+		t.Error("TestDrain returned before done")
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	//nothing serves the request, it times out and is never run
+	ran := false
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := DoContext(ctx, func() { ran = true })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error(fmt.Sprintf("TestDoContext expected a deadline error, got %v", err))
+	}
+	Poll()
+	if ran {
+		t.Error("TestDoContext ran a request given up on")
+	}
+
+	//a served request gives its result
+	stop := make(chan struct{})
+	exited := serveMain(stop)
+	got, err := CallContext(context.Background(), func() int { return 7 })
+	if err != nil || got != 7 {
+		t.Error(fmt.Sprintf("TestDoContext expected 7, got %d, %v", got, err))
+	}
+	stats := GetStats()
+	if stats.Served != 1 || stats.Capacity != QueueSize || stats.Peak < 1 {
+		t.Error(fmt.Sprintf("TestDoContext got stats %+v", stats))
+	}
+	close(stop)
+	<-exited
+}
+
+func TestShutdown(t *testing.T) {
+	EnterMainThread()
+	ExitMainThread()
+
+	//a waiting request fails once the main thread stops
+	errs := make(chan error)
+	go func() {
+		errs <- DoContext(context.Background(), func() {})
+	}()
+	for len(reqCh) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if stats := GetStats(); stats.Depth != 1 || stats.Peak != 1 {
+		t.Error(fmt.Sprintf("TestShutdown expected one queued request, got %+v", stats))
+	}
+	Shutdown()
+	if err := <-errs; !errors.Is(err, ErrShutdown) {
+		t.Error(fmt.Sprintf("TestShutdown expected ErrShutdown for a waiting request, got %v", err))
+	}
+
+	//new requests fail straight away and recorded calls are dropped
+	_, err := CallContext(context.Background(), func() int { return 1 })
+	if !errors.Is(err, ErrShutdown) {
+		t.Error(fmt.Sprintf("TestShutdown expected ErrShutdown for a new request, got %v", err))
+	}
+	Async(func() {})
+	stats := GetStats()
+	if !stats.Stopped || stats.Depth != 0 || stats.Recorded != 0 || stats.Failed != 2 {
+		t.Error(fmt.Sprintf("TestShutdown got stats %+v", stats))
+	}
+
+	//entering the main thread again serves requests
+	stop := make(chan struct{})
+	exited := serveMain(stop)
+	if got := Call(func() int { return 2 }); got != 2 {
+		t.Error(fmt.Sprintf("TestShutdown expected 2 after entering again, got %d", got))
+	}
+	close(stop)
+	<-exited
+}
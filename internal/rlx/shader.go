@@ -3,156 +3,208 @@ package rlx
 import (
 	"unsafe"
 
+	"karalis/pkg/profile"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 func CompileShader(shaderCode string, type_ int32) uint32 {
+	logCall("CompileShader", shaderCode, type_)
+	if IsHeadless() {
+		return nextNullID()
+	}
 	return Call(func() uint32 {
+		defer profile.Begin(profile.TrackMain, "shader compile").End()
 		return rl.CompileShader(shaderCode, type_)
 	})
 }
 
 func LoadComputeShaderProgram(shaderID uint32) uint32 {
+	logCall("LoadComputeShaderProgram", shaderID)
+	if IsHeadless() {
+		return nextNullID()
+	}
 	return Call(func() uint32 {
 		return rl.LoadComputeShaderProgram(shaderID)
 	})
 }
 
 func GetLocationUniform(shaderId uint32, uniformName string) int32 {
+	logCall("GetLocationUniform", shaderRef(shaderId), uniformName)
 	return Call(func() int32 {
 		return rl.GetLocationUniform(shaderId, uniformName)
 	})
 }
 
 func SetUniformMatrix(locIndex int32, mat rl.Matrix) {
-	Do(func() {
-		rl.SetUniformMatrix(locIndex, mat)
-	})
+	logCall("SetUniformMatrix", locIndex, mat)
+	record(command{op: opSetUniformMatrix, i: [4]int32{locIndex}, mat: mat})
 }
 
 func SetUniformSampler(locIndex int32, texture uint32) {
-	Do(func() {
-		rl.SetUniformSampler(locIndex, texture)
-	})
+	logCall("SetUniformSampler", locIndex, texRef(texture))
+	record(command{op: opSetUniformSampler, i: [4]int32{locIndex}, id: texture})
 }
 
 func ComputeShaderDispatch(groupsX, groupsY, groupsZ uint32) {
+	logCall("ComputeShaderDispatch", groupsX, groupsY, groupsZ)
 	Do(func() {
 		rl.ComputeShaderDispatch(groupsX, groupsY, groupsZ)
 	})
 }
 
 func UnloadShaderProgram(id uint32) {
+	logCall("UnloadShaderProgram", shaderRef(id))
 	Do(func() {
 		rl.UnloadShaderProgram(id)
 	})
 }
 
 func EnableShader(shaderID uint32) {
-	Do(func() {
-		rl.EnableShader(shaderID)
-	})
+	logCall("EnableShader", shaderRef(shaderID))
+	record(command{op: opEnableShader, id: shaderID})
 }
 
 func DisableShader() {
-	Do(func() {
-		rl.DisableShader()
-	})
+	logCall("DisableShader")
+	record(command{op: opDisableShader})
 }
 
 func LoadShader(vsFileName string, csFileName string, esFileName string, gsFileName string, fsFileName string) rl.Shader {
+	logCall("LoadShader", vsFileName, csFileName, esFileName, gsFileName, fsFileName)
+	if IsHeadless() {
+		return nullShader()
+	}
 	return Call(func() rl.Shader {
+		defer profile.Begin(profile.TrackMain, "shader compile").End()
 		return rl.LoadShader(vsFileName, csFileName, esFileName, gsFileName, fsFileName)
 	})
 }
 
 func LoadShaderFromMemory(vsCode string, csCode string, esCode string, gsCode string, fsCode string) rl.Shader {
+	logCall("LoadShaderFromMemory", vsCode, csCode, esCode, gsCode, fsCode)
+	if IsHeadless() {
+		return nullShader()
+	}
 	return Call(func() rl.Shader {
+		defer profile.Begin(profile.TrackMain, "shader compile").End()
 		return rl.LoadShaderFromMemory(vsCode, csCode, esCode, gsCode, fsCode)
 	})
 }
 
+// id of the default shader raylib falls back to when a shader fails to build
+func GetShaderIdDefault() uint32 {
+	logCall("GetShaderIdDefault")
+	return Call(func() uint32 {
+		return rl.GetShaderIdDefault()
+	})
+}
+
 func IsShaderValid(shader rl.Shader) bool {
+	logCall("IsShaderValid", shader)
 	return Call(func() bool {
 		return rl.IsShaderValid(shader)
 	})
 }
 
 func GetShaderLocation(shader rl.Shader, uniformName string) int32 {
+	logCall("GetShaderLocation", shader, uniformName)
 	return Call(func() int32 {
-		return rl.GetShaderLocation(shader, uniformName)
+		return rl.GetShaderLocation(currentShader(shader), uniformName)
 	})
 }
 
 func GetShaderLocationAttrib(shader rl.Shader, attribName string) int32 {
+	logCall("GetShaderLocationAttrib", shader, attribName)
 	return Call(func() int32 {
-		return rl.GetShaderLocationAttrib(shader, attribName)
+		return rl.GetShaderLocationAttrib(currentShader(shader), attribName)
 	})
 }
 
 func SetShaderValue(shader rl.Shader, locIndex int32, value []float32, uniformType rl.ShaderUniformDataType) {
-	buf := append([]float32(nil), value...)
-	Do(func() {
-		rl.SetShaderValue(shader, locIndex, buf, uniformType)
-	})
+	logCall("SetShaderValue", shader, locIndex, value, uniformType)
+	if len(value) > commandFloats {
+		buf := append([]float32(nil), value...)
+		Async(func() {
+			rl.SetShaderValue(currentShader(shader), locIndex, buf, uniformType)
+		})
+		return
+	}
+	c := command{op: opSetShaderValue, shader: shader, i: [4]int32{locIndex, int32(uniformType), int32(len(value))}}
+	copy(c.f[:], value)
+	record(c)
 }
 
 func SetShaderValueV(shader rl.Shader, locIndex int32, value []float32, uniformType rl.ShaderUniformDataType, count int32) {
-	buf := append([]float32(nil), value...)
-	Do(func() {
-		rl.SetShaderValueV(shader, locIndex, buf, uniformType, count)
-	})
+	logCall("SetShaderValueV", shader, locIndex, value, uniformType, count)
+	if len(value) > commandFloats {
+		buf := append([]float32(nil), value...)
+		Async(func() {
+			rl.SetShaderValueV(currentShader(shader), locIndex, buf, uniformType, count)
+		})
+		return
+	}
+	c := command{op: opSetShaderValueV, shader: shader, i: [4]int32{locIndex, int32(uniformType), int32(len(value)), count}}
+	copy(c.f[:], value)
+	record(c)
 }
 
 func SetShaderValueMatrix(shader rl.Shader, locIndex int32, mat rl.Matrix) {
-	Do(func() {
-		rl.SetShaderValueMatrix(shader, locIndex, mat)
-	})
+	logCall("SetShaderValueMatrix", shader, locIndex, mat)
+	record(command{op: opSetShaderValueMatrix, shader: shader, i: [4]int32{locIndex}, mat: mat})
 }
 
 func SetShaderValueTexture(shader rl.Shader, locIndex int32, texture rl.Texture2D) {
-	Do(func() {
-		rl.SetShaderValueTexture(shader, locIndex, texture)
-	})
+	logCall("SetShaderValueTexture", shader, locIndex, texture)
+	record(command{op: opSetShaderValueTexture, shader: shader, i: [4]int32{locIndex}, tex: texture})
 }
 
 func UnloadShader(shader rl.Shader) {
+	logCall("UnloadShader", shader)
 	Do(func() {
 		rl.UnloadShader(shader)
 	})
 }
 
+// value is read when the call runs, it must not change after
 func SetUniform(locIndex int32, value any, uniformType, count int32) {
-	Do(func() {
-		rl.SetUniform(locIndex, value, uniformType, count)
-	})
+	logCall("SetUniform", locIndex, value, uniformType, count)
+	record(command{op: opSetUniform, i: [4]int32{locIndex, uniformType, count}, val: value})
 }
 
 func LoadShaderBuffer(size uint32, data unsafe.Pointer, usageHint int32) uint32 {
+	logCall("LoadShaderBuffer", size, data, usageHint)
+	if IsHeadless() {
+		return nextNullID()
+	}
 	return Call(func() uint32 {
 		return rl.LoadShaderBuffer(size, data, usageHint)
 	})
 }
 
 func UpdateShaderBuffer(id uint32, data unsafe.Pointer, dataSize uint32, offset uint32) {
+	logCall("UpdateShaderBuffer", bufferRef(id), data, dataSize, offset)
 	Do(func() {
 		rl.UpdateShaderBuffer(id, data, dataSize, offset)
 	})
 }
 
 func BindShaderBuffer(id uint32, index uint32) {
+	logCall("BindShaderBuffer", bufferRef(id), index)
 	Do(func() {
 		rl.BindShaderBuffer(id, index)
 	})
 }
 
 func ReadShaderBuffer(id uint32, dest unsafe.Pointer, count uint32, offset uint32) {
+	logCall("ReadShaderBuffer", bufferRef(id), dest, count, offset)
 	Do(func() {
 		rl.ReadShaderBuffer(id, dest, count, offset)
 	})
 }
 
 func UnloadShaderBuffer(id uint32) {
+	logCall("UnloadShaderBuffer", bufferRef(id))
 	Do(func() {
 		rl.UnloadShaderBuffer(id)
 	})
@@ -7,92 +7,103 @@ import (
 )
 
 func SetShapesTexture(texture rl.Texture2D, source rl.Rectangle) {
+	logCall("SetShapesTexture", texture, source)
 	Do(func() {
 		rl.SetShapesTexture(texture, source)
 	})
 }
 
 func GetShapesTexture() rl.Texture2D {
+	logCall("GetShapesTexture")
 	return Call(func() rl.Texture2D {
 		return rl.GetShapesTexture()
 	})
 }
 
 func GetShapesTextureRectangle() rl.Rectangle {
+	logCall("GetShapesTextureRectangle")
 	return Call(func() rl.Rectangle {
 		return rl.GetShapesTextureRectangle()
 	})
 }
 
 func DrawPixel(posX int32, posY int32, col color.RGBA) {
-	Do(func() {
+	logCall("DrawPixel", posX, posY, col)
+	Async(func() {
 		rl.DrawPixel(posX, posY, col)
 	})
 }
 
 func DrawPixelV(position rl.Vector2, col color.RGBA) {
-	Do(func() {
+	logCall("DrawPixelV", position, col)
+	Async(func() {
 		rl.DrawPixelV(position, col)
 	})
 }
 
 func DrawLine(startPosX int32, startPosY int32, endPosX int32, endPosY int32, col color.RGBA) {
-	Do(func() {
+	logCall("DrawLine", startPosX, startPosY, endPosX, endPosY, col)
+	Async(func() {
 		rl.DrawLine(startPosX, startPosY, endPosX, endPosY, col)
 	})
 }
 
 func DrawLineV(startPos rl.Vector2, endPos rl.Vector2, col color.RGBA) {
-	Do(func() {
+	logCall("DrawLineV", startPos, endPos, col)
+	Async(func() {
 		rl.DrawLineV(startPos, endPos, col)
 	})
 }
 
 func DrawLineEx(startPos rl.Vector2, endPos rl.Vector2, thick float32, col color.RGBA) {
-	Do(func() {
+	logCall("DrawLineEx", startPos, endPos, thick, col)
+	Async(func() {
 		rl.DrawLineEx(startPos, endPos, thick, col)
 	})
 }
 
 func DrawLineStrip(points []rl.Vector2, col color.RGBA) {
+	logCall("DrawLineStrip", points, col)
 	buf := append([]rl.Vector2(nil), points...)
-	Do(func() {
+	Async(func() {
 		rl.DrawLineStrip(buf, col)
 	})
 }
 
 func DrawCircle(centerX int32, centerY int32, radius float32, col color.RGBA) {
-	Do(func() {
+	logCall("DrawCircle", centerX, centerY, radius, col)
+	Async(func() {
 		rl.DrawCircle(centerX, centerY, radius, col)
 	})
 }
 
 func DrawCircleV(center rl.Vector2, radius float32, col color.RGBA) {
-	Do(func() {
+	logCall("DrawCircleV", center, radius, col)
+	Async(func() {
 		rl.DrawCircleV(center, radius, col)
 	})
 }
 
 func DrawRectangle(posX int32, posY int32, width int32, height int32, col color.RGBA) {
-	Do(func() {
-		rl.DrawRectangle(posX, posY, width, height, col)
-	})
+	logCall("DrawRectangle", posX, posY, width, height, col)
+	record(command{op: opDrawRectangle, i: [4]int32{posX, posY, width, height}, col: col})
 }
 
 func DrawRectangleRec(rec rl.Rectangle, col color.RGBA) {
-	Do(func() {
-		rl.DrawRectangleRec(rec, col)
-	})
+	logCall("DrawRectangleRec", rec, col)
+	record(command{op: opDrawRectangleRec, f: [commandFloats]float32{rec.X, rec.Y, rec.Width, rec.Height}, col: col})
 }
 
 func DrawRectanglePro(rec rl.Rectangle, origin rl.Vector2, rotation float32, col color.RGBA) {
-	Do(func() {
+	logCall("DrawRectanglePro", rec, origin, rotation, col)
+	Async(func() {
 		rl.DrawRectanglePro(rec, origin, rotation, col)
 	})
 }
 
 func DrawTriangle(v1 rl.Vector2, v2 rl.Vector2, v3 rl.Vector2, col color.RGBA) {
-	Do(func() {
+	logCall("DrawTriangle", v1, v2, v3, col)
+	Async(func() {
 		rl.DrawTriangle(v1, v2, v3, col)
 	})
 }
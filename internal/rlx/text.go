@@ -7,18 +7,21 @@ import (
 )
 
 func GetFontDefault() rl.Font {
+	logCall("GetFontDefault")
 	return Call(func() rl.Font {
 		return rl.GetFontDefault()
 	})
 }
 
 func LoadFont(fileName string) rl.Font {
+	logCall("LoadFont", fileName)
 	return Call(func() rl.Font {
 		return rl.LoadFont(fileName)
 	})
 }
 
 func LoadFontEx(fileName string, fontSize int32, codepoints []rune, runesNumber ...int32) rl.Font {
+	logCall("LoadFontEx", fileName, fontSize, codepoints, runesNumber)
 	cp := append([]rune(nil), codepoints...)
 	return Call(func() rl.Font {
 		return rl.LoadFontEx(fileName, fontSize, cp, runesNumber...)
@@ -26,73 +29,83 @@ func LoadFontEx(fileName string, fontSize int32, codepoints []rune, runesNumber
 }
 
 func LoadFontFromImage(image rl.Image, key color.RGBA, firstChar rune) rl.Font {
+	logCall("LoadFontFromImage", image, key, firstChar)
 	return Call(func() rl.Font {
 		return rl.LoadFontFromImage(image, key, firstChar)
 	})
 }
 
 func IsFontValid(font rl.Font) bool {
+	logCall("IsFontValid", font)
 	return Call(func() bool {
 		return rl.IsFontValid(font)
 	})
 }
 
 func UnloadFont(font rl.Font) {
+	logCall("UnloadFont", font)
 	Do(func() {
 		rl.UnloadFont(font)
 	})
 }
 
 func DrawFPS(posX int32, posY int32) {
-	Do(func() {
+	logCall("DrawFPS", posX, posY)
+	Async(func() {
 		rl.DrawFPS(posX, posY)
 	})
 }
 
 func DrawText(text string, posX int32, posY int32, fontSize int32, col color.RGBA) {
-	Do(func() {
-		rl.DrawText(text, posX, posY, fontSize, col)
-	})
+	logCall("DrawText", text, posX, posY, fontSize, col)
+	record(command{op: opDrawText, str: text, i: [4]int32{posX, posY, fontSize}, col: col})
 }
 
 func DrawTextEx(font rl.Font, text string, position rl.Vector2, fontSize float32, spacing float32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTextEx", font, text, position, fontSize, spacing, tint)
+	Async(func() {
 		rl.DrawTextEx(font, text, position, fontSize, spacing, tint)
 	})
 }
 
 func DrawTextPro(font rl.Font, text string, position rl.Vector2, origin rl.Vector2, rotation float32, fontSize float32, spacing float32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTextPro", font, text, position, origin, rotation, fontSize, spacing, tint)
+	Async(func() {
 		rl.DrawTextPro(font, text, position, origin, rotation, fontSize, spacing, tint)
 	})
 }
 
 func DrawTextCodepoint(font rl.Font, codepoint rune, position rl.Vector2, fontSize float32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTextCodepoint", font, codepoint, position, fontSize, tint)
+	Async(func() {
 		rl.DrawTextCodepoint(font, codepoint, position, fontSize, tint)
 	})
 }
 
 func DrawTextCodepoints(font rl.Font, codepoints []rune, position rl.Vector2, fontSize float32, spacing float32, tint color.RGBA) {
+	logCall("DrawTextCodepoints", font, codepoints, position, fontSize, spacing, tint)
 	cp := append([]rune(nil), codepoints...)
-	Do(func() {
+	Async(func() {
 		rl.DrawTextCodepoints(font, cp, position, fontSize, spacing, tint)
 	})
 }
 
 func SetTextLineSpacing(spacing int) {
+	logCall("SetTextLineSpacing", spacing)
 	Do(func() {
 		rl.SetTextLineSpacing(spacing)
 	})
 }
 
 func MeasureText(text string, fontSize int32) int32 {
+	logCall("MeasureText", text, fontSize)
 	return Call(func() int32 {
 		return rl.MeasureText(text, fontSize)
 	})
 }
 
 func MeasureTextEx(font rl.Font, text string, fontSize float32, spacing float32) rl.Vector2 {
+	logCall("MeasureTextEx", font, text, fontSize, spacing)
 	return Call(func() rl.Vector2 {
 		return rl.MeasureTextEx(font, text, fontSize, spacing)
 	})
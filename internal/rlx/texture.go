@@ -1,61 +1,169 @@
 package rlx
 
+/*
+// from rlgl, which raylib-go does not bind
+unsigned int rlLoadTexture(const void *data, int width, int height, int format, int mipmapCount);
+*/
+import "C"
+
 import (
 	"image"
 	"image/color"
 
+	"karalis/pkg/goray"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 func LoadTexture(fileName string) rl.Texture2D {
+	logCall("LoadTexture", fileName)
+	if IsHeadless() {
+		img := rl.LoadImage(fileName)
+		defer rl.UnloadImage(img)
+		return nullTexture(img.Width, img.Height, img.Format)
+	}
 	return Call(func() rl.Texture2D {
 		return rl.LoadTexture(fileName)
 	})
 }
 
 func LoadTextureFromImage(image *rl.Image) rl.Texture2D {
+	logCall("LoadTextureFromImage", image)
+	if IsHeadless() {
+		return nullTexture(image.Width, image.Height, image.Format)
+	}
 	return Call(func() rl.Texture2D {
 		return rl.LoadTextureFromImage(image)
 	})
 }
 
 func LoadTextureCubemap(image *rl.Image, layout int32) rl.Texture2D {
+	logCall("LoadTextureCubemap", image, layout)
+	if IsHeadless() {
+		return nullTexture(image.Width, image.Height, image.Format)
+	}
 	return Call(func() rl.Texture2D {
 		return rl.LoadTextureCubemap(image, layout)
 	})
 }
 
 func LoadRenderTexture(width int32, height int32) rl.RenderTexture2D {
+	logCall("LoadRenderTexture", width, height)
+	if IsHeadless() {
+		return nullRenderTexture(width, height)
+	}
 	return Call(func() rl.RenderTexture2D {
 		return rl.LoadRenderTexture(width, height)
 	})
 }
 
+// load a render texture with a depth texture shaders can sample in place of the depth renderbuffer,
+// or with no depth at all when depth is false
+// returns an empty render texture when the framebuffer is not complete
+func LoadRenderTextureEx(width int32, height int32, depth bool, depthTexture bool) rl.RenderTexture2D {
+	logCall("LoadRenderTextureEx", width, height, depth, depthTexture)
+	if IsHeadless() {
+		target := nullRenderTexture(width, height)
+		if !depth {
+			target.Depth = rl.Texture2D{}
+		}
+		return target
+	}
+	return Call(func() rl.RenderTexture2D {
+		return loadRenderTextureEx(width, height, depth, depthTexture)
+	})
+}
+
+// build the framebuffer the way rl.LoadRenderTexture does, with the depth attachment asked for
+func loadRenderTextureEx(width int32, height int32, depth bool, depthTexture bool) rl.RenderTexture2D {
+	target := rl.RenderTexture2D{ID: rl.LoadFramebuffer()}
+	if target.ID == 0 {
+		return target
+	}
+
+	target.Texture = rl.Texture2D{
+		ID:      uint32(C.rlLoadTexture(nil, C.int(width), C.int(height), C.int(rl.UncompressedR8g8b8a8), 1)),
+		Width:   width,
+		Height:  height,
+		Mipmaps: 1,
+		Format:  rl.UncompressedR8g8b8a8,
+	}
+	rl.FramebufferAttach(target.ID, target.Texture.ID, rl.AttachmentColorChannel0, rl.AttachmentTexture2d, 0)
+	if depth {
+		target.Depth = rl.Texture2D{
+			ID:      rl.LoadTextureDepth(width, height, !depthTexture),
+			Width:   width,
+			Height:  height,
+			Mipmaps: 1,
+			Format:  rl.UncompressedR32,
+		}
+		attach := rl.AttachmentRenderbuffer
+		if depthTexture {
+			attach = rl.AttachmentTexture2d
+		}
+		rl.FramebufferAttach(target.ID, target.Depth.ID, rl.AttachmentDepth, attach, 0)
+	}
+
+	//unloading the framebuffer also frees its depth attachment
+	if !rl.FramebufferComplete(target.ID) {
+		rl.UnloadRenderTexture(target)
+		return rl.RenderTexture2D{}
+	}
+	return target
+}
+
+// load a render texture with a combined depth and stencil renderbuffer, which rlgl cannot make
+// the renderbuffer id is kept in Depth, and an empty render texture is returned when it cannot be made
+func LoadRenderTextureStencil(width int32, height int32) rl.RenderTexture2D {
+	logCall("LoadRenderTextureStencil", width, height)
+	if IsHeadless() {
+		return nullRenderTexture(width, height)
+	}
+	return Call(func() rl.RenderTexture2D {
+		target := loadRenderTextureEx(width, height, false, false)
+		if target.ID == 0 {
+			return target
+		}
+		rb, err := goray.AttachDepthStencil(target.ID, width, height)
+		if err != nil || !rl.FramebufferComplete(target.ID) {
+			rl.UnloadRenderTexture(target)
+			return rl.RenderTexture2D{}
+		}
+		target.Depth = rl.Texture2D{ID: rb, Width: width, Height: height, Mipmaps: 1}
+		return target
+	})
+}
+
 func IsTextureValid(texture rl.Texture2D) bool {
+	logCall("IsTextureValid", texture)
 	return Call(func() bool {
 		return rl.IsTextureValid(texture)
 	})
 }
 
 func UnloadTexture(texture rl.Texture2D) {
+	logCall("UnloadTexture", texture)
 	Do(func() {
 		rl.UnloadTexture(texture)
 	})
 }
 
 func IsRenderTextureValid(target rl.RenderTexture2D) bool {
+	logCall("IsRenderTextureValid", target)
 	return Call(func() bool {
 		return rl.IsRenderTextureValid(target)
 	})
 }
 
 func UnloadRenderTexture(target rl.RenderTexture2D) {
+	logCall("UnloadRenderTexture", target)
 	Do(func() {
 		rl.UnloadRenderTexture(target)
 	})
 }
 
 func UpdateTexture(texture rl.Texture2D, pixels any) {
+	logCall("UpdateTexture", texture, pixels)
 	switch p := pixels.(type) {
 	case []color.RGBA:
 		buf := append([]color.RGBA(nil), p...)
@@ -82,6 +190,7 @@ func UpdateTexture(texture rl.Texture2D, pixels any) {
 }
 
 func UpdateTextureRec(texture rl.Texture2D, rec rl.Rectangle, pixels any) {
+	logCall("UpdateTextureRec", texture, rec, pixels)
 	switch p := pixels.(type) {
 	case []color.RGBA:
 		buf := append([]color.RGBA(nil), p...)
@@ -108,55 +217,67 @@ func UpdateTextureRec(texture rl.Texture2D, rec rl.Rectangle, pixels any) {
 }
 
 func GenTextureMipmaps(texture *rl.Texture2D) {
+	logCall("GenTextureMipmaps", texture)
 	Do(func() {
 		rl.GenTextureMipmaps(texture)
 	})
 }
 
 func SetTextureFilter(texture rl.Texture2D, filter rl.TextureFilterMode) {
-	Do(func() {
+	logCall("SetTextureFilter", texture, filter)
+	Async(func() {
 		rl.SetTextureFilter(texture, filter)
 	})
 }
 
 func SetTextureWrap(texture rl.Texture2D, wrap rl.TextureWrapMode) {
-	Do(func() {
+	logCall("SetTextureWrap", texture, wrap)
+	Async(func() {
 		rl.SetTextureWrap(texture, wrap)
 	})
 }
 
 func DrawTexture(texture rl.Texture2D, posX int32, posY int32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTexture", texture, posX, posY, tint)
+	Async(func() {
 		rl.DrawTexture(texture, posX, posY, tint)
 	})
 }
 
 func DrawTextureV(texture rl.Texture2D, position rl.Vector2, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTextureV", texture, position, tint)
+	Async(func() {
 		rl.DrawTextureV(texture, position, tint)
 	})
 }
 
 func DrawTextureEx(texture rl.Texture2D, position rl.Vector2, rotation float32, scale float32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTextureEx", texture, position, rotation, scale, tint)
+	Async(func() {
 		rl.DrawTextureEx(texture, position, rotation, scale, tint)
 	})
 }
 
 func DrawTextureRec(texture rl.Texture2D, source rl.Rectangle, position rl.Vector2, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTextureRec", texture, source, position, tint)
+	Async(func() {
 		rl.DrawTextureRec(texture, source, position, tint)
 	})
 }
 
 func DrawTexturePro(texture rl.Texture2D, source rl.Rectangle, dest rl.Rectangle, origin rl.Vector2, rotation float32, tint color.RGBA) {
-	Do(func() {
-		rl.DrawTexturePro(texture, source, dest, origin, rotation, tint)
+	logCall("DrawTexturePro", texture, source, dest, origin, rotation, tint)
+	record(command{
+		op:  opDrawTexturePro,
+		tex: texture,
+		f:   [commandFloats]float32{source.X, source.Y, source.Width, source.Height, dest.X, dest.Y, dest.Width, dest.Height, origin.X, origin.Y, rotation},
+		col: tint,
 	})
 }
 
 func DrawTextureNPatch(texture rl.Texture2D, nPatchInfo rl.NPatchInfo, dest rl.Rectangle, origin rl.Vector2, rotation float32, tint color.RGBA) {
-	Do(func() {
+	logCall("DrawTextureNPatch", texture, nPatchInfo, dest, origin, rotation, tint)
+	Async(func() {
 		rl.DrawTextureNPatch(texture, nPatchInfo, dest, origin, rotation, tint)
 	})
 }
@@ -0,0 +1,16 @@
+package rlx
+
+/*
+#include <pthread.h>
+#include <stdint.h>
+
+static uintptr_t rlx_thread(void) {
+	return (uintptr_t)pthread_self();
+}
+*/
+import "C"
+
+// id of the os thread the calling goroutine runs on
+func threadID() uint64 {
+	return uint64(C.rlx_thread())
+}
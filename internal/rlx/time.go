@@ -1,44 +1,72 @@
 package rlx
 
-import rl "github.com/gen2brain/raylib-go/raylib"
+import (
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
 
 func SetTargetFPS(fps int32) {
+	logCall("SetTargetFPS", fps)
+	if IsHeadless() {
+		nullSetFPS(fps)
+		return
+	}
 	Do(func() {
 		rl.SetTargetFPS(fps)
 	})
 }
 
 func GetFrameTime() float32 {
+	logCall("GetFrameTime")
+	if IsHeadless() {
+		return nullGetFrameTime()
+	}
 	return Call(func() float32 {
 		return rl.GetFrameTime()
 	})
 }
 
 func GetTime() float64 {
+	logCall("GetTime")
+	if IsHeadless() {
+		return nullGetTime()
+	}
 	return Call(func() float64 {
 		return rl.GetTime()
 	})
 }
 
 func GetFPS() int32 {
+	logCall("GetFPS")
+	if IsHeadless() {
+		return nullGetFPS()
+	}
 	return Call(func() int32 {
 		return rl.GetFPS()
 	})
 }
 
 func SwapScreenBuffer() {
+	logCall("SwapScreenBuffer")
 	Do(func() {
 		rl.SwapScreenBuffer()
 	})
 }
 
 func PollInputEvents() {
+	logCall("PollInputEvents")
 	Do(func() {
 		rl.PollInputEvents()
 	})
 }
 
 func WaitTime(seconds float64) {
+	logCall("WaitTime", seconds)
+	if IsHeadless() {
+		time.Sleep(time.Duration(seconds * float64(time.Second)))
+		return
+	}
 	Do(func() {
 		rl.WaitTime(seconds)
 	})
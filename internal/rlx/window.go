@@ -8,6 +8,11 @@ import (
 
 // InitWindow - Initialize window and OpenGL context
 func InitWindow(width int32, height int32, title string) {
+	logCall("InitWindow", width, height, title)
+	if IsHeadless() {
+		nullInitWindow(width, height)
+		return
+	}
 	Do(func() {
 		rl.InitWindow(width, height, title)
 	})
@@ -15,12 +20,18 @@ func InitWindow(width int32, height int32, title string) {
 
 // CloseWindow - Close window and unload OpenGL context
 func CloseWindow() {
+	logCall("CloseWindow")
+	if IsHeadless() {
+		nullCloseWindow()
+		return
+	}
 	Do(func() {
 		rl.CloseWindow()
 	})
 }
 
 func SetConfigFlags(flags uint32) {
+	logCall("SetConfigFlags", flags)
 	Do(func() {
 		rl.SetConfigFlags(flags)
 	})
@@ -28,108 +39,132 @@ func SetConfigFlags(flags uint32) {
 
 // WindowShouldClose - Check if application should close
 func WindowShouldClose() bool {
+	logCall("WindowShouldClose")
+	if IsHeadless() {
+		return nullShouldClose()
+	}
 	return Call(func() bool {
 		return rl.WindowShouldClose()
 	})
 }
 
 func IsWindowReady() bool {
+	logCall("IsWindowReady")
+	if IsHeadless() {
+		return !nullShouldClose()
+	}
 	return Call(func() bool {
 		return rl.IsWindowReady()
 	})
 }
 
 func IsWindowFullscreen() bool {
+	logCall("IsWindowFullscreen")
 	return Call(func() bool {
 		return rl.IsWindowFullscreen()
 	})
 }
 
 func IsWindowHidden() bool {
+	logCall("IsWindowHidden")
 	return Call(func() bool {
 		return rl.IsWindowHidden()
 	})
 }
 
 func IsWindowMinimized() bool {
+	logCall("IsWindowMinimized")
 	return Call(func() bool {
 		return rl.IsWindowMinimized()
 	})
 }
 
 func IsWindowMaximized() bool {
+	logCall("IsWindowMaximized")
 	return Call(func() bool {
 		return rl.IsWindowMaximized()
 	})
 }
 
 func IsWindowFocused() bool {
+	logCall("IsWindowFocused")
 	return Call(func() bool {
 		return rl.IsWindowFocused()
 	})
 }
 
 func IsWindowResized() bool {
+	logCall("IsWindowResized")
 	return Call(func() bool {
 		return rl.IsWindowResized()
 	})
 }
 
 func IsWindowState(flag uint32) bool {
+	logCall("IsWindowState", flag)
 	return Call(func() bool {
 		return rl.IsWindowState(flag)
 	})
 }
 
 func SetWindowState(flags uint32) {
+	logCall("SetWindowState", flags)
 	Do(func() {
 		rl.SetWindowState(flags)
 	})
 }
 
 func ClearWindowState(flags uint32) {
+	logCall("ClearWindowState", flags)
 	Do(func() {
 		rl.ClearWindowState(flags)
 	})
 }
 
 func ToggleFullscreen() {
+	logCall("ToggleFullscreen")
 	Do(func() {
 		rl.ToggleFullscreen()
 	})
 }
 
 func ToggleBorderlessWindowed() {
+	logCall("ToggleBorderlessWindowed")
 	Do(func() {
 		rl.ToggleBorderlessWindowed()
 	})
 }
 
 func MaximizeWindow() {
+	logCall("MaximizeWindow")
 	Do(func() {
 		rl.MaximizeWindow()
 	})
 }
 
 func MinimizeWindow() {
+	logCall("MinimizeWindow")
 	Do(func() {
 		rl.MinimizeWindow()
 	})
 }
 
 func RestoreWindow() {
+	logCall("RestoreWindow")
 	Do(func() {
 		rl.RestoreWindow()
 	})
 }
 
 func SetWindowIcon(image rl.Image) {
+	logCall("SetWindowIcon", image)
 	Do(func() {
 		rl.SetWindowIcon(image)
 	})
 }
 
 func SetWindowIcons(images []rl.Image, count int32) {
+	logCall("SetWindowIcons", images, count)
 	copied := append([]rl.Image(nil), images...)
 	Do(func() {
 		rl.SetWindowIcons(copied, count)
@@ -137,210 +172,265 @@ func SetWindowIcons(images []rl.Image, count int32) {
 }
 
 func SetWindowTitle(title string) {
+	logCall("SetWindowTitle", title)
 	Do(func() {
 		rl.SetWindowTitle(title)
 	})
 }
 
 func SetWindowPosition(x int, y int) {
+	logCall("SetWindowPosition", x, y)
 	Do(func() {
 		rl.SetWindowPosition(x, y)
 	})
 }
 
 func SetWindowMonitor(monitor int) {
+	logCall("SetWindowMonitor", monitor)
 	Do(func() {
 		rl.SetWindowMonitor(monitor)
 	})
 }
 
 func SetWindowMinSize(width int, height int) {
+	logCall("SetWindowMinSize", width, height)
 	Do(func() {
 		rl.SetWindowMinSize(width, height)
 	})
 }
 
 func SetWindowMaxSize(width int, height int) {
+	logCall("SetWindowMaxSize", width, height)
 	Do(func() {
 		rl.SetWindowMaxSize(width, height)
 	})
 }
 
 func SetWindowSize(width int, height int) {
+	logCall("SetWindowSize", width, height)
+	if IsHeadless() {
+		nullSetSize(int32(width), int32(height))
+		return
+	}
 	Do(func() {
 		rl.SetWindowSize(width, height)
 	})
 }
 
 func SetWindowOpacity(opacity float32) {
+	logCall("SetWindowOpacity", opacity)
 	Do(func() {
 		rl.SetWindowOpacity(opacity)
 	})
 }
 
 func SetWindowFocused() {
+	logCall("SetWindowFocused")
 	Do(func() {
 		rl.SetWindowFocused()
 	})
 }
 
 func GetWindowHandle() unsafe.Pointer {
+	logCall("GetWindowHandle")
 	return Call(func() unsafe.Pointer {
 		return rl.GetWindowHandle()
 	})
 }
 
 func GetScreenWidth() int {
+	logCall("GetScreenWidth")
+	if IsHeadless() {
+		width, _ := nullGetSize()
+		return int(width)
+	}
 	return Call(func() int {
 		return rl.GetScreenWidth()
 	})
 }
 
 func GetScreenHeight() int {
+	logCall("GetScreenHeight")
+	if IsHeadless() {
+		_, height := nullGetSize()
+		return int(height)
+	}
 	return Call(func() int {
 		return rl.GetScreenHeight()
 	})
 }
 
 func GetRenderWidth() int {
+	logCall("GetRenderWidth")
+	if IsHeadless() {
+		width, _ := nullGetSize()
+		return int(width)
+	}
 	return Call(func() int {
 		return rl.GetRenderWidth()
 	})
 }
 
 func GetRenderHeight() int {
+	logCall("GetRenderHeight")
+	if IsHeadless() {
+		_, height := nullGetSize()
+		return int(height)
+	}
 	return Call(func() int {
 		return rl.GetRenderHeight()
 	})
 }
 
 func GetMonitorCount() int {
+	logCall("GetMonitorCount")
 	return Call(func() int {
 		return rl.GetMonitorCount()
 	})
 }
 
 func GetCurrentMonitor() int {
+	logCall("GetCurrentMonitor")
 	return Call(func() int {
 		return rl.GetCurrentMonitor()
 	})
 }
 
 func GetMonitorPosition(monitor int) rl.Vector2 {
+	logCall("GetMonitorPosition", monitor)
 	return Call(func() rl.Vector2 {
 		return rl.GetMonitorPosition(monitor)
 	})
 }
 
 func GetMonitorWidth(monitor int) int {
+	logCall("GetMonitorWidth", monitor)
 	return Call(func() int {
 		return rl.GetMonitorWidth(monitor)
 	})
 }
 
 func GetMonitorHeight(monitor int) int {
+	logCall("GetMonitorHeight", monitor)
 	return Call(func() int {
 		return rl.GetMonitorHeight(monitor)
 	})
 }
 
 func GetMonitorPhysicalWidth(monitor int) int {
+	logCall("GetMonitorPhysicalWidth", monitor)
 	return Call(func() int {
 		return rl.GetMonitorPhysicalWidth(monitor)
 	})
 }
 
 func GetMonitorPhysicalHeight(monitor int) int {
+	logCall("GetMonitorPhysicalHeight", monitor)
 	return Call(func() int {
 		return rl.GetMonitorPhysicalHeight(monitor)
 	})
 }
 
 func GetMonitorRefreshRate(monitor int) int {
+	logCall("GetMonitorRefreshRate", monitor)
 	return Call(func() int {
 		return rl.GetMonitorRefreshRate(monitor)
 	})
 }
 
 func GetWindowPosition() rl.Vector2 {
+	logCall("GetWindowPosition")
 	return Call(func() rl.Vector2 {
 		return rl.GetWindowPosition()
 	})
 }
 
 func GetWindowScaleDPI() rl.Vector2 {
+	logCall("GetWindowScaleDPI")
 	return Call(func() rl.Vector2 {
 		return rl.GetWindowScaleDPI()
 	})
 }
 
 func GetMonitorName(monitor int) string {
+	logCall("GetMonitorName", monitor)
 	return Call(func() string {
 		return rl.GetMonitorName(monitor)
 	})
 }
 
 func SetClipboardText(text string) {
+	logCall("SetClipboardText", text)
 	Do(func() {
 		rl.SetClipboardText(text)
 	})
 }
 
 func GetClipboardText() string {
+	logCall("GetClipboardText")
 	return Call(func() string {
 		return rl.GetClipboardText()
 	})
 }
 
 func GetClipboardImage() rl.Image {
+	logCall("GetClipboardImage")
 	return Call(func() rl.Image {
 		return rl.GetClipboardImage()
 	})
 }
 
 func EnableEventWaiting() {
+	logCall("EnableEventWaiting")
 	Do(func() {
 		rl.EnableEventWaiting()
 	})
 }
 
 func DisableEventWaiting() {
+	logCall("DisableEventWaiting")
 	Do(func() {
 		rl.DisableEventWaiting()
 	})
 }
 
 func ShowCursor() {
+	logCall("ShowCursor")
 	Do(func() {
 		rl.ShowCursor()
 	})
 }
 
 func HideCursor() {
+	logCall("HideCursor")
 	Do(func() {
 		rl.HideCursor()
 	})
 }
 
 func IsCursorHidden() bool {
+	logCall("IsCursorHidden")
 	return Call(func() bool {
 		return rl.IsCursorHidden()
 	})
 }
 
 func EnableCursor() {
+	logCall("EnableCursor")
 	Do(func() {
 		rl.EnableCursor()
 	})
 }
 
 func DisableCursor() {
+	logCall("DisableCursor")
 	Do(func() {
 		rl.DisableCursor()
 	})
 }
 
 func IsCursorOnScreen() bool {
+	logCall("IsCursorOnScreen")
 	return Call(func() bool {
 		return rl.IsCursorOnScreen()
 	})
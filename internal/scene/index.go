@@ -0,0 +1,158 @@
+package scene
+
+import (
+	"karalis/pkg/spatial"
+
+	pub_object "karalis/pkg/object"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	IndexSize    = float32(64)
+	IndexMinSize = float32(1)
+)
+
+// cameras able to provide their combined view projection matrix for culling
+type viewProjCamera interface {
+	GetViewProjMatrix() rl.Matrix
+}
+
+type indexEntry struct {
+	mat rl.Matrix
+}
+
+func (s *Scene) initIndex() {
+	if s.index == nil {
+		s.index = spatial.NewOctree[pub_object.Object](rl.Vector3{}, IndexSize, IndexMinSize)
+		s.indexed = map[pub_object.Object]*indexEntry{}
+		s.moving = map[pub_object.Object]*indexEntry{}
+	}
+}
+
+// get the bounds an object is indexed by, from its collider or its own bounds
+func indexBounds(obj pub_object.Object) (rl.BoundingBox, bool) {
+	if col := obj.GetCollider(); col != nil {
+		return col.GetAABB(), true
+	}
+	if b, ok := obj.(pub_object.Bounded); ok {
+		return b.GetBounds(), true
+	}
+	return rl.BoundingBox{}, false
+}
+
+// index obj and its descendants as they join the scene
+func (s *Scene) OnAttach(obj pub_object.Object) {
+	if s == nil || obj == nil {
+		return
+	}
+	s.initIndex()
+
+	s.indexObject(obj)
+	pub_object.Walk(obj, func(child pub_object.Object) bool {
+		s.indexObject(child)
+		return true
+	})
+}
+
+// drop obj and its descendants from the index as they leave the scene
+func (s *Scene) OnDetach(obj pub_object.Object) {
+	if s == nil || obj == nil || s.index == nil {
+		return
+	}
+
+	s.unindexObject(obj)
+	pub_object.Walk(obj, func(child pub_object.Object) bool {
+		s.unindexObject(child)
+		return true
+	})
+}
+
+func (s *Scene) indexObject(obj pub_object.Object) {
+	box, ok := indexBounds(obj)
+	if !ok {
+		return
+	}
+	e := &indexEntry{mat: obj.GetModelMatrix()}
+	s.indexed[obj] = e
+	//interpolated objects move every tick and are checked for it
+	if _, ok := obj.(pub_object.Interpolated); ok {
+		s.moving[obj] = e
+	}
+	s.index.Insert(obj, box)
+}
+
+func (s *Scene) unindexObject(obj pub_object.Object) {
+	if _, ok := s.indexed[obj]; !ok {
+		return
+	}
+	s.index.Remove(obj)
+	delete(s.indexed, obj)
+	delete(s.moving, obj)
+}
+
+// move the bounds of indexed objects that moved this tick
+// objects joining and leaving are indexed as they attach and detach, so only moving ones are checked
+func (s *Scene) refreshIndex() {
+	if s == nil || s.index == nil {
+		return
+	}
+
+	for obj, e := range s.moving {
+		//only recompute bounds for objects that have moved
+		mat := obj.GetModelMatrix()
+		if e.mat == mat {
+			continue
+		}
+		e.mat = mat
+		if box, ok := indexBounds(obj); ok {
+			s.index.Insert(obj, box)
+		}
+	}
+}
+
+// check if an object should be drawn for a camera
+func (s *Scene) isVisible(obj pub_object.Object, frustum *spatial.Frustum) bool {
+	if frustum == nil || s.index == nil {
+		return true
+	}
+
+	box, ok := s.index.GetBox(obj)
+	if !ok {
+		return true
+	}
+	return frustum.ContainsBox(box)
+}
+
+func (s *Scene) QueryBox(box rl.BoundingBox) []pub_object.Object {
+	if s == nil {
+		return []pub_object.Object{}
+	}
+
+	return s.index.QueryBox(box)
+}
+
+func (s *Scene) QuerySphere(sp pub_object.Sphere) []pub_object.Object {
+	if s == nil {
+		return []pub_object.Object{}
+	}
+
+	return s.index.QuerySphere(sp)
+}
+
+func (s *Scene) QueryFrustum(f spatial.Frustum) []pub_object.Object {
+	if s == nil {
+		return []pub_object.Object{}
+	}
+
+	return s.index.QueryFrustum(f)
+}
+
+// get up to k objects closest to pos, nearest first
+func (s *Scene) Nearest(pos rl.Vector3, k int) []pub_object.Object {
+	if s == nil {
+		return []pub_object.Object{}
+	}
+
+	return s.index.Nearest(pos, k)
+}
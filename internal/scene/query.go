@@ -0,0 +1,48 @@
+package scene
+
+import (
+	pub_object "karalis/pkg/object"
+)
+
+// visit every object in the scene depth first until fn returns false
+func (s *Scene) Walk(fn func(pub_object.Object) bool) {
+	if s == nil {
+		return
+	}
+
+	pub_object.Walk(s, fn)
+}
+
+func (s *Scene) FindByID(id uint64) pub_object.Object {
+	if s == nil {
+		return nil
+	}
+
+	return pub_object.FindByID(s, id)
+}
+
+// find an object by its path of names, e.g. "world/cell_3_4/terrain"
+func (s *Scene) FindByPath(path string) pub_object.Object {
+	if s == nil {
+		return nil
+	}
+
+	return pub_object.FindByPath(s, path)
+}
+
+func (s *Scene) FindByTag(tag string) []pub_object.Object {
+	if s == nil {
+		return []pub_object.Object{}
+	}
+
+	return pub_object.FindByTag(s, tag)
+}
+
+// find all objects in the scene of a go type
+func OfType[T pub_object.Object](s *Scene) []T {
+	if s == nil {
+		return []T{}
+	}
+
+	return pub_object.OfType[T](s)
+}
@@ -3,7 +3,10 @@ package scene
 import (
 	"errors"
 	"image/color"
-	"slices"
+
+	"karalis/pkg/profile"
+	"karalis/pkg/render"
+	"karalis/pkg/spatial"
 
 	pub_object "karalis/pkg/object"
 
@@ -13,17 +16,27 @@ import (
 var ()
 
 type Scene struct {
+	meta pub_object.Meta
+
 	childs []pub_object.Object
 	parent pub_object.Object
+
+	index   *spatial.Octree[pub_object.Object]
+	indexed map[pub_object.Object]*indexEntry
+	moving  map[pub_object.Object]*indexEntry
+
+	pend pending
 }
 
 func (s *Scene) Init() error {
 	if s == nil {
 		return errors.New("Invalid scene")
 	}
+	s.meta.SetName("scene")
 
 	s.parent = nil
 	s.childs = []pub_object.Object{}
+	s.initIndex()
 
 	return nil
 }
@@ -37,7 +50,7 @@ func (s *Scene) Prerender(cam pub_object.Camera) []func() {
 	for _, child := range s.childs {
 		switch child.(type) {
 		default:
-			cmds = append(cmds, child.Prerender(cam)...)
+			cmds = append(cmds, profile.Cmds(profile.TrackMain, "Prerender", child, child.Prerender(cam))...)
 		}
 	}
 	return cmds
@@ -48,14 +61,28 @@ func (s *Scene) Render(cam pub_object.Camera) []func() {
 		return []func(){}
 	}
 
-	cmds := []func(){}
-	for _, child := range s.childs {
-		switch child.(type) {
-		default:
-			cmds = append(cmds, child.Render(cam)...)
+	q := render.NewQueue()
+	s.Submit(cam, q)
+	return q.Cmds(cam.GetPos())
+}
+
+func (s *Scene) Submit(cam pub_object.Camera, q *render.Queue) {
+	if s == nil {
+		return
+	}
+
+	//skip indexed objects outside the cameras view, nested ones included as containers collect through the queue
+	if vp, ok := cam.(viewProjCamera); ok && q.Visible == nil {
+		frustum := spatial.NewFrustum(vp.GetViewProjMatrix())
+		q.Visible = func(obj pub_object.Object) bool {
+			return s.isVisible(obj, &frustum)
 		}
+		defer func() { q.Visible = nil }()
+	}
+
+	for _, child := range s.childs {
+		render.Collect(q, cam, child)
 	}
-	return cmds
 }
 
 func (s *Scene) Postrender(cam pub_object.Camera) []func() {
@@ -65,7 +92,7 @@ func (s *Scene) Postrender(cam pub_object.Camera) []func() {
 
 	cmds := []func(){}
 	for _, child := range s.childs {
-		cmds = append(cmds, child.Postrender(cam)...)
+		cmds = append(cmds, profile.Cmds(profile.TrackMain, "Postrender", child, child.Postrender(cam))...)
 	}
 	return cmds
 }
@@ -86,10 +113,31 @@ func (s *Scene) Update(dt float32) {
 		return
 	}
 
+	//start of a tick, moving objects keep where they were to draw in between
+	s.SaveState()
+
 	//perform update on objects
 	for _, child := range s.childs {
 		child.Update(dt)
 	}
+
+	//safe point for tree changes made during the update
+	s.ApplyDeferred()
+	s.refreshIndex()
+}
+
+// remember the state of interpolated objects as the previous tick
+func (s *Scene) SaveState() {
+	if s == nil {
+		return
+	}
+
+	pub_object.Walk(s, func(obj pub_object.Object) bool {
+		if interp, ok := obj.(pub_object.Interpolated); ok {
+			interp.SaveState()
+		}
+		return true
+	})
 }
 
 func (s *Scene) OnAdd(obj pub_object.Object) {
@@ -104,6 +152,8 @@ func (s *Scene) OnRemove() {
 		return
 	}
 	s.parent = nil
+	s.meta.Release()
+	pub_object.RemoveChilds(s)
 }
 
 func (s *Scene) AddChild(obj pub_object.Object) {
@@ -113,6 +163,7 @@ func (s *Scene) AddChild(obj pub_object.Object) {
 
 	s.childs = append(s.childs, obj)
 	obj.OnAdd(s)
+	s.OnAttach(obj)
 }
 
 func (s *Scene) RemChild(obj pub_object.Object) {
@@ -130,6 +181,7 @@ func (s *Scene) RemChild(obj pub_object.Object) {
 
 	//remove child
 	if index >= 0 {
+		s.OnDetach(obj)
 		s.childs[index] = s.childs[len(s.childs)-1]
 		s.childs = s.childs[:len(s.childs)-1]
 		obj.OnRemove()
@@ -141,13 +193,7 @@ func (s *Scene) GetChilds() []pub_object.Object {
 		return []pub_object.Object{}
 	}
 
-	childs := s.childs
-	grandchilds := []pub_object.Object{}
-	for _, child := range childs {
-		grandchilds = append(grandchilds, child.GetChilds()...)
-	}
-
-	return slices.Concat(grandchilds, childs)
+	return s.childs
 }
 
 func (s *Scene) GetCollider() pub_object.Collider {
@@ -305,3 +351,11 @@ func (s *Scene) GetParent() pub_object.Object {
 	}
 	return s.parent
 }
+
+func (s *Scene) GetMeta() *pub_object.Meta {
+	if s == nil {
+		return nil
+	}
+
+	return &s.meta
+}
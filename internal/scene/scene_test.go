@@ -0,0 +1,227 @@
+package scene
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"karalis/pkg/render"
+
+	pub_object "karalis/pkg/object"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type testObj struct {
+	pub_object.Object
+
+	meta   pub_object.Meta
+	parent pub_object.Object
+	childs []pub_object.Object
+	ticks  int
+}
+
+type testCam struct {
+	pub_object.Camera
+}
+
+func (o *testObj) GetMeta() *pub_object.Meta        { return &o.meta }
+func (o *testObj) GetParent() pub_object.Object     { return o.parent }
+func (o *testObj) GetChilds() []pub_object.Object   { return o.childs }
+func (o *testObj) GetCollider() pub_object.Collider { return nil }
+func (o *testObj) OnAdd(parent pub_object.Object)   { o.parent = parent }
+func (o *testObj) OnRemove()                        { o.parent = nil }
+func (o *testObj) AddChild(child pub_object.Object) {
+	o.childs = append(o.childs, child)
+	child.OnAdd(o)
+	pub_object.Attached(o, child)
+}
+func (o *testObj) RemChild(child pub_object.Object) {
+	pub_object.Detached(o, child)
+	o.childs = slices.DeleteFunc(o.childs, func(c pub_object.Object) bool { return c == child })
+	child.OnRemove()
+}
+func (o *testObj) Update(dt float32) { o.ticks++ }
+func (o *testObj) Render(cam pub_object.Camera) []func() {
+	ticks := o.ticks
+	return []func(){func() { _ = ticks + len(o.childs) }}
+}
+
+func (c *testCam) GetPos() rl.Vector3 { return rl.Vector3{} }
+
+// unit box moving every tick
+type testBox struct {
+	testObj
+	pos rl.Vector3
+}
+
+func (b *testBox) GetBounds() rl.BoundingBox {
+	return rl.NewBoundingBox(b.pos, rl.Vector3Add(b.pos, rl.NewVector3(1, 1, 1)))
+}
+func (b *testBox) GetModelMatrix() rl.Matrix { return rl.MatrixTranslate(b.pos.X, b.pos.Y, b.pos.Z) }
+func (b *testBox) SaveState()                {}
+
+// object submitting its children through the queue like containers do
+type testGroup struct {
+	testObj
+}
+
+func (g *testGroup) Submit(cam pub_object.Camera, q *render.Queue) {
+	q.AddCmds(g.Render(cam))
+	for _, child := range g.childs {
+		render.Collect(q, cam, child)
+	}
+}
+
+// camera at the origin looking down -z
+type testViewCam struct {
+	testCam
+}
+
+func (c *testViewCam) GetViewProjMatrix() rl.Matrix {
+	view := rl.MatrixLookAt(rl.NewVector3(0, 0, 0), rl.NewVector3(0, 0, -1), rl.NewVector3(0, 1, 0))
+	//90 degree perspective with near 0.1 and far 100
+	proj := rl.Matrix{M0: 1, M5: 1, M10: -100.1 / 99.9, M11: -1, M14: -20 / 99.9}
+	return rl.MatrixMultiply(view, proj)
+}
+
+func newTestScene(t *testing.T) *Scene {
+	s := &Scene{}
+	err := s.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestDeferred(t *testing.T) {
+	s := newTestScene(t)
+	a := &testObj{}
+	b := &testObj{}
+	s.AddChild(a)
+
+	s.DeferAdd(s, b)
+	s.DeferAdd(a, &testObj{})
+	s.DeferRem(s, a)
+	if len(s.GetChilds()) != 1 {
+		t.Error("TestDeferred applied before update")
+	}
+
+	s.Update(0)
+	if childs := s.GetChilds(); len(childs) != 1 || childs[0] != pub_object.Object(b) {
+		t.Error(fmt.Sprintf("TestDeferred childs %d", len(childs)))
+	}
+	if a.GetParent() != nil || b.GetParent() != pub_object.Object(s) || len(a.childs) != 1 {
+		t.Error("TestDeferred parents")
+	}
+}
+
+// logic and render loops sharing a scene, run with -race
+func TestLogicRenderHandOff(t *testing.T) {
+	s := newTestScene(t)
+	root := &testObj{}
+	s.AddChild(root)
+	cam := &testCam{}
+	mu := sync.Mutex{}
+	ticks := 200
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	//logic loop
+	go func() {
+		defer wg.Done()
+		for range ticks {
+			mu.Lock()
+			s.Update(1.0 / 60)
+			mu.Unlock()
+		}
+	}()
+	//gameplay code spawning objects from another goroutine
+	go func() {
+		defer wg.Done()
+		for range ticks {
+			s.DeferAdd(root, &testObj{})
+		}
+	}()
+
+	//render loop
+	for range ticks {
+		mu.Lock()
+		for _, cmd := range s.Render(cam) {
+			cmd()
+		}
+		mu.Unlock()
+	}
+	wg.Wait()
+
+	s.Update(0)
+	if len(root.childs) != ticks {
+		t.Error(fmt.Sprintf("TestLogicRenderHandOff childs %d", len(root.childs)))
+	}
+}
+
+func TestIndex(t *testing.T) {
+	s := newTestScene(t)
+	holder := &testObj{}
+	early := &testBox{pos: rl.NewVector3(0, 0, -10)}
+	holder.AddChild(early)
+	//children added before the holder joins are indexed with it
+	s.AddChild(holder)
+	late := &testBox{pos: rl.NewVector3(10, 0, -10)}
+	holder.AddChild(late)
+
+	near := func(pos rl.Vector3) []pub_object.Object {
+		return s.QuerySphere(pub_object.Sphere{Center: rl.Vector3Add(pos, rl.NewVector3(0.5, 0.5, 0.5)), Radius: 0.5})
+	}
+	var cases = []struct {
+		move func()
+		pos  rl.Vector3
+		want []pub_object.Object
+	}{
+		{func() {}, rl.NewVector3(0, 0, -10), []pub_object.Object{early}},
+		{func() {}, rl.NewVector3(10, 0, -10), []pub_object.Object{late}},
+		{func() { late.pos = rl.NewVector3(20, 0, -10) }, rl.NewVector3(20, 0, -10), []pub_object.Object{late}},
+		{func() {}, rl.NewVector3(10, 0, -10), []pub_object.Object{}},
+		{func() { holder.RemChild(late) }, rl.NewVector3(20, 0, -10), []pub_object.Object{}},
+		{func() { s.RemChild(holder) }, rl.NewVector3(0, 0, -10), []pub_object.Object{}},
+	}
+	for i, c := range cases {
+		c.move()
+		s.Update(0)
+		got := near(c.pos)
+		if !slices.Equal(got, c.want) {
+			t.Error(fmt.Sprintf("TestIndex[%d] expected %d objects, got %d", i, len(c.want), len(got)))
+		}
+	}
+	if s.index.Len() != 0 {
+		t.Error(fmt.Sprintf("TestIndex expected an empty index, got %d", s.index.Len()))
+	}
+}
+
+func TestCull(t *testing.T) {
+	s := newTestScene(t)
+	holder := &testGroup{}
+	s.AddChild(holder)
+	//nested objects are culled as well as top level ones
+	holder.AddChild(&testBox{pos: rl.NewVector3(0, 0, -10)})
+	holder.AddChild(&testBox{pos: rl.NewVector3(0, 0, 10)})
+	s.AddChild(&testBox{pos: rl.NewVector3(0, 0, 10)})
+
+	var cases = []struct {
+		cam  pub_object.Camera
+		want int
+	}{
+		//the holder and the box in front
+		{&testViewCam{}, 2},
+		//cameras without a view projection draw everything
+		{&testCam{}, 4},
+	}
+	for i, c := range cases {
+		q := render.NewQueue()
+		s.Submit(c.cam, q)
+		if q.Len() != c.want || q.Visible != nil {
+			t.Error(fmt.Sprintf("TestCull[%d] expected %d items, got %d", i, c.want, q.Len()))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package scene
+
+import (
+	"fmt"
+	"os"
+
+	"karalis/res"
+
+	pub_scene "karalis/pkg/scene"
+)
+
+func init() {
+	pub_scene.Register("Scene", func(n pub_scene.Node) (*Scene, error) {
+		s := &Scene{}
+		return s, s.Init()
+	})
+	pub_scene.PrefabReader = readPrefab
+}
+
+// read prefab files from the engine resources
+func readPrefab(path string) ([]byte, error) {
+	data, err := res.GetRes(path)
+	if err != nil {
+		return nil, err
+	}
+	buf, ok := data.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("Invalid prefab resource: %s", path)
+	}
+	return buf, nil
+}
+
+// save a scene to a file
+func (s *Scene) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return pub_scene.Save(file, s)
+}
+
+// load a scene from a file
+func Load(path string) (*Scene, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	obj, err := pub_scene.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := obj.(*Scene)
+	if !ok {
+		return nil, fmt.Errorf("Scene file root is not a scene: %T", obj)
+	}
+	return s, nil
+}
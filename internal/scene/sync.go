@@ -0,0 +1,62 @@
+package scene
+
+import (
+	"sync"
+
+	pub_object "karalis/pkg/object"
+)
+
+// tree change requested while the scene may be being walked
+type change struct {
+	parent pub_object.Object
+	child  pub_object.Object
+	add    bool
+}
+
+type pending struct {
+	mu      sync.Mutex
+	changes []change
+}
+
+// queue adding child to parent at the end of the next update
+// safe to call from any goroutine and while iterating the tree
+func (s *Scene) DeferAdd(parent pub_object.Object, child pub_object.Object) {
+	if s == nil || parent == nil || child == nil {
+		return
+	}
+
+	s.pend.mu.Lock()
+	s.pend.changes = append(s.pend.changes, change{parent: parent, child: child, add: true})
+	s.pend.mu.Unlock()
+}
+
+// queue removing child from parent at the end of the next update
+func (s *Scene) DeferRem(parent pub_object.Object, child pub_object.Object) {
+	if s == nil || parent == nil || child == nil {
+		return
+	}
+
+	s.pend.mu.Lock()
+	s.pend.changes = append(s.pend.changes, change{parent: parent, child: child})
+	s.pend.mu.Unlock()
+}
+
+// apply queued tree changes in the order they were requested
+func (s *Scene) ApplyDeferred() {
+	if s == nil {
+		return
+	}
+
+	s.pend.mu.Lock()
+	changes := s.pend.changes
+	s.pend.changes = nil
+	s.pend.mu.Unlock()
+
+	for _, c := range changes {
+		if c.add {
+			c.parent.AddChild(c.child)
+		} else {
+			c.parent.RemChild(c.child)
+		}
+	}
+}
@@ -11,7 +11,7 @@ import (
 )
 
 var (
-	computes = map[string]Compute{}
+	computes = map[string]*Compute{}
 )
 
 type Compute struct {
@@ -21,12 +21,12 @@ type Compute struct {
 	cleaner *runtime.Cleanup
 }
 
-func NewCompute(shader string) (Compute, error) {
+func NewCompute(shader string) (*Compute, error) {
 	if c, ok := computes[shader]; ok {
 		return c, nil
 	}
 
-	c := Compute{}
+	c := &Compute{}
 	err := c.init(shader)
 	computes[shader] = c
 
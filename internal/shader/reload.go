@@ -0,0 +1,120 @@
+package shader
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"karalis/internal/rlx"
+	"karalis/pkg/event"
+	"karalis/res"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	// extensions of the stages a Shader is built from
+	stageExts = []string{".vert", ".frag", ".geom", ".ctrl", ".eval"}
+)
+
+// recompile the programs built from a changed source
+func init() {
+	event.Subscribe(res.Changes, func(c res.Changed) {
+		err := Reload(c.Path)
+		if err != nil {
+			logger.Error("reload", "path", c.Path, "err", err)
+		}
+	})
+}
+
+// recompile every shader and compute program using the source at path
+// programs failing to build are kept as they were and the first error is returned
+func Reload(src string) error {
+	var first error
+	ext := path.Ext(src)
+	if slices.Contains(stageExts, ext) {
+		for _, sh := range shaders {
+			s, ok := sh.(*Shader)
+			if !ok || !s.uses(src) {
+				continue
+			}
+			err := s.reload()
+			if err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	if ext == ".comp" {
+		for _, c := range computes {
+			if "shader/"+c.name+".comp" != src {
+				continue
+			}
+			err := c.reload()
+			if err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// whether the shader was built from the source at path
+func (s *Shader) uses(path string) bool {
+	if s == nil {
+		return false
+	}
+
+	return slices.Contains([]string{s.vsname, s.fsname, s.gsname, s.csname, s.esname}, path)
+}
+
+// build the current variant again from its sources
+// other variants are unloaded and built again when next selected
+func (s *Shader) reload() error {
+	if s == nil {
+		return fmt.Errorf("Invalid shader")
+	}
+
+	key := s.shaderKey()
+	old := s.shader
+	variants := s.shaders
+	s.shaders = map[string]*rl.Shader{}
+	err := s.genShader()
+	if err == nil && (s.shader == nil || s.shader.ID == rlx.GetShaderIdDefault()) {
+		err = fmt.Errorf("Shader failed to compile, keeping the previous one: %s", s.name)
+	}
+	if err != nil {
+		s.shaders = variants
+		s.shader = old
+		return err
+	}
+
+	if old != nil {
+		rlx.ReplaceShader(*old, *s.shader)
+	}
+	s.shaders[key] = s.shader
+	//the old current program stays loaded for materials remapped off it, the other variants are built again when needed
+	for _, variant := range variants {
+		if variant == nil || (old != nil && variant.ID == old.ID) || variant.ID == s.shader.ID {
+			continue
+		}
+		rlx.UnloadShader(*variant)
+	}
+	return nil
+}
+
+// build the program again, the current one is kept if the new one fails
+func (c *Compute) reload() error {
+	if c == nil {
+		return fmt.Errorf("Invalid shader")
+	}
+
+	old := c.id
+	err := c.genShader()
+	if err != nil {
+		return fmt.Errorf("Compute shader %s: %+v, keeping the previous one", c.name, err)
+	}
+	if old != 0 && old != c.id {
+		rlx.UnloadShaderProgram(old)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"karalis/internal/rlx"
+	"karalis/pkg/logging"
 	"karalis/pkg/shader"
 	"karalis/res"
 
@@ -15,6 +16,8 @@ import (
 
 var (
 	shaders = map[string]shader.Shader{}
+
+	logger = logging.For(logging.Shader)
 )
 
 type Shader struct {
@@ -351,5 +354,9 @@ func (s *Shader) OnRemove() error {
 		rlx.UnloadShader(*s.shader)
 		s.shader = nil
 	}
+	//a later NewShader loads it again instead of getting the unloaded one
+	if cur, ok := shaders[s.name]; ok && cur == shader.Shader(s) {
+		delete(shaders, s.name)
+	}
 	return nil
 }
@@ -0,0 +1,52 @@
+package stage
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"karalis/internal/rlx"
+)
+
+var (
+	ErrNoGame = errors.New("No game running")
+)
+
+// read back the last frame drawn to the games render texture
+func (g *Game) Capture() (*image.RGBA, error) {
+	if g == nil {
+		return nil, fmt.Errorf("Invalid stage")
+	}
+
+	rlx.Lock(&g.mu)
+	img := rlx.LoadImageFromTexture(g.rt.Texture())
+	g.mu.Unlock()
+	if img == nil || img.Width == 0 || img.Height == 0 {
+		return nil, fmt.Errorf("Nothing to capture")
+	}
+	defer rlx.UnloadImage(img)
+
+	//render textures are stored bottom up
+	rlx.ImageFlipVertical(img)
+	colors := rlx.LoadImageColors(img)
+	defer rlx.UnloadImageColors(colors)
+
+	//the scene leaves alpha undefined so captures are made opaque
+	out := image.NewRGBA(image.Rect(0, 0, int(img.Width), int(img.Height)))
+	for i, c := range colors {
+		out.Pix[i*4+0] = c.R
+		out.Pix[i*4+1] = c.G
+		out.Pix[i*4+2] = c.B
+		out.Pix[i*4+3] = 255
+	}
+	return out, nil
+}
+
+// capture the running game, failing while none has finished loading
+func CaptureGame() (*image.RGBA, error) {
+	g := currentGame()
+	if g == nil {
+		return nil, ErrNoGame
+	}
+	return g.Capture()
+}
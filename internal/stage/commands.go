@@ -0,0 +1,77 @@
+package stage
+
+import (
+	"fmt"
+
+	"karalis/internal/object/world"
+	"karalis/internal/rlx"
+	"karalis/internal/scene"
+	"karalis/pkg/app"
+	"karalis/pkg/console"
+)
+
+// console commands and variables reaching into the running game
+func init() {
+	console.RegisterVar(console.Int64Var("g_seed", "world seed, changing it regenerates the world",
+		func() int64 { return world.Seed },
+		func(seed int64) error {
+			world.Seed = seed
+			g := currentGame()
+			if g == nil {
+				return nil
+			}
+			rlx.Lock(&g.mu)
+			defer g.mu.Unlock()
+			for _, w := range scene.OfType[*world.World](g.scene) {
+				w.SetSeed(seed)
+			}
+			return nil
+		}))
+	console.Register(console.Command{
+		Name: "scene_save",
+		Args: "<file>",
+		Help: "save the current scene",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Usage: scene_save <file>")
+			}
+			g := currentGame()
+			if g == nil {
+				return ErrNoGame
+			}
+			return g.SaveScene(args[0])
+		},
+	})
+	console.Register(console.Command{
+		Name: "scene_load",
+		Args: "<file>",
+		Help: "replace the current scene with a saved one",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Usage: scene_load <file>")
+			}
+			g := currentGame()
+			if g == nil {
+				return ErrNoGame
+			}
+			return g.LoadScene(args[0])
+		},
+	})
+}
+
+// get the game in the apps stack, nil while none has finished loading
+func currentGame() *Game {
+	if app.CurApp == nil {
+		return nil
+	}
+	stack, ok := app.CurApp.GetStage().(*Stack)
+	if !ok {
+		return nil
+	}
+	for _, s := range stack.GetStages() {
+		if g, ok := s.(*Game); ok && g.scene != nil {
+			return g
+		}
+	}
+	return nil
+}
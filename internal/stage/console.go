@@ -0,0 +1,259 @@
+package stage
+
+import (
+	"fmt"
+	"strings"
+
+	"karalis/internal/rlx"
+	"karalis/pkg/app"
+	"karalis/pkg/console"
+	"karalis/pkg/input"
+
+	pub_object "karalis/pkg/object"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var ()
+
+const (
+	consoleFontSize = 20
+	consoleLineGap  = 2
+	// part of the screen the console drops down over
+	consoleHeight = 0.45
+)
+
+// drop-down developer console pushed over the other stages
+// stages below keep updating while it is open but input goes to the console
+type Console struct {
+	con *console.Console
+
+	line   []rune
+	hist   int
+	draft  []rune
+	scroll int
+
+	//completions cycled through by tab
+	matches []string
+	match   int
+
+	time float32
+}
+
+func NewConsole(con *console.Console) *Console {
+	c := &Console{con: con}
+	c.Init()
+	return c
+}
+
+// register the action toggling the console
+func RegisterConsole() error {
+	toggle := func() {
+		if _, ok := app.CurApp.GetTopStage().(*Console); ok {
+			app.CurApp.PopStage(nil)
+		} else {
+			app.CurApp.PushStage(NewConsole(console.Default), nil)
+		}
+	}
+	return input.RegisterAction("ToggleConsole", &toggle, nil, true)
+}
+
+func (c *Console) Init() error {
+	if c == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+
+	c.line = []rune{}
+	c.hist = -1
+	c.scroll = 0
+	c.resetMatches()
+	return nil
+}
+
+// the stages below keep running
+func (c *Console) PausesBelow() bool {
+	if c == nil {
+		return false
+	}
+
+	return false
+}
+
+func (c *Console) OnResize(w int32, h int32) {
+	if c == nil {
+		return
+	}
+}
+
+// draw the scrollback above the line being typed
+func (c *Console) Render(alpha float32) {
+	if c == nil {
+		return
+	}
+
+	w := int32(rlx.GetScreenWidth())
+	h := int32(float32(rlx.GetScreenHeight()) * consoleHeight)
+	step := int32(consoleFontSize + consoleLineGap)
+	rlx.DrawRectangle(0, 0, w, h, rlx.Fade(rl.Black, 0.8))
+	rlx.DrawRectangle(0, h-step-4, w, 1, rl.Gray)
+
+	cursor := ""
+	if int(c.time*2)%2 == 0 {
+		cursor = "_"
+	}
+	rlx.DrawText("> "+string(c.line)+cursor, 6, h-step, consoleFontSize, rl.RayWhite)
+
+	lines := c.con.GetLines()
+	end := len(lines) - c.scroll
+	y := h - 2*step - 4
+	for i := end - 1; i >= 0 && y > -step; i-- {
+		col := rl.LightGray
+		if strings.HasPrefix(lines[i], "ERR") {
+			col = rl.Red
+		}
+		rlx.DrawText(lines[i], 6, y, consoleFontSize, col)
+		y -= step
+	}
+}
+
+func (c *Console) Update(dt float32) {
+	if c == nil {
+		return
+	}
+
+	c.time += dt
+}
+
+// type into the console, keys already handled by bindings such as the toggle are not typed
+func (c *Console) OnInput(dt float32) {
+	if c == nil {
+		return
+	}
+
+	input.HandleInput("Console")
+	for ch := rlx.GetCharPressed(); ch > 0; ch = rlx.GetCharPressed() {
+		if ch == '`' || ch == '~' {
+			continue
+		}
+		c.line = append(c.line, rune(ch))
+		c.resetMatches()
+	}
+
+	for key := rlx.GetKeyPressed(); key > 0; key = rlx.GetKeyPressed() {
+		switch key {
+		case rl.KeyEnter, rl.KeyKpEnter:
+			c.con.Submit(string(c.line))
+			c.line = []rune{}
+			c.hist = -1
+			c.scroll = 0
+			c.resetMatches()
+		case rl.KeyBackspace:
+			if len(c.line) > 0 {
+				c.line = c.line[:len(c.line)-1]
+			}
+			c.resetMatches()
+		case rl.KeyUp:
+			c.browse(-1)
+		case rl.KeyDown:
+			c.browse(1)
+		case rl.KeyTab:
+			c.complete()
+		case rl.KeyPageUp:
+			c.scroll = min(c.scroll+5, max(len(c.con.GetLines())-1, 0))
+		case rl.KeyPageDown:
+			c.scroll = max(c.scroll-5, 0)
+		}
+	}
+}
+
+// step through the lines entered before, dir -1 goes back
+func (c *Console) browse(dir int) {
+	history := c.con.GetHistory()
+	if len(history) == 0 {
+		return
+	}
+
+	if c.hist < 0 {
+		if dir > 0 {
+			return
+		}
+		c.draft = c.line
+		c.hist = len(history)
+	}
+	c.hist += dir
+	switch {
+	case c.hist < 0:
+		c.hist = 0
+	case c.hist >= len(history):
+		c.hist = -1
+		c.line = c.draft
+		return
+	}
+	c.line = []rune(history[c.hist])
+	c.resetMatches()
+}
+
+// complete the line, pressing again cycles through the other matches
+func (c *Console) complete() {
+	if c.matches == nil {
+		c.matches = c.con.Complete(string(c.line))
+		c.match = -1
+		if len(c.matches) > 1 {
+			c.con.Printf("%s\n", strings.Join(c.matches, "  "))
+		}
+	}
+	if len(c.matches) == 0 {
+		return
+	}
+
+	//a single match is taken so the next tab completes its args
+	if len(c.matches) == 1 {
+		c.line = []rune(c.matches[0] + " ")
+		c.resetMatches()
+		return
+	}
+	c.match = (c.match + 1) % len(c.matches)
+	c.line = []rune(c.matches[c.match])
+}
+
+func (c *Console) resetMatches() {
+	c.matches = nil
+	c.match = -1
+}
+
+// drop keys typed while the console was closed and free the mouse
+func (c *Console) OnAdd() {
+	if c == nil {
+		return
+	}
+
+	for rlx.GetCharPressed() > 0 {
+	}
+	for rlx.GetKeyPressed() > 0 {
+	}
+	if ply, ok := app.CurApp.GetStage().GetPlayer().(mouseReleaser); ok {
+		ply.ReleaseMouse()
+	}
+}
+
+func (c *Console) OnRemove() {
+	if c == nil {
+		return
+	}
+}
+
+func (c *Console) GetPlayer() pub_object.Object {
+	if c == nil {
+		return nil
+	}
+
+	return nil
+}
+
+func (c *Console) GetCurrentScene() pub_object.Object {
+	if c == nil {
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package stage
+
+import (
+	"fmt"
+	"time"
+
+	"karalis/internal/object/world"
+	"karalis/internal/scene"
+	"karalis/pkg/crash"
+)
+
+const (
+	// how long a crash report waits for the game lock before leaving the game state out
+	crashLockWait = 100 * time.Millisecond
+)
+
+// fill in the stages, player position and world seed of a crash report
+// the game lock is only waited on briefly as the other loop may be stuck holding it
+func FillReport(r *crash.Report, s *Stack) {
+	if r == nil || s == nil {
+		return
+	}
+
+	r.Seed = world.Seed
+	var g *Game
+	for _, st := range s.GetStages() {
+		r.Stages = append(r.Stages, fmt.Sprintf("%T", st))
+		if game, ok := st.(*Game); ok && game.scene != nil {
+			g = game
+		}
+	}
+	if g == nil {
+		return
+	}
+	deadline := time.Now().Add(crashLockWait)
+	for !g.mu.TryLock() {
+		if time.Now().After(deadline) {
+			r.Player = "unknown, game busy"
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	defer g.mu.Unlock()
+	if g.player != nil {
+		pos := g.player.GetPos()
+		r.Player = fmt.Sprintf("(%.2f, %.2f, %.2f)", pos.X, pos.Y, pos.Z)
+	}
+	for _, w := range scene.OfType[*world.World](g.scene) {
+		r.Seed = w.GetSeed()
+		break
+	}
+}
@@ -2,25 +2,64 @@ package stage
 
 import (
 	"fmt"
+	"sync"
 
 	"karalis/internal/object/character"
-	"karalis/internal/object/prim"
+	_ "karalis/internal/object/prim"
 	"karalis/internal/object/world"
 	"karalis/internal/rlx"
 	"karalis/internal/scene"
+	"karalis/internal/target"
+	"karalis/pkg/event"
+	"karalis/pkg/input"
+	"karalis/pkg/logging"
+	"karalis/pkg/render"
+	"karalis/pkg/save"
+	"karalis/pkg/settings"
+	"karalis/res"
 
 	pub_object "karalis/pkg/object"
+	pub_scene "karalis/pkg/scene"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-var ()
+var (
+	logger = logging.For(logging.App)
+)
+
+// cameras drawn between logic ticks
+type lerpCamera interface {
+	Lerp(alpha float32)
+}
 
+// the scene is owned by whichever loop holds mu
+// the logic loop takes it for a whole tick and the render loop for a whole frame
+// there is no render snapshot, objects keep their draw state in gpu handles and models a copy could not share,
+// and holding mu for a tick stays cheap: ticks are short next to a frame, the render loop blends the saved
+// previous tick instead of waiting for the next one, and rlx.Lock serves gpu calls from the tick while it waits
 type Game struct {
+	//world kind and seed to start in, empty and 0 keep the ones in the world prefab
+	World string
+	Seed  int64
+
+	//saved game to rebuild instead of starting a new one
+	Save *save.State
+
+	//replay to play back and whether to record, picked up on the next tick
+	Replay *input.Replay
+	Record bool
+
+	mu     sync.Mutex
 	scene  *scene.Scene
 	player *character.Player
-	rt     rl.RenderTexture2D
-	resize bool
+	queue  *render.Queue
+	rt     *target.Target
+
+	//how far Init has come, read by the loading stage
+	progress progress
+
+	settings *event.Subscription
 }
 
 // initialize game object
@@ -28,10 +67,25 @@ func (g *Game) Init() error {
 	if g == nil {
 		return fmt.Errorf("Invalid stage")
 	}
-	g.rt = rlx.LoadRenderTexture(int32(rlx.GetRenderWidth()), int32(rlx.GetRenderHeight()))
+	g.progress.set(0)
+	rt, err := target.Acquire(target.Options{})
+	if err != nil {
+		return err
+	}
+	g.rt = rt
+	g.queue = render.NewQueue()
+
+	err = registerPause()
+	if err != nil {
+		return err
+	}
+
+	if g.Save != nil {
+		return g.restore(g.Save)
+	}
 
 	g.scene = &scene.Scene{}
-	err := g.scene.Init()
+	err = g.scene.Init()
 	if err != nil {
 		return err
 	}
@@ -42,29 +96,100 @@ func (g *Game) Init() error {
 		return err
 	}
 	g.scene.AddChild(g.player)
+	g.applySettings(settings.Get())
+	g.progress.set(0.2)
 
-	grid, err := prim.NewGrid()
-	if err != nil {
-		return err
+	world := map[string]interface{}{}
+	if g.World != "" {
+		world["props"] = map[string]interface{}{"kind": g.World}
+	}
+	if g.Seed != 0 {
+		world["seed"] = g.Seed
+	}
+	prefabs := []struct {
+		path      string
+		overrides map[string]interface{}
+	}{
+		{"prefab/grid.json", nil},
+		{"prefab/terrain_world.json", world},
+	}
+	for i, prefab := range prefabs {
+		_, err = pub_scene.InstantiateWith(prefab.path, g.scene, prefab.overrides)
+		if err != nil {
+			return err
+		}
+		g.progress.set(0.2 + 0.8*float32(i+1)/float32(len(prefabs)))
+	}
+
+	return nil
+}
+
+// get how far Init has come from 0 to 1
+func (g *Game) GetProgress() float32 {
+	if g == nil {
+		return 0
 	}
-	g.scene.AddChild(grid)
 
-	w, err := world.NewTerrainWorld()
+	return g.progress.get()
+}
+
+// save the current scene to a file
+func (g *Game) SaveScene(path string) error {
+	if g == nil || g.scene == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+	return g.scene.Save(path)
+}
+
+// replace the current scene with one loaded from a file
+func (g *Game) LoadScene(path string) error {
+	if g == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+
+	s, err := scene.Load(path)
 	if err != nil {
 		return err
 	}
-	g.scene.AddChild(w)
+
+	players := scene.OfType[*character.Player](s)
+	if len(players) == 0 {
+		s.OnRemove()
+		return fmt.Errorf("Scene has no player: %s", path)
+	}
+	player := players[0]
+
+	s.OnAdd(nil)
+	rlx.Lock(&g.mu)
+	g.setScene(s, player)
+	g.mu.Unlock()
 
 	return nil
 }
 
+// swap in a scene and its player, removing the old scene so its objects release what they hold
+// callers hold mu once the game is added
+func (g *Game) setScene(s *scene.Scene, player *character.Player) {
+	old := g.scene
+	g.scene = s
+	g.player = player
+	if old != nil && old != s {
+		old.OnRemove()
+	}
+}
+
 // handle resize event
 func (g *Game) OnResize(w int32, h int32) {
 	if g == nil {
 		return
 	}
 
-	g.resize = true
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+
 	if g.player != nil {
 		g.player.GetCam().OnResize(w, h)
 	}
@@ -73,21 +198,24 @@ func (g *Game) OnResize(w int32, h int32) {
 	}
 }
 
-// render hook
-func (g *Game) Render() {
+// render hook, alpha is how far the frame is between the previous and current tick
+func (g *Game) Render(alpha float32) {
 	if g == nil {
 		return
 	}
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+
 	cam := g.player.GetCam()
 	if cam == nil {
 		return
 	}
-	if g.resize {
-		rlx.UnloadRenderTexture(g.rt)
-		g.rt = rlx.LoadRenderTexture(int32(rlx.GetRenderWidth()), int32(rlx.GetRenderHeight()))
-		g.resize = false
-	}
+	//the target is resized by the manager
+	rt := g.rt.RenderTexture()
 	cam.UpdateCam()
+	if lc, ok := cam.(lerpCamera); ok {
+		lc.Lerp(alpha)
+	}
 
 	cmds := []func(){}
 
@@ -96,41 +224,59 @@ func (g *Game) Render() {
 		cmd()
 	}
 
-	rlx.BeginTextureMode(g.rt)
+	rlx.BeginTextureMode(rt)
 	rlx.ClearBackground(rl.Black)
-	cmds = cam.Render()
-	cmds = append(g.scene.Render(cam), cmds...)
+	g.queue.Reset()
+	g.queue.Alpha = alpha
+	g.scene.Submit(cam, g.queue)
+	cmds = append(g.queue.Cmds(cam.GetPos()), cam.Render()...)
 	for _, cmd := range cmds {
 		cmd()
 	}
 	rlx.EndTextureMode()
 
 	cmds = append(cam.Postrender(), g.scene.Postrender(cam)...)
-	rlx.BeginTextureMode(g.rt)
+	rlx.BeginTextureMode(rt)
 	for _, cmd := range cmds {
 		cmd()
 	}
 	rlx.EndTextureMode()
-	rlx.DrawTexturePro(g.rt.Texture, rl.Rectangle{0, 0, float32(g.rt.Texture.Width), -float32(g.rt.Texture.Height)}, rl.Rectangle{0, 0, float32(g.rt.Texture.Width), float32(g.rt.Texture.Height)}, rl.Vector2{0, 0}, 0.0, rl.RayWhite)
+	rlx.DrawTexturePro(rt.Texture, rl.Rectangle{0, 0, float32(rt.Texture.Width), -float32(rt.Texture.Height)}, rl.Rectangle{0, 0, float32(rt.Texture.Width), float32(rt.Texture.Height)}, rl.Vector2{0, 0}, 0.0, rl.RayWhite)
 
 	return
 }
 
 // handle update cycle
 func (g *Game) Update(dt float32) {
-	if g == nil || g.scene == nil {
+	if g == nil {
 		return
 	}
 
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+	if g.scene == nil {
+		return
+	}
+	//resources changed on disk are swapped in while nothing draws
+	res.Changes.Flush()
+	err := g.startReplay()
+	if err != nil {
+		logger.Error("start replay", "err", err)
+	}
 	g.scene.Update(dt)
 }
 
 // handle player input
 func (g *Game) OnInput(dt float32) {
-	if g == nil || g.scene == nil {
+	if g == nil {
 		return
 	}
 
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+	if g.scene == nil {
+		return
+	}
 	g.player.OnInput(dt)
 }
 
@@ -139,13 +285,43 @@ func (g *Game) OnAdd() {
 	if g == nil || g.scene == nil {
 		return
 	}
+
+	g.settings = event.On(func(c settings.Changed) {
+		rlx.Lock(&g.mu)
+		defer g.mu.Unlock()
+		g.applySettings(c.Next)
+	})
 }
 
 // handle remove event
 func (g *Game) OnRemove() {
-	if g == nil || g.scene == nil {
+	if g == nil {
 		return
 	}
+	//back to the pool for the next game
+	g.rt.Release()
+	g.rt = nil
+	g.settings.Cancel()
+	g.settings = nil
+
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+	g.setScene(nil, nil)
+}
+
+// apply the settings the world and player read, callers hold mu once the game is added
+func (g *Game) applySettings(s settings.Settings) {
+	world.CellRender = s.CellRender
+	character.MouseSensitivity = s.Sensitivity
+	if g.player != nil {
+		g.player.GetCam().SetFovy(s.FOV)
+	}
+	if world.GrassLOD != s.GrassLOD {
+		world.GrassLOD = s.GrassLOD
+		for _, w := range scene.OfType[*world.World](g.scene) {
+			w.RebuildGrass()
+		}
+	}
 }
 
 // get the player object
@@ -159,7 +335,7 @@ func (g *Game) GetPlayer() pub_object.Object {
 
 // get currently active scene (where player is at)
 func (g *Game) GetCurrentScene() pub_object.Object {
-	if g == nil {
+	if g == nil || g.scene == nil {
 		return nil
 	}
 
@@ -0,0 +1,141 @@
+package stage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"karalis/internal/object/character"
+	"karalis/internal/object/prim"
+	"karalis/internal/object/world"
+	"karalis/internal/rlx"
+	"karalis/internal/scene"
+	"karalis/internal/target"
+	"karalis/pkg/input"
+	"karalis/pkg/render"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSceneRoundTrip(t *testing.T) {
+	s := &scene.Scene{}
+	err := s.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.OnAdd(nil)
+
+	player, err := character.NewPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.AddChild(player)
+	pose := input.Pose{Pos: rl.NewVector3(3, 4, 5), Yaw: 30, Pitch: -10, Dist: 5}
+	player.SetPose(pose)
+	grid, err := prim.NewGrid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.AddChild(grid)
+	w, err := world.NewTerrainWorld()
+	if err != nil {
+		t.Fatal(err)
+	}
+	//a seed of 0 is a seed like any other
+	w.SetSeed(0)
+	s.AddChild(w)
+
+	g := &Game{scene: s, player: player}
+	path := filepath.Join(t.TempDir(), "scene.json")
+	err = g.SaveScene(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the replaced scene is removed along with what it holds
+	released := false
+	grid.GetMeta().OnRelease(func() { released = true })
+	err = g.LoadScene(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !released {
+		t.Error("TestSceneRoundTrip expected the old scene released")
+	}
+
+	got := g.player.GetPose()
+	if got != pose || g.player.GetPos() != pose.Pos {
+		t.Error(fmt.Sprintf("TestSceneRoundTrip expected player pose %+v, got %+v at %v", pose, got, g.player.GetPos()))
+	}
+	if n := len(scene.OfType[*prim.Grid](g.scene)); n != 1 {
+		t.Error(fmt.Sprintf("TestSceneRoundTrip expected one grid, got %d", n))
+	}
+	worlds := scene.OfType[*world.World](g.scene)
+	if len(worlds) != 1 || worlds[0].GetSeed() != 0 || worlds[0].GetKind() != "terrain" {
+		t.Error(fmt.Sprintf("TestSceneRoundTrip expected a terrain world with seed 0, got %d worlds", len(worlds)))
+	}
+}
+
+// the logic loop ticking a game while the render loop draws it, run with -race
+func TestGameHandOff(t *testing.T) {
+	rlx.InitWindow(320, 200, "test")
+	defer rlx.CloseWindow()
+
+	rt, err := target.Acquire(target.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Release()
+	g := &Game{queue: render.NewQueue(), rt: rt}
+	g.scene = &scene.Scene{}
+	err = g.scene.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.scene.OnAdd(nil)
+	g.player, err = character.NewPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.scene.AddChild(g.player)
+	sky, err := world.NewSkybox(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.scene.AddChild(sky)
+	ticks := 100
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	//logic loop
+	go func() {
+		defer wg.Done()
+		for range ticks {
+			g.OnInput(1.0 / 60)
+			g.Update(1.0 / 60)
+		}
+	}()
+	//gameplay code spawning objects from another goroutine
+	go func() {
+		defer wg.Done()
+		for range ticks {
+			grid, err := prim.NewGrid()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			g.scene.DeferAdd(g.scene, grid)
+		}
+	}()
+
+	//render loop
+	for i := range ticks {
+		g.Render(float32(i%4) / 4)
+	}
+	wg.Wait()
+
+	g.Update(0)
+	if n := len(scene.OfType[*prim.Grid](g.scene)); n != ticks {
+		t.Error(fmt.Sprintf("TestGameHandOff expected %d grids, got %d", ticks, n))
+	}
+}
@@ -0,0 +1,221 @@
+package stage
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"karalis/internal/rlx"
+	"karalis/pkg/console"
+
+	pub_object "karalis/pkg/object"
+	pub_stage "karalis/pkg/stage"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var ()
+
+// progress shared between a loading goroutine and the frames showing it
+type progress struct {
+	bits atomic.Uint32
+}
+
+func (p *progress) set(v float32) {
+	p.bits.Store(math.Float32bits(v))
+}
+
+func (p *progress) get() float32 {
+	return math.Float32frombits(p.bits.Load())
+}
+
+// shown while the next stage initializes off the main thread, then replaced by it
+type Loading struct {
+	stack *Stack
+	next  pub_stage.Stage
+	tr    pub_stage.Transition
+
+	start sync.Once
+	done  chan error
+	err   error
+	time  float32
+
+	//next was handed to the stack or released, either way it is no longer ours
+	settled bool
+	//a key was pressed to leave after a failed load
+	leaving bool
+}
+
+func NewLoading(stack *Stack, next pub_stage.Stage, tr pub_stage.Transition) *Loading {
+	l := &Loading{
+		stack: stack,
+		next:  next,
+		tr:    tr,
+	}
+	l.Init()
+	return l
+}
+
+func (l *Loading) Init() error {
+	if l == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+
+	l.done = make(chan error, 1)
+	return nil
+}
+
+// get how far the next stage has loaded, -1 when it does not report progress
+func (l *Loading) GetProgress() float32 {
+	if l == nil {
+		return 0
+	}
+
+	if p, ok := l.next.(pub_stage.Progress); ok {
+		return p.GetProgress()
+	}
+	return -1
+}
+
+// get the error the next stage failed to load with
+func (l *Loading) GetError() error {
+	if l == nil {
+		return nil
+	}
+
+	return l.err
+}
+
+func (l *Loading) OnResize(w int32, h int32) {
+	if l == nil {
+		return
+	}
+}
+
+// draw a progress bar, bouncing when the next stage does not report progress
+func (l *Loading) Render(alpha float32) {
+	if l == nil {
+		return
+	}
+
+	w := int32(rlx.GetScreenWidth())
+	h := int32(rlx.GetScreenHeight())
+	if l.err != nil {
+		rlx.DrawText(l.err.Error(), 20, h/2, 20, rl.Red)
+		rlx.DrawText("Press any key to go back", 20, h/2+30, 20, rl.RayWhite)
+		return
+	}
+
+	barW := w / 2
+	barH := int32(16)
+	x := (w - barW) / 2
+	y := h/2 - barH/2
+	rlx.DrawText("Loading", x, y-30, 20, rl.RayWhite)
+	rlx.DrawRectangle(x, y, barW, barH, rl.DarkGray)
+
+	p := l.GetProgress()
+	if p < 0 {
+		pos := (float32(math.Sin(float64(l.time)*3)) + 1) / 2
+		rlx.DrawRectangle(x+int32(pos*float32(barW-barW/4)), y, barW/4, barH, rl.RayWhite)
+		return
+	}
+	rlx.DrawRectangle(x, y, int32(min(max(p, 0), 1)*float32(barW)), barH, rl.RayWhite)
+}
+
+// swap in the next stage once its Init has finished
+func (l *Loading) Update(dt float32) {
+	if l == nil {
+		return
+	}
+
+	l.time += dt
+	select {
+	case err := <-l.done:
+		l.settled = true
+		if err != nil {
+			l.err = err
+			logger.Error("load stage", "stage", fmt.Sprintf("%T", l.next), "err", err)
+			//whatever Init got to before failing is let go
+			l.next.OnRemove()
+			return
+		}
+		l.stack.replace(l, l.next, l.tr)
+	default:
+	}
+}
+
+// leave a failed load on any key
+func (l *Loading) OnInput(dt float32) {
+	if l == nil || l.err == nil || l.leaving {
+		return
+	}
+
+	if rlx.GetKeyPressed() != 0 {
+		l.leaving = true
+		l.back()
+	}
+}
+
+// go back to the stage below, quitting when there is none
+func (l *Loading) back() {
+	if len(l.stack.GetStages()) > 1 {
+		l.stack.Pop(nil)
+		return
+	}
+	err := console.Exec("quit")
+	if err != nil {
+		logger.Error("quit after failed load", "err", err)
+	}
+}
+
+// start initializing the next stage
+func (l *Loading) OnAdd() {
+	if l == nil || l.next == nil {
+		return
+	}
+
+	l.start.Do(func() {
+		go func() {
+			l.done <- l.next.Init()
+		}()
+	})
+}
+
+// release the next stage unless it was swapped in, waiting for its Init when still loading
+func (l *Loading) OnRemove() {
+	if l == nil || l.next == nil || l.settled {
+		return
+	}
+
+	l.settled = true
+	//a load never started has nothing to wait for
+	l.start.Do(func() {
+		l.done <- nil
+	})
+	select {
+	case <-l.done:
+		l.next.OnRemove()
+	default:
+		go func() {
+			<-l.done
+			l.next.OnRemove()
+		}()
+	}
+}
+
+func (l *Loading) GetPlayer() pub_object.Object {
+	if l == nil {
+		return nil
+	}
+
+	return nil
+}
+
+func (l *Loading) GetCurrentScene() pub_object.Object {
+	if l == nil {
+		return nil
+	}
+
+	return nil
+}
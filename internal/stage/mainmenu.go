@@ -24,7 +24,7 @@ func (s *MainMenu) OnResize(w int32, h int32) {
 	}
 }
 
-func (s *MainMenu) Render() {
+func (s *MainMenu) Render(alpha float32) {
 	if s == nil {
 		return
 	}
@@ -0,0 +1,125 @@
+package stage
+
+import (
+	"fmt"
+
+	"karalis/internal/rlx"
+	"karalis/pkg/app"
+	"karalis/pkg/input"
+
+	pub_object "karalis/pkg/object"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var ()
+
+// players that can hand the mouse back for menus
+type mouseReleaser interface {
+	ReleaseMouse()
+}
+
+// pause menu pushed over the game, the game keeps drawing underneath but stops updating
+type Pause struct{}
+
+func NewPause() *Pause {
+	p := &Pause{}
+	p.Init()
+	return p
+}
+
+// register the action toggling the pause menu
+func registerPause() error {
+	toggle := func() {
+		if _, ok := app.CurApp.GetTopStage().(*Pause); ok {
+			app.CurApp.PopStage(nil)
+		} else {
+			app.CurApp.PushStage(NewPause(), nil)
+		}
+	}
+	return input.RegisterAction("Pause", &toggle, nil, true)
+}
+
+func (p *Pause) Init() error {
+	if p == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+
+	return nil
+}
+
+// stages below stop updating while paused
+func (p *Pause) PausesBelow() bool {
+	if p == nil {
+		return false
+	}
+
+	return true
+}
+
+func (p *Pause) OnResize(w int32, h int32) {
+	if p == nil {
+		return
+	}
+}
+
+// dim the stages below and show the menu title
+func (p *Pause) Render(alpha float32) {
+	if p == nil {
+		return
+	}
+
+	w := int32(rlx.GetScreenWidth())
+	h := int32(rlx.GetScreenHeight())
+	rlx.DrawRectangle(0, 0, w, h, rlx.Fade(rl.Black, 0.5))
+	text := "Paused"
+	size := int32(40)
+	rlx.DrawText(text, (w-rlx.MeasureText(text, size))/2, h/2-size/2, size, rl.RayWhite)
+}
+
+func (p *Pause) Update(dt float32) {
+	if p == nil {
+		return
+	}
+}
+
+func (p *Pause) OnInput(dt float32) {
+	if p == nil {
+		return
+	}
+
+	input.HandleInput("Menu")
+}
+
+// free the mouse for the menu
+func (p *Pause) OnAdd() {
+	if p == nil {
+		return
+	}
+
+	if ply, ok := app.CurApp.GetStage().GetPlayer().(mouseReleaser); ok {
+		ply.ReleaseMouse()
+	}
+}
+
+func (p *Pause) OnRemove() {
+	if p == nil {
+		return
+	}
+}
+
+func (p *Pause) GetPlayer() pub_object.Object {
+	if p == nil {
+		return nil
+	}
+
+	return nil
+}
+
+func (p *Pause) GetCurrentScene() pub_object.Object {
+	if p == nil {
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package stage
+
+import (
+	"flag"
+	"testing"
+
+	"karalis/internal/object/character"
+	"karalis/internal/object/world"
+	"karalis/internal/rlx"
+	"karalis/internal/scene"
+	"karalis/internal/target"
+	"karalis/pkg/render"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestGameRender(t *testing.T) {
+	rlx.InitWindow(320, 200, "test")
+	defer rlx.CloseWindow()
+
+	rt, err := target.Acquire(target.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Release()
+	g := &Game{queue: render.NewQueue(), rt: rt}
+	g.scene = &scene.Scene{}
+	err = g.scene.Init()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.scene.OnAdd(nil)
+	g.player, err = character.NewPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.scene.AddChild(g.player)
+	sky, err := world.NewSkybox(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.scene.AddChild(sky)
+	g.Update(0)
+
+	//the scene draws into the game target which is then drawn flipped to the screen
+	rec := rlx.StartRecorder()
+	g.Render(1)
+	rec.Stop()
+	err = rec.CompareGolden("testdata/game_render.golden", *update)
+	if err != nil {
+		t.Error(err)
+	}
+}
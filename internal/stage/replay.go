@@ -0,0 +1,147 @@
+package stage
+
+import (
+	"fmt"
+
+	"karalis/internal/object/world"
+	"karalis/internal/rlx"
+	"karalis/internal/scene"
+	"karalis/pkg/app"
+	"karalis/pkg/console"
+	"karalis/pkg/input"
+)
+
+// console commands recording and playing back input
+func init() {
+	console.Register(console.Command{
+		Name: "replay_record",
+		Help: "record input from the next tick until replay_stop",
+		Run: func(c *console.Console, args []string) error {
+			g := currentGame()
+			if g == nil {
+				return ErrNoGame
+			}
+			return g.RecordReplay()
+		},
+	})
+	console.Register(console.Command{
+		Name: "replay_stop",
+		Args: "[file]",
+		Help: "stop playing back, or stop recording and save the replay",
+		Run: func(c *console.Console, args []string) error {
+			if input.Replaying() {
+				input.StopPlayback()
+				return nil
+			}
+			r := input.StopRecording()
+			if r == nil {
+				return fmt.Errorf("Not recording")
+			}
+			path := input.NewReplayPath()
+			if len(args) > 0 {
+				path = args[0]
+			}
+			err := r.Save(path)
+			if err != nil {
+				return err
+			}
+			c.Printf("saved %d ticks to %s\n", r.Ticks, path)
+			return nil
+		},
+	})
+	console.Register(console.Command{
+		Name: "replay_play",
+		Args: "<file>",
+		Help: "play back a replay in the running game",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Usage: replay_play <file>")
+			}
+			r, err := input.LoadReplay(args[0])
+			if err != nil {
+				return err
+			}
+			g := currentGame()
+			if g == nil {
+				return ErrNoGame
+			}
+			return g.PlayReplay(r)
+		},
+	})
+}
+
+// record input from the start of the next tick
+func (g *Game) RecordReplay() error {
+	if g == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+	if input.Recording() || input.Replaying() {
+		return fmt.Errorf("Replay already running")
+	}
+
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+	g.Record = true
+	return nil
+}
+
+// play back a replay from the start of the next tick
+// the world seed and player pose are reset to the recorded ones
+func (g *Game) PlayReplay(r *input.Replay) error {
+	if g == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+	err := r.Validate()
+	if err != nil {
+		return err
+	}
+	if input.Recording() {
+		return fmt.Errorf("Recording input")
+	}
+
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+	g.Replay = r
+	return nil
+}
+
+// begin a requested recording or playback, called at the start of a tick holding mu
+func (g *Game) startReplay() error {
+	if !g.Record && g.Replay == nil {
+		return nil
+	}
+	record, r := g.Record, g.Replay
+	g.Record = false
+	g.Replay = nil
+
+	//the tick rate is not part of the world so it has to match
+	rate := 0
+	if app.CurApp != nil {
+		rate = app.CurApp.GetTickRate()
+	}
+	var w *world.World
+	for _, obj := range scene.OfType[*world.World](g.scene) {
+		w = obj
+		break
+	}
+
+	if record {
+		return input.StartRecording(input.Replay{
+			World:    w.GetKind(),
+			Seed:     w.GetSeed(),
+			TickRate: rate,
+			Start:    g.player.GetPose(),
+		})
+	}
+
+	if r.TickRate != rate {
+		return fmt.Errorf("Replay runs at %d ticks per second, the game at %d", r.TickRate, rate)
+	}
+	if w != nil && r.World != "" && r.World != w.GetKind() {
+		return fmt.Errorf("Replay is of a %s world, the game runs %s", r.World, w.GetKind())
+	}
+	world.Seed = r.Seed
+	w.SetSeed(r.Seed)
+	g.player.SetPose(r.Start)
+	return input.StartPlayback(r)
+}
@@ -0,0 +1,220 @@
+package stage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+	"time"
+
+	"karalis/internal/object/character"
+	"karalis/internal/object/world"
+	"karalis/internal/rlx"
+	"karalis/internal/scene"
+	"karalis/pkg/app"
+	"karalis/pkg/console"
+	"karalis/pkg/input"
+	"karalis/pkg/save"
+	"karalis/pkg/settings"
+
+	pub_scene "karalis/pkg/scene"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// console commands for save slots
+func init() {
+	console.Register(console.Command{
+		Name: "save",
+		Args: "[slot]",
+		Help: "save the game to a slot, quick by default",
+		Run: func(c *console.Console, args []string) error {
+			slot := save.DefaultSlot
+			if len(args) > 0 {
+				slot = args[0]
+			}
+			g := currentGame()
+			if g == nil {
+				return ErrNoGame
+			}
+			err := g.SaveSlot(save.Dir(), slot)
+			if err != nil {
+				return err
+			}
+			c.Printf("saved %s\n", slot)
+			return nil
+		},
+		Complete: completeSlots,
+	})
+	console.Register(console.Command{
+		Name: "load",
+		Args: "[slot]",
+		Help: "replace the game with the one in a slot, quick by default",
+		Run: func(c *console.Console, args []string) error {
+			slot := save.DefaultSlot
+			if len(args) > 0 {
+				slot = args[0]
+			}
+			return LoadSlot(save.Dir(), slot)
+		},
+		Complete: completeSlots,
+	})
+	console.Register(console.Command{
+		Name: "saves",
+		Help: "list the save slots, newest first",
+		Run: func(c *console.Console, args []string) error {
+			slots, err := save.List(save.Dir())
+			if err != nil {
+				return err
+			}
+			for _, s := range slots {
+				c.Printf("%s  %s  %s %d\n", s.Name, s.Time.Format("2006-01-02 15:04:05"), s.World, s.Seed)
+			}
+			return nil
+		},
+	})
+	console.Register(console.Command{
+		Name: "save_delete",
+		Args: "<slot>",
+		Help: "delete a save slot",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Usage: save_delete <slot>")
+			}
+			return save.Delete(save.Dir(), args[0])
+		},
+		Complete: completeSlots,
+	})
+}
+
+func completeSlots(args []string, prefix string) []string {
+	matches := []string{}
+	if len(args) > 0 {
+		return matches
+	}
+	slots, _ := save.List(save.Dir())
+	for _, s := range slots {
+		if strings.HasPrefix(s.Name, prefix) {
+			matches = append(matches, s.Name)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// get the play state of the game
+func (g *Game) GetState() (*save.State, error) {
+	if g == nil || g.scene == nil {
+		return nil, fmt.Errorf("Invalid stage")
+	}
+
+	rlx.Lock(&g.mu)
+	defer g.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+	err := pub_scene.Save(buf, g.scene)
+	if err != nil {
+		return nil, err
+	}
+	pose := g.player.GetPose()
+	s := &save.State{
+		Time:  time.Now(),
+		Scene: buf.Bytes(),
+		Player: save.Player{
+			Pos:   [3]float32{pose.Pos.X, pose.Pos.Y, pose.Pos.Z},
+			Yaw:   pose.Yaw,
+			Pitch: pose.Pitch,
+			Dist:  pose.Dist,
+			Mode:  g.player.GetMode(),
+		},
+	}
+	for _, w := range scene.OfType[*world.World](g.scene) {
+		s.World = w.GetKind()
+		s.Seed = w.GetSeed()
+		s.Cells = w.GetCellEdits()
+		break
+	}
+	return s, nil
+}
+
+// save the game to a slot in dir with a thumbnail of the last frame
+func (g *Game) SaveSlot(dir string, slot string) error {
+	if g == nil {
+		return fmt.Errorf("Invalid stage")
+	}
+	err := save.ValidSlot(slot)
+	if err != nil {
+		return err
+	}
+
+	//nothing is drawn when headless
+	var thumb image.Image
+	img, err := g.Capture()
+	if err != nil {
+		logger.Debug("save without thumbnail", "slot", slot, "err", err)
+	} else {
+		thumb = img
+	}
+	s, err := g.GetState()
+	if err != nil {
+		return err
+	}
+	return save.Write(dir, slot, s, thumb)
+}
+
+// replace the running game with the one saved in a slot in dir, loading it behind a loading stage
+func LoadSlot(dir string, slot string) error {
+	s, err := save.Read(dir, slot)
+	if err != nil {
+		return err
+	}
+	if app.CurApp == nil {
+		return fmt.Errorf("No app running")
+	}
+
+	next := &Game{World: s.World, Seed: s.Seed, Save: s}
+	stack, ok := app.CurApp.GetStage().(*Stack)
+	g := currentGame()
+	if !ok || g == nil {
+		app.CurApp.LoadStage(next, NewFade(0.5))
+		return nil
+	}
+	stack.replace(g, NewLoading(stack, next, NewFade(0.5)), nil)
+	return nil
+}
+
+// build the scene and player from a save, called from Init
+func (g *Game) restore(s *save.State) error {
+	f, err := pub_scene.Read(bytes.NewReader(s.Scene))
+	if err != nil {
+		return err
+	}
+	obj, err := pub_scene.Decode(f.Root)
+	if err != nil {
+		return err
+	}
+	sc, ok := obj.(*scene.Scene)
+	if !ok {
+		return fmt.Errorf("Save scene root is not a scene: %T", obj)
+	}
+	players := scene.OfType[*character.Player](sc)
+	if len(players) == 0 {
+		sc.OnRemove()
+		return fmt.Errorf("Save has no player")
+	}
+	g.progress.set(0.6)
+
+	sc.OnAdd(nil)
+	g.setScene(sc, players[0])
+	g.applySettings(settings.Get())
+
+	p := s.Player
+	g.player.SetMode(p.Mode)
+	g.player.SetPose(input.Pose{Pos: rl.NewVector3(p.Pos[0], p.Pos[1], p.Pos[2]), Yaw: p.Yaw, Pitch: p.Pitch, Dist: p.Dist})
+	for _, w := range scene.OfType[*world.World](sc) {
+		w.SetCellEdits(s.Cells)
+		world.Seed = w.GetSeed()
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+package stage
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"karalis/internal/rlx"
+	"karalis/pkg/event"
+
+	pub_object "karalis/pkg/object"
+	pub_stage "karalis/pkg/stage"
+)
+
+// kinds of stack changes
+const (
+	opPush = iota
+	opPop
+	opReplace
+	opSet
+)
+
+// change queued until the next update
+type stackOp struct {
+	kind  int
+	stage pub_stage.Stage
+	old   pub_stage.Stage
+	tr    pub_stage.Transition
+}
+
+// transition being played, from is the stack as it was before the change
+// stages that left the stack are only removed once it ends
+type transition struct {
+	effect  pub_stage.Transition
+	from    []pub_stage.Stage
+	gone    []pub_stage.Stage
+	elapsed float32
+}
+
+// stages layered bottom to top, the top stage gets input while those below keep rendering
+// changes are queued and applied at the start of the next update so stages can change the stack from their own callbacks
+type Stack struct {
+	mu     sync.Mutex
+	stages []pub_stage.Stage
+	trans  *transition
+
+	//copy of stages for lookups from inside stage callbacks
+	view atomic.Pointer[[]pub_stage.Stage]
+
+	pmu     sync.Mutex
+	pending []stackOp
+}
+
+func NewStack() *Stack {
+	s := &Stack{}
+	s.Init()
+	return s
+}
+
+func (s *Stack) Init() error {
+	if s == nil {
+		return nil
+	}
+
+	s.stages = []pub_stage.Stage{}
+	s.trans = nil
+	s.pending = []stackOp{}
+	s.publish()
+	return nil
+}
+
+// push a stage over the current ones
+func (s *Stack) Push(next pub_stage.Stage, tr pub_stage.Transition) {
+	s.queue(stackOp{kind: opPush, stage: next, tr: tr})
+}
+
+// pop the top stage
+func (s *Stack) Pop(tr pub_stage.Transition) {
+	s.queue(stackOp{kind: opPop, tr: tr})
+}
+
+// replace the top stage
+func (s *Stack) Change(next pub_stage.Stage, tr pub_stage.Transition) {
+	s.queue(stackOp{kind: opReplace, stage: next, tr: tr})
+}
+
+// replace the top stage with a loading stage while next initializes off the main thread
+func (s *Stack) Load(next pub_stage.Stage, tr pub_stage.Transition) {
+	s.Change(NewLoading(s, next, tr), nil)
+}
+
+// replace the whole stack with a single stage right away
+// unlike the other changes this must not be called from stage callbacks
+func (s *Stack) Set(next pub_stage.Stage) {
+	if s == nil {
+		return
+	}
+
+	rlx.Lock(&s.mu)
+	defer s.mu.Unlock()
+	s.applyPending()
+	s.apply(stackOp{kind: opSet, stage: next})
+}
+
+// get the top stage
+func (s *Stack) Top() pub_stage.Stage {
+	stages := s.getView()
+	if len(stages) == 0 {
+		return nil
+	}
+	return stages[len(stages)-1]
+}
+
+// get the stages bottom to top
+func (s *Stack) GetStages() []pub_stage.Stage {
+	return slices.Clone(s.getView())
+}
+
+// replace a specific stage wherever it is in the stack
+func (s *Stack) replace(old pub_stage.Stage, next pub_stage.Stage, tr pub_stage.Transition) {
+	s.queue(stackOp{kind: opReplace, stage: next, old: old, tr: tr})
+}
+
+func (s *Stack) queue(op stackOp) {
+	if s == nil {
+		return
+	}
+
+	s.pmu.Lock()
+	defer s.pmu.Unlock()
+	s.pending = append(s.pending, op)
+}
+
+// apply queued changes, callers hold mu
+func (s *Stack) applyPending() {
+	s.pmu.Lock()
+	ops := s.pending
+	s.pending = []stackOp{}
+	s.pmu.Unlock()
+
+	for _, op := range ops {
+		s.apply(op)
+	}
+}
+
+func (s *Stack) apply(op stackOp) {
+	s.endTransition()
+
+	prev := s.top()
+	before := slices.Clone(s.stages)
+	gone := []pub_stage.Stage{}
+	added := []pub_stage.Stage{}
+
+	switch op.kind {
+	case opPush:
+		if op.stage == nil {
+			return
+		}
+		s.stages = append(s.stages, op.stage)
+		added = append(added, op.stage)
+	case opPop:
+		if len(s.stages) == 0 {
+			return
+		}
+		gone = append(gone, s.stages[len(s.stages)-1])
+		s.stages = s.stages[:len(s.stages)-1]
+	case opReplace:
+		if op.stage == nil {
+			return
+		}
+		index := len(s.stages) - 1
+		if op.old != nil {
+			index = slices.Index(s.stages, op.old)
+			if index < 0 {
+				return
+			}
+		}
+		if index < 0 {
+			s.stages = append(s.stages, op.stage)
+		} else {
+			gone = append(gone, s.stages[index])
+			s.stages[index] = op.stage
+		}
+		added = append(added, op.stage)
+	case opSet:
+		gone = s.stages
+		s.stages = []pub_stage.Stage{}
+		if op.stage != nil {
+			s.stages = append(s.stages, op.stage)
+			added = append(added, op.stage)
+		}
+	}
+
+	if op.tr != nil && op.tr.GetDuration() > 0 {
+		s.trans = &transition{
+			effect: op.tr,
+			from:   before,
+			gone:   gone,
+		}
+	} else {
+		for _, stage := range gone {
+			stage.OnRemove()
+		}
+	}
+	for _, stage := range added {
+		stage.OnAdd()
+	}
+	s.publish()
+
+	if next := s.top(); next != prev {
+		event.Queue(event.StageChanged{Prev: prev, Next: next})
+	}
+}
+
+// finish the running transition removing the stages that left
+func (s *Stack) endTransition() {
+	if s.trans == nil {
+		return
+	}
+
+	for _, stage := range s.trans.gone {
+		if !slices.Contains(s.stages, stage) {
+			stage.OnRemove()
+		}
+	}
+	s.trans.effect.OnEnd()
+	s.trans = nil
+}
+
+func (s *Stack) top() pub_stage.Stage {
+	if len(s.stages) == 0 {
+		return nil
+	}
+	return s.stages[len(s.stages)-1]
+}
+
+func (s *Stack) publish() {
+	view := slices.Clone(s.stages)
+	s.view.Store(&view)
+}
+
+func (s *Stack) getView() []pub_stage.Stage {
+	if s == nil {
+		return nil
+	}
+
+	view := s.view.Load()
+	if view == nil {
+		return nil
+	}
+	return *view
+}
+
+// index of the lowest stage still updating
+func (s *Stack) firstActive() int {
+	for i := len(s.stages) - 1; i > 0; i-- {
+		if overlay, ok := s.stages[i].(pub_stage.Overlay); ok && overlay.PausesBelow() {
+			return i
+		}
+	}
+	return 0
+}
+
+func (s *Stack) OnResize(w int32, h int32) {
+	if s == nil {
+		return
+	}
+
+	rlx.Lock(&s.mu)
+	defer s.mu.Unlock()
+
+	stages := slices.Clone(s.stages)
+	if s.trans != nil {
+		for _, stage := range s.trans.from {
+			if !slices.Contains(stages, stage) {
+				stages = append(stages, stage)
+			}
+		}
+	}
+	for _, stage := range stages {
+		stage.OnResize(w, h)
+	}
+}
+
+// draw the stages bottom to top, through the transition effect while one runs
+func (s *Stack) Render(alpha float32) {
+	if s == nil {
+		return
+	}
+
+	rlx.Lock(&s.mu)
+	defer s.mu.Unlock()
+
+	if s.trans == nil {
+		renderStages(s.stages, alpha)
+		return
+	}
+
+	t := s.trans.elapsed / s.trans.effect.GetDuration()
+	if t > 1 {
+		t = 1
+	}
+	from := s.trans.from
+	s.trans.effect.Render(t, func() {
+		renderStages(from, alpha)
+	}, func() {
+		renderStages(s.stages, alpha)
+	})
+}
+
+func renderStages(stages []pub_stage.Stage, alpha float32) {
+	for _, stage := range stages {
+		stage.Render(alpha)
+	}
+}
+
+// apply queued changes, advance the transition and update stages not paused by an overlay
+func (s *Stack) Update(dt float32) {
+	if s == nil {
+		return
+	}
+
+	rlx.Lock(&s.mu)
+	defer s.mu.Unlock()
+
+	s.applyPending()
+	if s.trans != nil {
+		s.trans.elapsed += dt
+		if s.trans.elapsed >= s.trans.effect.GetDuration() {
+			s.endTransition()
+		}
+	}
+
+	for _, stage := range s.stages[s.firstActive():] {
+		stage.Update(dt)
+	}
+}
+
+// only the top stage receives input
+func (s *Stack) OnInput(dt float32) {
+	if s == nil {
+		return
+	}
+
+	rlx.Lock(&s.mu)
+	defer s.mu.Unlock()
+
+	if top := s.top(); top != nil {
+		top.OnInput(dt)
+	}
+}
+
+func (s *Stack) OnAdd() {
+	if s == nil {
+		return
+	}
+}
+
+// remove every stage, including those kept for a transition
+func (s *Stack) OnRemove() {
+	if s == nil {
+		return
+	}
+
+	rlx.Lock(&s.mu)
+	defer s.mu.Unlock()
+
+	s.endTransition()
+	for i := len(s.stages) - 1; i >= 0; i-- {
+		s.stages[i].OnRemove()
+	}
+	s.stages = []pub_stage.Stage{}
+	s.publish()
+}
+
+// get the player of the topmost stage that has one
+func (s *Stack) GetPlayer() pub_object.Object {
+	stages := s.getView()
+	for i := len(stages) - 1; i >= 0; i-- {
+		if ply := stages[i].GetPlayer(); ply != nil {
+			return ply
+		}
+	}
+	return nil
+}
+
+// get the scene of the topmost stage that has one
+func (s *Stack) GetCurrentScene() pub_object.Object {
+	stages := s.getView()
+	for i := len(stages) - 1; i >= 0; i-- {
+		if scene := stages[i].GetCurrentScene(); scene != nil {
+			return scene
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package stage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"karalis/internal/rlx"
+	"karalis/internal/target"
+
+	pub_object "karalis/pkg/object"
+	pub_stage "karalis/pkg/stage"
+)
+
+type testStage struct {
+	pub_object.Object
+
+	pauses  bool
+	err     error
+	block   chan struct{}
+	gone    chan struct{}
+	inits   int
+	updates int
+	inputs  int
+	renders int
+	added   int
+	removed int
+}
+
+func (s *testStage) Init() error {
+	s.inits++
+	if s.block != nil {
+		<-s.block
+	}
+	return s.err
+}
+func (s *testStage) OnResize(w int32, h int32) {}
+func (s *testStage) Render(alpha float32)      { s.renders++ }
+func (s *testStage) Update(dt float32)         { s.updates++ }
+func (s *testStage) OnInput(dt float32)        { s.inputs++ }
+func (s *testStage) OnAdd()                    { s.added++ }
+func (s *testStage) OnRemove() {
+	s.removed++
+	if s.gone != nil {
+		close(s.gone)
+	}
+}
+func (s *testStage) GetPlayer() pub_object.Object       { return s.Object }
+func (s *testStage) GetCurrentScene() pub_object.Object { return nil }
+func (s *testStage) PausesBelow() bool                  { return s.pauses }
+
+type testTransition struct {
+	duration float32
+	froms    int
+	tos      int
+	ended    int
+}
+
+func (t *testTransition) GetDuration() float32 { return t.duration }
+func (t *testTransition) OnEnd()               { t.ended++ }
+func (t *testTransition) Render(at float32, from func(), to func()) {
+	t.froms++
+	t.tos++
+	from()
+	to()
+}
+
+// stages only draw on the null backend
+func TestMain(m *testing.M) {
+	rlx.SetHeadless(true)
+	os.Exit(m.Run())
+}
+
+func TestStackOverlay(t *testing.T) {
+	tests := []struct {
+		pauses  bool
+		updates int
+	}{
+		{false, 2},
+		{true, 1},
+	}
+	for _, test := range tests {
+		s := NewStack()
+		game := &testStage{Object: &struct{ pub_object.Object }{}}
+		menu := &testStage{pauses: test.pauses}
+		s.Set(game)
+		s.Update(0.1)
+		s.Push(menu, nil)
+		if s.Top() != game {
+			t.Error(fmt.Sprintf("Push applied before update: top %v", s.Top()))
+		}
+		s.Update(0.1)
+		s.OnInput(0.1)
+		s.Render(1)
+
+		if game.updates != test.updates {
+			t.Error(fmt.Sprintf("Unexpected updates below overlay: expected %v, got %v", test.updates, game.updates))
+		}
+		if game.inputs != 0 || menu.inputs != 1 {
+			t.Error(fmt.Sprintf("Input should only reach the top stage: got %v below, %v on top", game.inputs, menu.inputs))
+		}
+		if game.renders != 1 || menu.renders != 1 {
+			t.Error(fmt.Sprintf("Every stage should render: got %v below, %v on top", game.renders, menu.renders))
+		}
+		if s.GetPlayer() != game.Object {
+			t.Error(fmt.Sprintf("Player should come from the stage below the overlay"))
+		}
+
+		s.Pop(nil)
+		s.Update(0.1)
+		if s.Top() != game || menu.removed != 1 || game.removed != 0 {
+			t.Error(fmt.Sprintf("Pop did not restore the stage below: top %v, removed %v", s.Top(), menu.removed))
+		}
+	}
+}
+
+func TestStackTransition(t *testing.T) {
+	tests := []struct {
+		duration float32
+		frames   int
+	}{
+		{0, 0},
+		{0.25, 2},
+		{0.5, 4},
+	}
+	for _, test := range tests {
+		s := NewStack()
+		a := &testStage{}
+		b := &testStage{}
+		tr := &testTransition{duration: test.duration}
+		s.Set(a)
+		s.Change(b, tr)
+
+		//each tick advances the transition before it is drawn
+		s.Update(0.1)
+		frames := 0
+		for a.removed == 0 && frames < 100 {
+			s.Render(1)
+			frames++
+			s.Update(0.1)
+		}
+		if tr.froms != test.frames {
+			t.Error(fmt.Sprintf("Unexpected transition frames for %v: expected %v, got %v", test.duration, test.frames, tr.froms))
+		}
+		if a.removed != 1 || b.added != 1 || b.removed != 0 {
+			t.Error(fmt.Sprintf("Unexpected stage lifecycle: removed %v, added %v", a.removed, b.added))
+		}
+		if test.duration > 0 && tr.ended != 1 {
+			t.Error(fmt.Sprintf("Transition not ended: %v", tr.ended))
+		}
+	}
+}
+
+func TestCrossFadeTargets(t *testing.T) {
+	before := target.Default.Stats()
+	c := NewCrossFade(0.5)
+	froms, tos := 0, 0
+	for range 3 {
+		c.Render(0.5, func() { froms++ }, func() { tos++ })
+	}
+	if froms != 3 || tos != 3 {
+		t.Error(fmt.Sprintf("TestCrossFadeTargets expected both sides drawn 3 times, got %d %d", froms, tos))
+	}
+	if s := target.Default.Stats(); s.Targets-s.Free != before.Targets-before.Free+2 {
+		t.Error(fmt.Sprintf("TestCrossFadeTargets expected two pooled targets held, got %+v", s))
+	}
+
+	//the targets go back to the pool for the next fade
+	c.OnEnd()
+	if s := target.Default.Stats(); s.Targets-s.Free != before.Targets-before.Free {
+		t.Error(fmt.Sprintf("TestCrossFadeTargets expected the targets released, got %+v", s))
+	}
+}
+
+func TestStackLoading(t *testing.T) {
+	tests := []struct {
+		tr pub_stage.Transition
+	}{
+		{nil},
+		{&testTransition{duration: 0.1}},
+	}
+	for _, test := range tests {
+		s := NewStack()
+		next := &testStage{}
+		s.Load(next, test.tr)
+
+		deadline := time.Now().Add(time.Second)
+		for s.Top() != next && time.Now().Before(deadline) {
+			s.Update(0.1)
+			time.Sleep(time.Millisecond)
+		}
+		if s.Top() != next {
+			t.Error(fmt.Sprintf("Loaded stage never replaced the loading stage: top %v", s.Top()))
+		}
+		if next.inits != 1 || next.added != 1 {
+			t.Error(fmt.Sprintf("Unexpected loaded stage lifecycle: inits %v, added %v", next.inits, next.added))
+		}
+	}
+}
+
+func TestLoadingFailure(t *testing.T) {
+	s := NewStack()
+	next := &testStage{err: fmt.Errorf("Broken")}
+	s.Load(next, nil)
+
+	deadline := time.Now().Add(time.Second)
+	l, _ := s.Top().(*Loading)
+	for (l == nil || l.GetError() == nil) && time.Now().Before(deadline) {
+		s.Update(0.1)
+		l, _ = s.Top().(*Loading)
+		time.Sleep(time.Millisecond)
+	}
+	if l == nil || l.GetError() == nil {
+		t.Fatal(fmt.Sprintf("TestLoadingFailure expected the loading stage to show the error, top %v", s.Top()))
+	}
+	if next.removed != 1 || next.added != 0 {
+		t.Error(fmt.Sprintf("TestLoadingFailure expected the failed stage released, removed %v, added %v", next.removed, next.added))
+	}
+
+	//removing the loading stage does not release it twice
+	s.OnRemove()
+	if next.removed != 1 {
+		t.Error(fmt.Sprintf("TestLoadingFailure expected one release, got %v", next.removed))
+	}
+}
+
+func TestLoadingRemoved(t *testing.T) {
+	s := NewStack()
+	next := &testStage{block: make(chan struct{}), gone: make(chan struct{})}
+	s.Load(next, nil)
+	s.Update(0.1)
+
+	//popped while the next stage is still initializing
+	s.Pop(nil)
+	s.Update(0.1)
+	if s.Top() != nil {
+		t.Error(fmt.Sprintf("TestLoadingRemoved expected an empty stack, top %v", s.Top()))
+	}
+	close(next.block)
+
+	select {
+	case <-next.gone:
+		if next.removed != 1 || next.added != 0 {
+			t.Error(fmt.Sprintf("TestLoadingRemoved expected the stage released once, removed %v, added %v", next.removed, next.added))
+		}
+	case <-time.After(time.Second):
+		t.Error("TestLoadingRemoved expected the stage released once loaded")
+	}
+}
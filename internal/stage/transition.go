@@ -0,0 +1,141 @@
+package stage
+
+import (
+	"image/color"
+
+	"karalis/internal/rlx"
+	"karalis/internal/target"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var ()
+
+// fade out to a color and back in on the new stages
+type Fade struct {
+	Duration float32
+	Color    color.RGBA
+}
+
+func NewFade(duration float32) *Fade {
+	return &Fade{
+		Duration: duration,
+		Color:    rl.Black,
+	}
+}
+
+func (f *Fade) GetDuration() float32 {
+	if f == nil {
+		return 0
+	}
+
+	return f.Duration
+}
+
+// first half covers the old stages, second half uncovers the new ones
+func (f *Fade) Render(t float32, from func(), to func()) {
+	if f == nil {
+		return
+	}
+
+	alpha := 2 * t
+	if t < 0.5 {
+		from()
+	} else {
+		to()
+		alpha = 2 - 2*t
+	}
+	w := int32(rlx.GetScreenWidth())
+	h := int32(rlx.GetScreenHeight())
+	rlx.DrawRectangle(0, 0, w, h, rlx.Fade(f.Color, alpha))
+}
+
+func (f *Fade) OnEnd() {
+	if f == nil {
+		return
+	}
+}
+
+// blend the old stages into the new ones
+type CrossFade struct {
+	Duration float32
+	//pooled for the length of the fade
+	fromRT *target.Target
+	toRT   *target.Target
+}
+
+func NewCrossFade(duration float32) *CrossFade {
+	return &CrossFade{
+		Duration: duration,
+	}
+}
+
+func (c *CrossFade) GetDuration() float32 {
+	if c == nil {
+		return 0
+	}
+
+	return c.Duration
+}
+
+// both stacks are drawn into their own target and blended on screen
+func (c *CrossFade) Render(t float32, from func(), to func()) {
+	if c == nil {
+		return
+	}
+
+	err := c.acquire(&c.fromRT)
+	if err == nil {
+		err = c.acquire(&c.toRT)
+	}
+	if err != nil {
+		logger.Error("cross fade target", "err", err)
+		to()
+		return
+	}
+	fromRT := c.fromRT.RenderTexture()
+	toRT := c.toRT.RenderTexture()
+	w := fromRT.Texture.Width
+	h := fromRT.Texture.Height
+
+	rlx.BeginTextureMode(fromRT)
+	rlx.ClearBackground(rl.Black)
+	from()
+	rlx.EndTextureMode()
+
+	rlx.BeginTextureMode(toRT)
+	rlx.ClearBackground(rl.Black)
+	to()
+	rlx.EndTextureMode()
+
+	src := rl.Rectangle{0, 0, float32(w), -float32(h)}
+	dst := rl.Rectangle{0, 0, float32(w), float32(h)}
+	rlx.DrawTexturePro(fromRT.Texture, src, dst, rl.Vector2{0, 0}, 0.0, rl.White)
+	rlx.DrawTexturePro(toRT.Texture, src, dst, rl.Vector2{0, 0}, 0.0, rlx.Fade(rl.White, t))
+}
+
+// give the render targets back to the pool
+func (c *CrossFade) OnEnd() {
+	if c == nil {
+		return
+	}
+
+	c.fromRT.Release()
+	c.toRT.Release()
+	c.fromRT = nil
+	c.toRT = nil
+}
+
+// take a pooled target for the fade, kept until it ends and resized by the target manager
+func (c *CrossFade) acquire(rt **target.Target) error {
+	if *rt != nil {
+		return nil
+	}
+
+	t, err := target.Acquire(target.Options{})
+	if err != nil {
+		return err
+	}
+	*rt = t
+	return nil
+}
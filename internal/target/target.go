@@ -0,0 +1,370 @@
+package target
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"karalis/internal/rlx"
+	"karalis/pkg/console"
+	"karalis/pkg/logging"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// how a target keeps depth
+type Depth int
+
+const (
+	// depth renderbuffer, as raylib render textures have
+	DepthBuffer Depth = iota
+	// depth texture shaders can sample
+	DepthTexture
+	DepthNone
+	// depth and stencil renderbuffer, for effects masking with the stencil such as portals
+	DepthStencil
+)
+
+var depthNames = map[Depth]string{
+	DepthBuffer:  "buffer",
+	DepthTexture: "texture",
+	DepthNone:    "none",
+	DepthStencil: "stencil",
+}
+
+func (d Depth) String() string {
+	return depthNames[d]
+}
+
+// how a target is sized and built
+type Options struct {
+	//size as a fraction of the render size, 0 is the whole size
+	Scale float32
+	//fixed size in pixels, kept on resize, used in place of Scale when both are set
+	Width  int32
+	Height int32
+	Depth  Depth
+}
+
+// whether the target follows the render size
+func (o Options) scaled() bool {
+	return o.Width <= 0 || o.Height <= 0
+}
+
+// get the size of a target with the options for a render size
+func (o Options) size(width int32, height int32) (int32, int32) {
+	if !o.scaled() {
+		return o.Width, o.Height
+	}
+	scale := o.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	return max(int32(float32(width)*scale), 1), max(int32(float32(height)*scale), 1)
+}
+
+// offscreen framebuffer handed out by a manager
+// the render texture is swapped when the manager resizes it, so it is fetched every frame rather than kept
+type Target struct {
+	mgr  *Manager
+	name string
+	opts Options
+	rt   rl.RenderTexture2D
+	//holders of a named target, 1 while a pooled target is in use
+	refs int
+}
+
+// get the render texture to draw into
+func (t *Target) RenderTexture() rl.RenderTexture2D {
+	if t == nil {
+		return rl.RenderTexture2D{}
+	}
+	t.mgr.mu.Lock()
+	defer t.mgr.mu.Unlock()
+	return t.rt
+}
+
+// get the color texture to sample
+func (t *Target) Texture() rl.Texture2D {
+	return t.RenderTexture().Texture
+}
+
+// give the target back, named targets are unloaded once nothing holds them and pooled ones wait to be reused
+func (t *Target) Release() {
+	if t == nil {
+		return
+	}
+	t.mgr.release(t)
+}
+
+// gpu memory a target of the size takes
+func targetBytes(width int32, height int32, depth Depth) int64 {
+	//rgba8 color, depth is 24 bits padded to 32 or packed with 8 bits of stencil
+	per := int64(4)
+	if depth != DepthNone {
+		per += 4
+	}
+	return int64(width) * int64(height) * per
+}
+
+func (t *Target) bytes() int64 {
+	return targetBytes(t.rt.Texture.Width, t.rt.Texture.Height, t.opts.Depth)
+}
+
+// named and pooled render targets sized from the render size
+// effects asking for the same name share one target and short lived passes reuse pooled ones instead of loading their own
+type Manager struct {
+	mu     sync.Mutex
+	width  int32
+	height int32
+	named  map[string]*Target
+	//every target loaded, in the order they were
+	targets []*Target
+}
+
+// a target as listed by the manager
+type Info struct {
+	//empty for pooled targets
+	Name   string
+	Width  int32
+	Height int32
+	Depth  Depth
+	Refs   int
+	Bytes  int64
+}
+
+// what the manager holds on the gpu
+type Stats struct {
+	Targets int
+	//pooled targets waiting to be reused
+	Free  int
+	Bytes int64
+}
+
+var (
+	Default = NewManager()
+
+	logger = logging.For(logging.App)
+)
+
+func init() {
+	console.Register(console.Command{
+		Name: "r_targets",
+		Help: "list the render targets and the gpu memory they take",
+		Run: func(c *console.Console, args []string) error {
+			for _, info := range Default.List() {
+				name := info.Name
+				if name == "" {
+					name = "(pool)"
+				}
+				c.Printf("%-16s %5dx%-5d depth %-7s refs %d %6.1f MiB\n", name, info.Width, info.Height, info.Depth, info.Refs, float64(info.Bytes)/(1<<20))
+			}
+			s := Default.Stats()
+			c.Printf("%d targets, %d free, %.1f MiB\n", s.Targets, s.Free, float64(s.Bytes)/(1<<20))
+			return nil
+		},
+	})
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		named:   map[string]*Target{},
+		targets: []*Target{},
+	}
+}
+
+// get the named target, loading it the first time, every Get needs a Release
+// later callers share the target and have to ask for it with the same options
+func (m *Manager) Get(name string, opts Options) (*Target, error) {
+	if m == nil {
+		return nil, fmt.Errorf("Invalid target manager")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("Invalid target name")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.named[name]; ok {
+		if t.opts != opts {
+			return nil, fmt.Errorf("Target %s exists with other options: %+v", name, t.opts)
+		}
+		t.refs++
+		return t, nil
+	}
+	t, err := m.load(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	m.named[name] = t
+	return t, nil
+}
+
+// get a pooled target only the caller uses until it releases it, reusing a free one with the same options
+func (m *Manager) Acquire(opts Options) (*Target, error) {
+	if m == nil {
+		return nil, fmt.Errorf("Invalid target manager")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.targets {
+		if t.name == "" && t.refs == 0 && t.opts == opts {
+			t.refs = 1
+			return t, nil
+		}
+	}
+	return m.load("", opts)
+}
+
+// load a target held once, called holding mu
+func (m *Manager) load(name string, opts Options) (*Target, error) {
+	m.fitSize()
+	width, height := opts.size(m.width, m.height)
+	rt := loadTexture(width, height, opts.Depth)
+	if rt.ID == 0 {
+		return nil, fmt.Errorf("Could not create a %dx%d render target", width, height)
+	}
+	t := &Target{mgr: m, name: name, opts: opts, rt: rt, refs: 1}
+	m.targets = append(m.targets, t)
+	return t, nil
+}
+
+func loadTexture(width int32, height int32, depth Depth) rl.RenderTexture2D {
+	if depth == DepthStencil {
+		return rlx.LoadRenderTextureStencil(width, height)
+	}
+	return rlx.LoadRenderTextureEx(width, height, depth != DepthNone, depth == DepthTexture)
+}
+
+// take the render size from the window until the first resize, called holding mu
+func (m *Manager) fitSize() {
+	if m.width > 0 && m.height > 0 {
+		return
+	}
+	m.width = int32(rlx.GetRenderWidth())
+	m.height = int32(rlx.GetRenderHeight())
+}
+
+func (m *Manager) release(t *Target) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t.refs <= 0 {
+		return
+	}
+	t.refs--
+	if t.refs > 0 || t.name == "" {
+		return
+	}
+	delete(m.named, t.name)
+	m.unload(t)
+}
+
+// unload a target and forget it, called holding mu
+func (m *Manager) unload(t *Target) {
+	rlx.UnloadRenderTexture(t.rt)
+	t.rt = rl.RenderTexture2D{}
+	t.refs = 0
+	m.targets = slices.DeleteFunc(m.targets, func(o *Target) bool { return o == t })
+}
+
+// reload the targets that follow the render size, free pooled targets are unloaded rather than resized
+func (m *Manager) OnResize(width int32, height int32) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.width = width
+	m.height = height
+	for _, t := range slices.Clone(m.targets) {
+		if t.refs == 0 {
+			m.unload(t)
+			continue
+		}
+		if !t.opts.scaled() {
+			continue
+		}
+		w, h := t.opts.size(width, height)
+		if t.rt.Texture.Width == w && t.rt.Texture.Height == h {
+			continue
+		}
+		rlx.UnloadRenderTexture(t.rt)
+		t.rt = loadTexture(w, h, t.opts.Depth)
+		if t.rt.ID == 0 {
+			logger.Error("resize render target", "name", t.name, "width", w, "height", h)
+		}
+	}
+}
+
+// unload every target, logging the ones still held
+func (m *Manager) Unload() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range slices.Clone(m.targets) {
+		if t.refs > 0 {
+			logger.Warn("render target still held", "name", t.name, "refs", t.refs, "bytes", t.bytes())
+		}
+		m.unload(t)
+	}
+	clear(m.named)
+}
+
+// list the loaded targets
+func (m *Manager) List() []Info {
+	if m == nil {
+		return []Info{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Info, 0, len(m.targets))
+	for _, t := range m.targets {
+		out = append(out, Info{
+			Name:   t.name,
+			Width:  t.rt.Texture.Width,
+			Height: t.rt.Texture.Height,
+			Depth:  t.opts.Depth,
+			Refs:   t.refs,
+			Bytes:  t.bytes(),
+		})
+	}
+	return out
+}
+
+func (m *Manager) Stats() Stats {
+	s := Stats{}
+	for _, info := range m.List() {
+		s.Targets++
+		if info.Name == "" && info.Refs == 0 {
+			s.Free++
+		}
+		s.Bytes += info.Bytes
+	}
+	return s
+}
+
+// get a named target from the default manager
+func Get(name string, opts Options) (*Target, error) {
+	return Default.Get(name, opts)
+}
+
+// get a pooled target from the default manager
+func Acquire(opts Options) (*Target, error) {
+	return Default.Acquire(opts)
+}
+
+// resize the targets of the default manager
+func OnResize(width int32, height int32) {
+	Default.OnResize(width, height)
+}
+
+// unload the targets of the default manager
+func Unload() {
+	Default.Unload()
+}
@@ -0,0 +1,148 @@
+package target
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"karalis/internal/rlx"
+)
+
+// targets are only made on the null backend
+func TestMain(m *testing.M) {
+	rlx.SetHeadless(true)
+	rlx.InitWindow(320, 200, "test")
+	os.Exit(m.Run())
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		opts   Options
+		width  int32
+		height int32
+		scaled bool
+	}{
+		{Options{}, 320, 200, true},
+		{Options{Scale: 0.5}, 160, 100, true},
+		{Options{Scale: 0.001}, 1, 1, true},
+		{Options{Width: 64, Height: 32}, 64, 32, false},
+		{Options{Scale: 0.5, Width: 64, Height: 32}, 64, 32, false},
+		{Options{Width: 64}, 320, 200, true},
+	}
+
+	for i, test := range tests {
+		w, h := test.opts.size(320, 200)
+		if w != test.width || h != test.height || test.opts.scaled() != test.scaled {
+			t.Error(fmt.Sprintf("TestOptions[%d] expected %dx%d scaled %v, got %dx%d scaled %v", i, test.width, test.height, test.scaled, w, h, test.opts.scaled()))
+		}
+	}
+}
+
+func TestManager(t *testing.T) {
+	m := NewManager()
+
+	//named targets are shared and unloaded with their last holder
+	a, err := m.Get("water", Options{Scale: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := m.Get("water", Options{Scale: 0.5})
+	if err != nil || a != b {
+		t.Error(fmt.Sprintf("TestManager expected a shared target, got %p %p, %v", a, b, err))
+	}
+	_, err = m.Get("water", Options{})
+	if err == nil {
+		t.Error("TestManager expected an error for other options")
+	}
+	rt := a.RenderTexture()
+	if rt.ID == 0 || rt.Texture.Width != 160 || rt.Texture.Height != 100 {
+		t.Error(fmt.Sprintf("TestManager expected a 160x100 target, got %d %dx%d", rt.ID, rt.Texture.Width, rt.Texture.Height))
+	}
+	a.Release()
+	if s := m.Stats(); s.Targets != 1 || s.Bytes != 160*100*8 {
+		t.Error(fmt.Sprintf("TestManager expected one held target, got %+v", s))
+	}
+	b.Release()
+	if s := m.Stats(); s.Targets != 0 || s.Bytes != 0 {
+		t.Error(fmt.Sprintf("TestManager expected no targets, got %+v", s))
+	}
+
+	//pooled targets are reused once released
+	p, err := m.Acquire(Options{Depth: DepthNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := m.Acquire(Options{Depth: DepthNone})
+	if err != nil || p == q {
+		t.Error(fmt.Sprintf("TestManager expected two pooled targets, got %p %p, %v", p, q, err))
+	}
+	if p.RenderTexture().Depth.ID != 0 {
+		t.Error("TestManager expected a target without depth")
+	}
+	q.Release()
+	r, err := m.Acquire(Options{Depth: DepthNone})
+	if err != nil || r != q {
+		t.Error(fmt.Sprintf("TestManager expected the released target back, got %p %p, %v", r, q, err))
+	}
+	r.Release()
+	if s := m.Stats(); s.Targets != 2 || s.Free != 1 || s.Bytes != 2*320*200*4 {
+		t.Error(fmt.Sprintf("TestManager expected two pooled targets, one free, got %+v", s))
+	}
+
+	//held targets follow the render size, free ones are dropped and fixed ones kept
+	fixed, err := m.Get("shadow", Options{Width: 64, Height: 64, Depth: DepthTexture})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.OnResize(640, 480)
+	rt = p.RenderTexture()
+	if rt.Texture.Width != 640 || rt.Texture.Height != 480 {
+		t.Error(fmt.Sprintf("TestManager expected a 640x480 target after resize, got %dx%d", rt.Texture.Width, rt.Texture.Height))
+	}
+	rt = fixed.RenderTexture()
+	if rt.Texture.Width != 64 || rt.Texture.Height != 64 {
+		t.Error(fmt.Sprintf("TestManager expected the fixed target to keep its size, got %dx%d", rt.Texture.Width, rt.Texture.Height))
+	}
+	if s := m.Stats(); s.Targets != 2 || s.Free != 0 {
+		t.Error(fmt.Sprintf("TestManager expected the free target dropped, got %+v", s))
+	}
+
+	m.Unload()
+	if s := m.Stats(); s.Targets != 0 || p.RenderTexture().ID != 0 {
+		t.Error(fmt.Sprintf("TestManager expected everything unloaded, got %+v", s))
+	}
+	//releasing after the unload does nothing
+	p.Release()
+	fixed.Release()
+}
+
+func TestDepthStencil(t *testing.T) {
+	m := NewManager()
+	defer m.Unload()
+
+	tests := []struct {
+		depth Depth
+		call  string
+	}{
+		{DepthBuffer, "LoadRenderTextureEx"},
+		{DepthStencil, "LoadRenderTextureStencil"},
+	}
+	for _, test := range tests {
+		rec := rlx.StartRecorder()
+		tgt, err := m.Acquire(Options{Depth: test.depth})
+		rec.Stop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entries := rec.Entries(); len(entries) == 0 || entries[len(entries)-1].Name != test.call {
+			t.Error(fmt.Sprintf("TestDepthStencil %s expected %s, got %q", test.depth, test.call, rec.Entries()))
+		}
+		if tgt.RenderTexture().Depth.ID == 0 {
+			t.Error(fmt.Sprintf("TestDepthStencil %s expected a depth attachment", test.depth))
+		}
+	}
+	//stencil targets are pooled apart from depth only ones and take the same memory
+	if s := m.Stats(); s.Targets != 2 || s.Bytes != 2*320*200*8 {
+		t.Error(fmt.Sprintf("TestDepthStencil expected two targets, got %+v", s))
+	}
+}
@@ -12,10 +12,17 @@ var (
 type App interface {
 	GetWidth() int32
 	GetHeight() int32
+	GetTickRate() int
+	SetTickRate(rate int) error
 	Running() bool
 	Start(debug bool) error
 	Exit()
 	SetStage(nextStage stage.Stage)
 	GetStage() stage.Stage
+	GetTopStage() stage.Stage
+	PushStage(next stage.Stage, tr stage.Transition)
+	PopStage(tr stage.Transition)
+	ChangeStage(next stage.Stage, tr stage.Transition)
+	LoadStage(next stage.Stage, tr stage.Transition)
 	GetShader() shader.Shader
 }
@@ -0,0 +1,120 @@
+package capture
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"karalis/pkg/config"
+)
+
+var ()
+
+// folder in the config dir captures are written to
+func Dir() string {
+	return filepath.Join(config.ConfigDir, "captures")
+}
+
+// path for a new screenshot in dir named after the time, numbered when taken in the same second
+func NextScreenshot(dir string, now time.Time) string {
+	base := "screenshot_" + now.Format("20060102_150405")
+	path := filepath.Join(dir, base+".png")
+	for i := 1; exists(path); i++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s_%d.png", base, i))
+	}
+	return path
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// write an image as a png, creating the folder it goes in
+func Save(img image.Image, path string) error {
+	if img == nil {
+		return fmt.Errorf("Invalid image")
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// steps the logic at a fixed rate for each recorded frame, decoupled from real time
+// so a sequence plays back smoothly at FPS however long each frame took to draw
+type Recorder struct {
+	Dir string
+	FPS int
+
+	tickRate int
+	//simulated time not yet covered by a tick, in 1/FPS of a tick so no error builds up
+	acc   int
+	frame int
+}
+
+// start a sequence in a new numbered folder under dir
+func NewRecorder(dir string, fps int, tickRate int) (*Recorder, error) {
+	if fps <= 0 || tickRate <= 0 {
+		return nil, fmt.Errorf("Invalid rate: %d fps at %d ticks", fps, tickRate)
+	}
+
+	seq := ""
+	for i := 1; ; i++ {
+		seq = filepath.Join(dir, fmt.Sprintf("sequence_%03d", i))
+		if !exists(seq) {
+			break
+		}
+	}
+	err := os.MkdirAll(seq, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{Dir: seq, FPS: fps, tickRate: tickRate}, nil
+}
+
+// advance one frame, getting the ticks to run before drawing it and the interpolation alpha to draw at
+func (r *Recorder) Advance() (int, float32) {
+	if r == nil {
+		return 0, 1
+	}
+
+	r.acc += r.tickRate
+	steps := r.acc / r.FPS
+	r.acc %= r.FPS
+	return steps, float32(r.acc) / float32(r.FPS)
+}
+
+// get the path of the next frame and count it
+func (r *Recorder) NextFrame() string {
+	if r == nil {
+		return ""
+	}
+
+	r.frame++
+	return filepath.Join(r.Dir, fmt.Sprintf("frame_%06d.png", r.frame))
+}
+
+// get the number of frames taken
+func (r *Recorder) GetFrames() int {
+	if r == nil {
+		return 0
+	}
+
+	return r.frame
+}
@@ -0,0 +1,97 @@
+package capture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAdvance(t *testing.T) {
+	tests := []struct {
+		fps      int
+		tickRate int
+		steps    []int
+		alpha    []float32
+	}{
+		{30, 60, []int{2, 2, 2}, []float32{0, 0, 0}},
+		{60, 60, []int{1, 1, 1}, []float32{0, 0, 0}},
+		{24, 60, []int{2, 3, 2, 3}, []float32{0.5, 0, 0.5, 0}},
+		{120, 60, []int{0, 1, 0, 1}, []float32{0.5, 0, 0.5, 0}},
+		{7, 60, []int{8, 9, 8, 9, 8, 9, 9}, []float32{4.0 / 7, 1.0 / 7, 5.0 / 7, 2.0 / 7, 6.0 / 7, 3.0 / 7, 0}},
+	}
+	for i, test := range tests {
+		r, err := NewRecorder(t.TempDir(), test.fps, test.tickRate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total := 0
+		for f := range test.steps {
+			steps, alpha := r.Advance()
+			total += steps
+			if steps != test.steps[f] || alpha != test.alpha[f] {
+				t.Error(fmt.Sprintf("Unexpected step for case %d frame %d: expected %d at %v, got %d at %v", i, f, test.steps[f], test.alpha[f], steps, alpha))
+			}
+		}
+		//no time is lost or gained over the frames
+		expect := len(test.steps) * test.tickRate / test.fps
+		if total != expect {
+			t.Error(fmt.Sprintf("Unexpected total ticks for case %d: expected %d, got %d", i, expect, total))
+		}
+	}
+
+	_, err := NewRecorder(t.TempDir(), 0, 60)
+	if err == nil {
+		t.Error("Recorder accepted 0 fps")
+	}
+}
+
+func TestNaming(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(1, 0, color.RGBA{255, 0, 0, 255})
+
+	expect := []string{"screenshot_20240506_070809.png", "screenshot_20240506_070809_1.png", "screenshot_20240506_070809_2.png"}
+	for i, name := range expect {
+		path := NextScreenshot(dir, now)
+		if filepath.Base(path) != name {
+			t.Error(fmt.Sprintf("Unexpected screenshot name %d: expected %s, got %s", i, name, filepath.Base(path)))
+		}
+		err := Save(img, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(filepath.Join(dir, expect[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	read, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := read.At(1, 0).RGBA(); r != 0xffff {
+		t.Error(fmt.Sprintf("Saved png does not match: %+v", read.At(1, 0)))
+	}
+
+	//sequences go in new folders numbered from 1
+	for i := 1; i <= 2; i++ {
+		r, err := NewRecorder(dir, 30, 60)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(r.Dir) != fmt.Sprintf("sequence_%03d", i) {
+			t.Error(fmt.Sprintf("Unexpected sequence folder: %s", r.Dir))
+		}
+		if frame := filepath.Base(r.NextFrame()); frame != "frame_000001.png" || r.GetFrames() != 1 {
+			t.Error(fmt.Sprintf("Unexpected first frame: %s", frame))
+		}
+	}
+}
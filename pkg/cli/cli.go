@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"karalis/pkg/net/client"
+	"karalis/pkg/net/server"
+)
+
+// subcommands of the karalis binary
+const (
+	Play   = "play"
+	Server = "server"
+	Client = "client"
+	Schema = "schema"
+	Gen    = "gen"
+	Replay = "replay"
+)
+
+var (
+	Commands = []string{Play, Server, Client, Schema, Gen, Replay}
+	Worlds   = []string{"terrain", "city", "dungeon"}
+	Schemas  = []string{"all", "scene", "bindings", "settings"}
+
+	// returned when help was asked for, usage has already been printed
+	ErrHelp = flag.ErrHelp
+)
+
+// parsed command line, only the fields of the chosen subcommand are set
+type Command struct {
+	Name string
+
+	//play and gen
+	Seed  int64
+	World string
+
+	//play and replay
+	ConfigDir string
+	Replay    string
+
+	//play
+	Debug   bool
+	Profile string
+	ResDir  string
+	Record  string
+
+	//server
+	Listen string
+
+	//client
+	Connect string
+	Local   string
+
+	//schema
+	Schema string
+
+	//gen
+	Size int
+	Out  string
+}
+
+// parse the arguments after the program name
+// no subcommand or leading flags run play
+func Parse(args []string, out io.Writer) (*Command, error) {
+	c := &Command{Name: Play}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		c.Name = args[0]
+		args = args[1:]
+	}
+	if c.Name == "help" {
+		Usage(out)
+		return nil, ErrHelp
+	}
+
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	switch c.Name {
+	case Play:
+		fs.Int64Var(&c.Seed, "seed", 0, "world seed, 0 keeps the default")
+		fs.StringVar(&c.World, "world", "terrain", "world kind: "+strings.Join(Worlds, "|"))
+		fs.StringVar(&c.ConfigDir, "config-dir", "", "directory for config files")
+		fs.BoolVar(&c.Debug, "debug", false, "write the log to logs/karalis.log in the config dir, rotated as it grows")
+		fs.StringVar(&c.Profile, "profile", "", "capture a chrome trace of the whole run to this file")
+		fs.StringVar(&c.ResDir, "res-dir", "", "load resources from this folder and reload them when they change")
+		fs.StringVar(&c.Record, "record", "", "record input to this replay file until the game quits")
+		fs.StringVar(&c.Replay, "replay", "", "play back a replay file, starting in its world")
+	case Server:
+		fs.StringVar(&c.Listen, "listen", server.DefaultListen, "address to listen on")
+	case Client:
+		fs.StringVar(&c.Connect, "connect", "", "address of the server")
+		fs.StringVar(&c.Local, "local", client.DefaultLocal, "local address to listen on")
+	case Schema:
+	case Gen:
+		fs.Int64Var(&c.Seed, "seed", 1234567, "world seed")
+		fs.StringVar(&c.World, "world", "terrain", "world kind: "+strings.Join(Worlds, "|"))
+		fs.IntVar(&c.Size, "size", 256, "preview size in pixels")
+		fs.StringVar(&c.Out, "out", "", "png to write, defaults to <world>_<seed>.png")
+	case Replay:
+		fs.StringVar(&c.ConfigDir, "config-dir", "", "directory for config files")
+	default:
+		Usage(out)
+		return nil, fmt.Errorf("Invalid command: %s", c.Name)
+	}
+	fs.Usage = func() {
+		if c.Name == Replay {
+			fmt.Fprintf(out, "usage: karalis %s [flags] <file>\n", c.Name)
+		} else {
+			fmt.Fprintf(out, "usage: karalis %s [flags]\n", c.Name)
+		}
+		fs.PrintDefaults()
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, c.validate(fs.Args())
+}
+
+// check flag values and positional arguments
+func (c *Command) validate(rest []string) error {
+	switch c.Name {
+	case Play, Gen:
+		if !slices.Contains(Worlds, c.World) {
+			return fmt.Errorf("Invalid world: %s", c.World)
+		}
+	case Replay:
+		if len(rest) == 0 {
+			return errors.New("Missing replay file")
+		}
+		c.Replay = rest[0]
+		rest = rest[1:]
+	case Client:
+		if c.Connect == "" {
+			return errors.New("Missing server address: --connect")
+		}
+	case Schema:
+		c.Schema = "all"
+		if len(rest) > 0 {
+			c.Schema = rest[0]
+			rest = rest[1:]
+		}
+		if !slices.Contains(Schemas, c.Schema) {
+			return fmt.Errorf("Invalid schema: %s", c.Schema)
+		}
+	}
+	if c.Name == Play && c.Replay != "" && c.Record != "" {
+		return errors.New("Cannot record while playing back a replay")
+	}
+	if c.Name == Gen {
+		if c.Size < 2 {
+			return fmt.Errorf("Invalid size: %d", c.Size)
+		}
+		if c.Out == "" {
+			c.Out = fmt.Sprintf("%s_%d.png", c.World, c.Seed)
+		}
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("Unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	return nil
+}
+
+// print the subcommands
+func Usage(out io.Writer) {
+	fmt.Fprint(out, `usage: karalis [command] [flags]
+
+commands:
+  play    start the game (default)
+            --seed, --world terrain|city|dungeon, --config-dir, --debug,
+            --profile <file>, --res-dir <dir>, --record <file>, --replay <file>
+  server  run the signal server
+            --listen
+  client  connect to a signal server
+            --connect, --local
+  schema  print json schemas for config and scene files
+            [all|scene|bindings|settings]
+  gen     write a world preview png
+            --seed, --world, --size, --out
+  replay  play back a replay without a window and print where the player ends up
+            --config-dir, <file>
+
+run karalis <command> -h for the flags of a command
+`)
+}
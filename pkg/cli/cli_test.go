@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		args   []string
+		expect Command
+	}{
+		{[]string{}, Command{Name: Play, World: "terrain"}},
+		{[]string{"--debug", "--seed", "42"}, Command{Name: Play, World: "terrain", Seed: 42, Debug: true}},
+		{[]string{"play", "--profile", "trace.json"}, Command{Name: Play, World: "terrain", Profile: "trace.json"}},
+		{[]string{"--res-dir", "res"}, Command{Name: Play, World: "terrain", ResDir: "res"}},
+		{[]string{"play", "--world", "dungeon", "--config-dir", "/tmp/k"}, Command{Name: Play, World: "dungeon", ConfigDir: "/tmp/k"}},
+		{[]string{"--record", "run.json"}, Command{Name: Play, World: "terrain", Record: "run.json"}},
+		{[]string{"--replay", "run.json"}, Command{Name: Play, World: "terrain", Replay: "run.json"}},
+		{[]string{"replay", "run.json"}, Command{Name: Replay, Replay: "run.json"}},
+		{[]string{"replay", "--config-dir", "/tmp/k", "run.json"}, Command{Name: Replay, ConfigDir: "/tmp/k", Replay: "run.json"}},
+		{[]string{"server"}, Command{Name: Server, Listen: ":9595"}},
+		{[]string{"server", "--listen", ":7000"}, Command{Name: Server, Listen: ":7000"}},
+		{[]string{"client", "--connect", "host:9595"}, Command{Name: Client, Connect: "host:9595", Local: ":9595"}},
+		{[]string{"schema"}, Command{Name: Schema, Schema: "all"}},
+		{[]string{"schema", "scene"}, Command{Name: Schema, Schema: "scene"}},
+		{[]string{"gen", "--world", "city", "--seed", "3"}, Command{Name: Gen, World: "city", Seed: 3, Size: 256, Out: "city_3.png"}},
+	}
+	for _, test := range tests {
+		c, err := Parse(test.args, &bytes.Buffer{})
+		if err != nil {
+			t.Error(fmt.Sprintf("Failed to parse %v: %+v", test.args, err))
+			continue
+		}
+		if *c != test.expect {
+			t.Error(fmt.Sprintf("Unexpected command for %v: expected %+v, got %+v", test.args, test.expect, *c))
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		help bool
+	}{
+		{[]string{"help"}, true},
+		{[]string{"play", "-h"}, true},
+		{[]string{"fly"}, false},
+		{[]string{"play", "--world", "moon"}, false},
+		{[]string{"play", "--seed", "abc"}, false},
+		{[]string{"play", "extra"}, false},
+		{[]string{"client"}, false},
+		{[]string{"replay"}, false},
+		{[]string{"replay", "a.json", "b.json"}, false},
+		{[]string{"--record", "a.json", "--replay", "b.json"}, false},
+		{[]string{"schema", "saves"}, false},
+		{[]string{"schema", "scene", "bindings"}, false},
+		{[]string{"gen", "--size", "1"}, false},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.args, &bytes.Buffer{})
+		if err == nil {
+			t.Error(fmt.Sprintf("Expected error parsing %v", test.args))
+			continue
+		}
+		if errors.Is(err, ErrHelp) != test.help {
+			t.Error(fmt.Sprintf("Unexpected help result for %v: %+v", test.args, err))
+		}
+	}
+}
+
+func TestUsageFlags(t *testing.T) {
+	usage := &bytes.Buffer{}
+	Usage(usage)
+
+	tests := []struct {
+		name string
+	}{
+		{Play},
+		{Server},
+		{Client},
+		{Gen},
+		{Replay},
+	}
+	for _, test := range tests {
+		//the flag set prints every registered flag as a line starting with -name
+		out := &bytes.Buffer{}
+		Parse([]string{test.name, "-h"}, out)
+		for _, line := range strings.Split(out.String(), "\n") {
+			if !strings.HasPrefix(line, "  -") {
+				continue
+			}
+			name := strings.Fields(line)[0][1:]
+			if !strings.Contains(usage.String(), "--"+name) {
+				t.Error(fmt.Sprintf("Usage is missing %s --%s", test.name, name))
+			}
+		}
+	}
+}
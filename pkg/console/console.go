@@ -0,0 +1,400 @@
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// lines of output kept for scrolling back
+	MaxLines = 512
+	// lines entered kept for recalling
+	MaxHistory = 128
+)
+
+var (
+	// console shared by the whole engine
+	Default = New()
+)
+
+// command run by typing its name followed by its args
+type Command struct {
+	Name string
+	// usage of the args shown by help, such as "<file>"
+	Args string
+	Help string
+	Run  func(c *Console, args []string) error
+	// suggest values for the arg being typed, args holds the ones before it
+	Complete func(args []string, prefix string) []string
+}
+
+// console variable, typing its name prints it and its name followed by a value sets it
+type Var struct {
+	Name string
+	Help string
+	Get  func() string
+	Set  func(val string) error
+}
+
+// command and variable registry with scrollback and history
+// safe to use from any goroutine, commands run on the goroutine calling Exec
+type Console struct {
+	mu       sync.Mutex
+	commands map[string]*Command
+	vars     map[string]*Var
+	lines    []string
+	partial  string
+	history  []string
+}
+
+func New() *Console {
+	c := &Console{
+		commands: map[string]*Command{},
+		vars:     map[string]*Var{},
+		lines:    []string{},
+		history:  []string{},
+	}
+	c.registerBuiltins()
+	return c
+}
+
+// register a command, replacing one with the same name
+func (c *Console) Register(cmd Command) error {
+	if c == nil {
+		return fmt.Errorf("Invalid console")
+	}
+	if cmd.Name == "" || strings.ContainsAny(cmd.Name, " \t;\"") || cmd.Run == nil {
+		return fmt.Errorf("Invalid command: %q", cmd.Name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.vars[cmd.Name]; ok {
+		return fmt.Errorf("Name already used by a variable: %s", cmd.Name)
+	}
+	c.commands[cmd.Name] = &cmd
+	return nil
+}
+
+// register a variable, replacing one with the same name
+func (c *Console) RegisterVar(v Var) error {
+	if c == nil {
+		return fmt.Errorf("Invalid console")
+	}
+	if v.Name == "" || strings.ContainsAny(v.Name, " \t;\"") || v.Get == nil || v.Set == nil {
+		return fmt.Errorf("Invalid variable: %q", v.Name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.commands[v.Name]; ok {
+		return fmt.Errorf("Name already used by a command: %s", v.Name)
+	}
+	c.vars[v.Name] = &v
+	return nil
+}
+
+// get a variables value
+func (c *Console) GetVar(name string) (string, error) {
+	v := c.lookupVar(name)
+	if v == nil {
+		return "", fmt.Errorf("Unknown variable: %s", name)
+	}
+	return v.Get(), nil
+}
+
+// set a variables value
+func (c *Console) SetVar(name string, val string) error {
+	v := c.lookupVar(name)
+	if v == nil {
+		return fmt.Errorf("Unknown variable: %s", name)
+	}
+	return v.Set(val)
+}
+
+func (c *Console) lookupVar(name string) *Var {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.vars[name]
+}
+
+func (c *Console) lookupCommand(name string) *Command {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.commands[name]
+}
+
+// run a line typed by the user, recording it in the history
+func (c *Console) Submit(line string) error {
+	if c == nil {
+		return fmt.Errorf("Invalid console")
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	c.mu.Lock()
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+		if len(c.history) > MaxHistory {
+			c.history = slices.Delete(c.history, 0, len(c.history)-MaxHistory)
+		}
+	}
+	c.mu.Unlock()
+
+	c.Printf("> %s\n", line)
+	err := c.Exec(line)
+	if err != nil {
+		c.Printf("ERR: %+v\n", err)
+	}
+	return err
+}
+
+// run a line of commands separated by ;
+// every statement runs, the first error is returned
+func (c *Console) Exec(line string) error {
+	if c == nil {
+		return fmt.Errorf("Invalid console")
+	}
+
+	var first error
+	for _, stmt := range Split(line) {
+		err := c.run(stmt)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// run one statement
+func (c *Console) run(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	name := args[0]
+	if cmd := c.lookupCommand(name); cmd != nil {
+		return cmd.Run(c, args[1:])
+	}
+	if v := c.lookupVar(name); v != nil {
+		switch len(args) {
+		case 1:
+			c.Printf("%s = %q\n", name, v.Get())
+			return nil
+		case 2:
+			return v.Set(args[1])
+		default:
+			return fmt.Errorf("Too many values for %s", name)
+		}
+	}
+	return fmt.Errorf("Unknown command: %s", name)
+}
+
+// run every line of a file, blank lines and lines starting with // or # are skipped
+func (c *Console) ExecFile(path string) error {
+	if c == nil {
+		return fmt.Errorf("Invalid console")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var first error
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		err = c.Exec(line)
+		if err != nil {
+			c.Printf("ERR: %s:%d: %+v\n", path, n, err)
+			if first == nil {
+				first = fmt.Errorf("%s:%d: %+v", path, n, err)
+			}
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return err
+	}
+	return first
+}
+
+// get completions for the line being typed, each a full replacement for the line
+func (c *Console) Complete(line string) []string {
+	if c == nil {
+		return []string{}
+	}
+
+	//only the last statement is completed
+	head := ""
+	if i := strings.LastIndex(line, ";"); i >= 0 {
+		head = line[:i+1] + " "
+		line = strings.TrimLeft(line[i+1:], " \t")
+	}
+
+	fields := strings.Fields(line)
+	typing := len(fields) == 0 || !strings.HasSuffix(line, " ")
+	prefix := ""
+	if typing && len(fields) > 0 {
+		prefix = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	matches := []string{}
+	if len(fields) == 0 {
+		matches = c.matchNames(prefix)
+	} else if cmd := c.lookupCommand(fields[0]); cmd != nil && cmd.Complete != nil {
+		matches = cmd.Complete(fields[1:], prefix)
+		sort.Strings(matches)
+	}
+
+	base := head + strings.Join(fields, " ")
+	if len(fields) > 0 {
+		base += " "
+	}
+	out := make([]string, len(matches))
+	for i, match := range matches {
+		out[i] = base + match
+	}
+	return out
+}
+
+// write to the scrollback, lines are split on newlines
+func (c *Console) Write(p []byte) (int, error) {
+	if c == nil {
+		return 0, fmt.Errorf("Invalid console")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	text := c.partial + string(p)
+	parts := strings.Split(text, "\n")
+	c.partial = parts[len(parts)-1]
+	c.lines = append(c.lines, parts[:len(parts)-1]...)
+	if len(c.lines) > MaxLines {
+		c.lines = slices.Delete(c.lines, 0, len(c.lines)-MaxLines)
+	}
+	return len(p), nil
+}
+
+// print to the scrollback
+func (c *Console) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(c, format, args...)
+}
+
+// get the scrollback, oldest line first
+func (c *Console) GetLines() []string {
+	if c == nil {
+		return []string{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.lines)
+}
+
+// clear the scrollback
+func (c *Console) Clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = []string{}
+	c.partial = ""
+}
+
+// get the lines entered, oldest first
+func (c *Console) GetHistory() []string {
+	if c == nil {
+		return []string{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.history)
+}
+
+// get the names of the registered commands and variables in order
+func (c *Console) Names() []string {
+	if c == nil {
+		return []string{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := []string{}
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	for name := range c.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// split a line into statements of args
+// args are separated by spaces, statements by ; and double quotes group spaces into one arg
+func Split(line string) [][]string {
+	stmts := [][]string{}
+	args := []string{}
+	arg := strings.Builder{}
+	quoted := false
+	inArg := false
+
+	endArg := func() {
+		if inArg {
+			args = append(args, arg.String())
+			arg.Reset()
+			inArg = false
+		}
+	}
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case quoted:
+			arg.WriteRune(r)
+		case r == ' ' || r == '\t':
+			endArg()
+		case r == ';':
+			endArg()
+			if len(args) > 0 {
+				stmts = append(stmts, args)
+			}
+			args = []string{}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	endArg()
+	if len(args) > 0 {
+		stmts = append(stmts, args)
+	}
+	return stmts
+}
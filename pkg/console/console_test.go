@@ -0,0 +1,226 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line   string
+		expect [][]string
+	}{
+		{"", [][]string{}},
+		{"echo hi", [][]string{{"echo", "hi"}}},
+		{"  a   b\tc ", [][]string{{"a", "b", "c"}}},
+		{"a;b c ; ;d", [][]string{{"a"}, {"b", "c"}, {"d"}}},
+		{`echo "hello world" x`, [][]string{{"echo", "hello world", "x"}}},
+		{`echo "a;b"`, [][]string{{"echo", "a;b"}}},
+		{`echo ""`, [][]string{{"echo", ""}}},
+	}
+	for i, test := range tests {
+		out := Split(test.line)
+		if len(out) != len(test.expect) {
+			t.Error(fmt.Sprintf("Unexpected split for case %d: expected %q, got %q", i, test.expect, out))
+			continue
+		}
+		for j := range out {
+			if !slices.Equal(out[j], test.expect[j]) {
+				t.Error(fmt.Sprintf("Unexpected split for case %d: expected %q, got %q", i, test.expect, out))
+			}
+		}
+	}
+}
+
+// console with an int variable and a command recording its args
+func newTestConsole(val *int, got *[]string) *Console {
+	c := New()
+	c.RegisterVar(IntVar("t_val", "test value",
+		func() int { return *val },
+		func(v int) error {
+			if v < 0 {
+				return fmt.Errorf("Negative value")
+			}
+			*val = v
+			return nil
+		}))
+	c.Register(Command{
+		Name: "record",
+		Args: "<args>",
+		Run: func(c *Console, args []string) error {
+			*got = append(*got, args...)
+			return nil
+		},
+		Complete: func(args []string, prefix string) []string {
+			return []string{prefix + "1", prefix + "2"}
+		},
+	})
+	return c
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		line   string
+		err    bool
+		val    int
+		record []string
+		output string
+	}{
+		{"t_val 5", false, 5, []string{}, ""},
+		{"t_val", false, 0, []string{}, `t_val = "0"`},
+		{"t_val x", true, 0, []string{}, ""},
+		{"t_val -1", true, 0, []string{}, ""},
+		{"t_val 1 2", true, 0, []string{}, ""},
+		{"record a b; t_val 3", false, 3, []string{"a", "b"}, ""},
+		{"missing; record c", true, 0, []string{"c"}, ""},
+		{"echo hello there", false, 0, []string{}, "hello there"},
+		{"help t_val", false, 0, []string{}, `t_val = "0" - test value`},
+	}
+	for i, test := range tests {
+		val := 0
+		record := []string{}
+		c := newTestConsole(&val, &record)
+
+		err := c.Exec(test.line)
+		if (err != nil) != test.err {
+			t.Error(fmt.Sprintf("Unexpected error for case %d: %+v", i, err))
+		}
+		if val != test.val {
+			t.Error(fmt.Sprintf("Unexpected value for case %d: expected %d, got %d", i, test.val, val))
+		}
+		if !slices.Equal(record, test.record) {
+			t.Error(fmt.Sprintf("Unexpected args for case %d: expected %q, got %q", i, test.record, record))
+		}
+		lines := c.GetLines()
+		if test.output != "" && !slices.Contains(lines, test.output) {
+			t.Error(fmt.Sprintf("Unexpected output for case %d: expected %q, got %q", i, test.output, lines))
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	c := New()
+	run := func(c *Console, args []string) error { return nil }
+	tests := []struct {
+		err error
+	}{
+		{c.Register(Command{Name: "ok", Run: run})},
+		{c.RegisterVar(StringVar("ok_var", "", func() string { return "" }, func(string) error { return nil }))},
+	}
+	for i, test := range tests {
+		if test.err != nil {
+			t.Error(fmt.Sprintf("Unexpected register error for case %d: %+v", i, test.err))
+		}
+	}
+
+	invalid := []error{
+		c.Register(Command{Name: "", Run: run}),
+		c.Register(Command{Name: "a b", Run: run}),
+		c.Register(Command{Name: "a;b", Run: run}),
+		c.Register(Command{Name: "norun"}),
+		c.Register(Command{Name: "ok_var", Run: run}),
+		c.RegisterVar(StringVar("ok", "", func() string { return "" }, func(string) error { return nil })),
+	}
+	for i, err := range invalid {
+		if err == nil {
+			t.Error(fmt.Sprintf("Invalid registration %d was accepted", i))
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		line   string
+		expect []string
+	}{
+		{"t_", []string{"t_val"}},
+		{"rec", []string{"record"}},
+		{"he", []string{"help"}},
+		{"record ", []string{"record 1", "record 2"}},
+		{"record x y", []string{"record x y1", "record x y2"}},
+		{"help t_", []string{"help t_val"}},
+		{"echo a; t_", []string{"echo a; t_val"}},
+		{"t_val ", []string{}},
+		{"zzz", []string{}},
+	}
+	val := 0
+	record := []string{}
+	c := newTestConsole(&val, &record)
+	for i, test := range tests {
+		out := c.Complete(test.line)
+		if !slices.Equal(out, test.expect) {
+			t.Error(fmt.Sprintf("Unexpected completions for case %d: expected %q, got %q", i, test.expect, out))
+		}
+	}
+}
+
+func TestExecFile(t *testing.T) {
+	tests := []struct {
+		file   string
+		err    bool
+		val    int
+		record []string
+	}{
+		{"t_val 4\n", false, 4, []string{}},
+		{"// comment\n# comment\n\n  record a\nt_val 2\n", false, 2, []string{"a"}},
+		{"t_val 1\nbogus\nt_val 3\n", true, 3, []string{}},
+	}
+	for i, test := range tests {
+		val := 0
+		record := []string{}
+		c := newTestConsole(&val, &record)
+
+		path := filepath.Join(t.TempDir(), "autoexec.cfg")
+		err := os.WriteFile(path, []byte(test.file), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.ExecFile(path)
+		if (err != nil) != test.err {
+			t.Error(fmt.Sprintf("Unexpected error for case %d: %+v", i, err))
+		}
+		if val != test.val || !slices.Equal(record, test.record) {
+			t.Error(fmt.Sprintf("Unexpected state for case %d: got %d %q", i, val, record))
+		}
+	}
+
+	c := New()
+	err := c.ExecFile(filepath.Join(t.TempDir(), "missing.cfg"))
+	if !os.IsNotExist(err) {
+		t.Error(fmt.Sprintf("Expected a missing file error, got %+v", err))
+	}
+}
+
+func TestLimits(t *testing.T) {
+	c := New()
+	for i := 0; i < MaxHistory+10; i++ {
+		c.Submit(fmt.Sprintf("echo %d", i))
+	}
+	c.Submit("echo last")
+	c.Submit("echo last")
+	history := c.GetHistory()
+	if len(history) != MaxHistory || history[len(history)-1] != "echo last" || history[len(history)-2] == "echo last" {
+		t.Error(fmt.Sprintf("Unexpected history: %d lines ending in %q", len(history), history[len(history)-2:]))
+	}
+
+	c.Clear()
+	c.Printf("partial ")
+	c.Printf("line\n")
+	for i := 0; i < MaxLines; i++ {
+		c.Printf("%d\n", i)
+	}
+	lines := c.GetLines()
+	if len(lines) != MaxLines || lines[0] != "0" || lines[len(lines)-1] != fmt.Sprint(MaxLines-1) {
+		t.Error(fmt.Sprintf("Unexpected scrollback: %d lines from %q to %q", len(lines), lines[0], lines[len(lines)-1]))
+	}
+
+	c.Clear()
+	c.Printf("partial ")
+	c.Printf("line\n")
+	if lines := c.GetLines(); len(lines) != 1 || lines[0] != "partial line" {
+		t.Error(fmt.Sprintf("Unexpected joined line: %q", lines))
+	}
+}
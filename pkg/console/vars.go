@@ -0,0 +1,210 @@
+package console
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// variable holding an int
+func IntVar(name string, help string, get func() int, set func(int) error) Var {
+	return Var{
+		Name: name,
+		Help: help,
+		Get: func() string {
+			return strconv.Itoa(get())
+		},
+		Set: func(val string) error {
+			i, err := strconv.Atoi(val)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %s: %s", name, val)
+			}
+			return set(i)
+		},
+	}
+}
+
+// variable holding an int64
+func Int64Var(name string, help string, get func() int64, set func(int64) error) Var {
+	return Var{
+		Name: name,
+		Help: help,
+		Get: func() string {
+			return strconv.FormatInt(get(), 10)
+		},
+		Set: func(val string) error {
+			i, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %s: %s", name, val)
+			}
+			return set(i)
+		},
+	}
+}
+
+// variable holding a float
+func FloatVar(name string, help string, get func() float64, set func(float64) error) Var {
+	return Var{
+		Name: name,
+		Help: help,
+		Get: func() string {
+			return strconv.FormatFloat(get(), 'g', -1, 64)
+		},
+		Set: func(val string) error {
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %s: %s", name, val)
+			}
+			return set(f)
+		},
+	}
+}
+
+// variable holding a bool, set with 1/0 or true/false
+func BoolVar(name string, help string, get func() bool, set func(bool) error) Var {
+	return Var{
+		Name: name,
+		Help: help,
+		Get: func() string {
+			if get() {
+				return "1"
+			}
+			return "0"
+		},
+		Set: func(val string) error {
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %s: %s", name, val)
+			}
+			return set(b)
+		},
+	}
+}
+
+// variable holding a string
+func StringVar(name string, help string, get func() string, set func(string) error) Var {
+	return Var{
+		Name: name,
+		Help: help,
+		Get:  get,
+		Set:  set,
+	}
+}
+
+// commands every console has
+func (c *Console) registerBuiltins() {
+	c.Register(Command{
+		Name: "help",
+		Args: "[name]",
+		Help: "list commands and variables or describe one",
+		Run: func(c *Console, args []string) error {
+			if len(args) == 0 {
+				for _, name := range c.Names() {
+					c.Printf("  %s\n", name)
+				}
+				return nil
+			}
+			if cmd := c.lookupCommand(args[0]); cmd != nil {
+				c.Printf("%s %s - %s\n", cmd.Name, cmd.Args, cmd.Help)
+				return nil
+			}
+			if v := c.lookupVar(args[0]); v != nil {
+				c.Printf("%s = %q - %s\n", v.Name, v.Get(), v.Help)
+				return nil
+			}
+			return fmt.Errorf("Unknown command: %s", args[0])
+		},
+		Complete: func(args []string, prefix string) []string {
+			if len(args) > 0 {
+				return []string{}
+			}
+			return c.matchNames(prefix)
+		},
+	})
+	c.Register(Command{
+		Name: "echo",
+		Args: "<text>",
+		Help: "print text",
+		Run: func(c *Console, args []string) error {
+			c.Printf("%s\n", strings.Join(args, " "))
+			return nil
+		},
+	})
+	c.Register(Command{
+		Name: "clear",
+		Help: "clear the output",
+		Run: func(c *Console, args []string) error {
+			c.Clear()
+			return nil
+		},
+	})
+	c.Register(Command{
+		Name: "history",
+		Help: "list the lines entered",
+		Run: func(c *Console, args []string) error {
+			for i, line := range c.GetHistory() {
+				c.Printf("%4d  %s\n", i+1, line)
+			}
+			return nil
+		},
+	})
+	c.Register(Command{
+		Name: "vars",
+		Args: "[prefix]",
+		Help: "list variables and their values",
+		Run: func(c *Console, args []string) error {
+			prefix := ""
+			if len(args) > 0 {
+				prefix = args[0]
+			}
+			for _, name := range c.Names() {
+				if v := c.lookupVar(name); v != nil && strings.HasPrefix(name, prefix) {
+					c.Printf("  %s = %q\n", name, v.Get())
+				}
+			}
+			return nil
+		},
+	})
+	c.Register(Command{
+		Name: "exec",
+		Args: "<file>",
+		Help: "run the lines of a file",
+		Run: func(c *Console, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Usage: exec <file>")
+			}
+			return c.ExecFile(args[0])
+		},
+	})
+}
+
+// names of commands and variables starting with prefix
+func (c *Console) matchNames(prefix string) []string {
+	matches := []string{}
+	for _, name := range c.Names() {
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
+
+// register a command on the default console
+func Register(cmd Command) error {
+	return Default.Register(cmd)
+}
+
+// register a variable on the default console
+func RegisterVar(v Var) error {
+	return Default.RegisterVar(v)
+}
+
+// run a line on the default console
+func Exec(line string) error {
+	return Default.Exec(line)
+}
+
+// print to the default console
+func Printf(format string, args ...interface{}) {
+	Default.Printf(format, args...)
+}
@@ -0,0 +1,113 @@
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"karalis/pkg/config"
+	"karalis/pkg/logging"
+	"karalis/pkg/settings"
+)
+
+// state of the app when a goroutine panicked
+type Report struct {
+	Time time.Time
+	// loop the panic happened on
+	Thread string
+	Panic  string
+	Stack  string
+	// stages bottom to top
+	Stages []string
+	// empty when there was no player
+	Player   string
+	Seed     int64
+	Settings settings.Settings
+	Log      []string
+}
+
+// start a report for a recovered panic, the caller fills in the app state
+func NewReport(thread string, val interface{}, stack []byte) *Report {
+	return &Report{
+		Time:     time.Now(),
+		Thread:   thread,
+		Panic:    fmt.Sprint(val),
+		Stack:    string(stack),
+		Stages:   []string{},
+		Settings: settings.Get(),
+		Log:      logging.GetLines(),
+	}
+}
+
+// write the report as text
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	if r == nil {
+		return 0, fmt.Errorf("Invalid report")
+	}
+
+	cfg, err := json.MarshalIndent(r.Settings, "", "    ")
+	if err != nil {
+		return 0, err
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s crash report\n", config.AppName)
+	fmt.Fprintf(b, "time: %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(b, "thread: %s\n", r.Thread)
+	fmt.Fprintf(b, "panic: %s\n", r.Panic)
+	fmt.Fprintf(b, "stages: %s\n", strings.Join(r.Stages, " > "))
+	if r.Player != "" {
+		fmt.Fprintf(b, "player: %s\n", r.Player)
+	}
+	fmt.Fprintf(b, "seed: %d\n", r.Seed)
+	fmt.Fprintf(b, "\nsettings:\n%s\n", cfg)
+	fmt.Fprintf(b, "\nlog:\n")
+	for _, line := range r.Log {
+		fmt.Fprintf(b, "%s\n", line)
+	}
+	fmt.Fprintf(b, "\nstack:\n%s", r.Stack)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// folder in the config dir crash reports are written to
+func Dir() string {
+	return filepath.Join(config.ConfigDir, "crashes")
+}
+
+// write the report to a new file in dir named after its time and get the path
+func (r *Report) Save(dir string) (string, error) {
+	if r == nil {
+		return "", fmt.Errorf("Invalid report")
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	base := "crash_" + r.Time.Format("20060102_150405")
+	path := filepath.Join(dir, base+".txt")
+	for i := 1; exists(path); i++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s_%d.txt", base, i))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	_, err = r.WriteTo(file)
+	if err != nil {
+		return "", err
+	}
+	return path, file.Close()
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
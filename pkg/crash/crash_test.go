@@ -0,0 +1,51 @@
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	dir := t.TempDir()
+	r := NewReport("logic", fmt.Errorf("boom"), []byte("goroutine 1 [running]:\n"))
+	r.Time = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	r.Stages = []string{"*stage.Game", "*stage.Pause"}
+	r.Player = "(1, 2, 3)"
+	r.Seed = 42
+	r.Log = []string{"first", "last"}
+
+	expect := []string{"crash_20240506_070809.txt", "crash_20240506_070809_1.txt"}
+	for i, name := range expect {
+		path, err := r.Save(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(path) != name {
+			t.Error(fmt.Sprintf("Unexpected report name %d: expected %s, got %s", i, name, filepath.Base(path)))
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, expect[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txt := string(data)
+	for _, part := range []string{
+		"thread: logic\n",
+		"panic: boom\n",
+		"stages: *stage.Game > *stage.Pause\n",
+		"player: (1, 2, 3)\n",
+		"seed: 42\n",
+		"\"fov\": 45",
+		"log:\nfirst\nlast\n",
+		"stack:\ngoroutine 1 [running]:\n",
+	} {
+		if !strings.Contains(txt, part) {
+			t.Error(fmt.Sprintf("Report is missing %q:\n%s", part, txt))
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package event
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+
+	"karalis/pkg/object"
+)
+
+var (
+	// bus shared by the whole engine
+	Default = NewBus()
+)
+
+// publish/subscribe hub dispatching events by their go type
+// handlers may be added, removed and published to from any goroutine
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[reflect.Type][]*Subscription
+
+	qmu    sync.Mutex
+	queued []func()
+}
+
+// handle to a subscription, cancel it to stop receiving events
+type Subscription struct {
+	bus    *Bus
+	typ    reflect.Type
+	handle func(interface{})
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subs:   map[reflect.Type][]*Subscription{},
+		queued: []func(){},
+	}
+}
+
+// subscribe fn to events of type E
+func Subscribe[E any](b *Bus, fn func(E)) *Subscription {
+	if b == nil || fn == nil {
+		return nil
+	}
+
+	typ := reflect.TypeFor[E]()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &Subscription{
+		bus: b,
+		typ: typ,
+		handle: func(e interface{}) {
+			fn(e.(E))
+		},
+	}
+	b.subs[typ] = append(b.subs[typ], s)
+	return s
+}
+
+// subscribe fn to events of type E for as long as owner is in the tree
+// the subscription is cancelled when the owners OnRemove releases its meta
+func SubscribeFor[E any](b *Bus, owner object.Object, fn func(E)) *Subscription {
+	s := Subscribe(b, fn)
+	if s != nil && owner != nil {
+		owner.GetMeta().OnRelease(s.Cancel)
+	}
+	return s
+}
+
+// deliver an event to its subscribers right away on the calling goroutine
+func Publish[E any](b *Bus, e E) {
+	if b == nil {
+		return
+	}
+
+	typ := reflect.TypeFor[E]()
+	b.mu.RLock()
+	subs := slices.Clone(b.subs[typ])
+	b.mu.RUnlock()
+
+	for _, s := range subs {
+		s.handle(e)
+	}
+}
+
+// queue an event to be delivered by the next Flush
+func Post[E any](b *Bus, e E) {
+	if b == nil {
+		return
+	}
+
+	b.qmu.Lock()
+	b.queued = append(b.queued, func() {
+		Publish(b, e)
+	})
+	b.qmu.Unlock()
+}
+
+// deliver queued events in the order they were posted
+// events posted by handlers during the flush wait for the next one
+func (b *Bus) Flush() {
+	if b == nil {
+		return
+	}
+
+	b.qmu.Lock()
+	queued := b.queued
+	b.queued = []func(){}
+	b.qmu.Unlock()
+
+	for _, deliver := range queued {
+		deliver()
+	}
+}
+
+// get the number of subscribers for events of type E
+func Count[E any](b *Bus) int {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs[reflect.TypeFor[E]()])
+}
+
+func (s *Subscription) Cancel() {
+	if s == nil {
+		return
+	}
+
+	b := s.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[s.typ]
+	index := slices.Index(subs, s)
+	if index < 0 {
+		return
+	}
+	b.subs[s.typ] = slices.Delete(slices.Clone(subs), index, index+1)
+	if len(b.subs[s.typ]) == 0 {
+		delete(b.subs, s.typ)
+	}
+}
+
+// subscribe to the default bus
+func On[E any](fn func(E)) *Subscription {
+	return Subscribe(Default, fn)
+}
+
+// subscribe to the default bus until owner is removed
+func OnFor[E any](owner object.Object, fn func(E)) *Subscription {
+	return SubscribeFor(Default, owner, fn)
+}
+
+// publish to the default bus
+func Emit[E any](e E) {
+	Publish(Default, e)
+}
+
+// queue onto the default bus
+func Queue[E any](e E) {
+	Post(Default, e)
+}
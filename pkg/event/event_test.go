@@ -0,0 +1,120 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"karalis/pkg/object"
+)
+
+type testOwner struct {
+	object.Object
+
+	meta object.Meta
+}
+
+func (o *testOwner) GetMeta() *object.Meta { return &o.meta }
+func (o *testOwner) OnRemove()             { o.meta.Release() }
+
+func TestPublish(t *testing.T) {
+	b := NewBus()
+	got := []string{}
+	sub := Subscribe(b, func(e ActionPressed) { got = append(got, "a:"+e.Action) })
+	Subscribe(b, func(e ActionPressed) { got = append(got, "b:"+e.Action) })
+	Subscribe(b, func(e ActionReleased) { got = append(got, "released") })
+
+	Publish(b, ActionPressed{Action: "jump"})
+	sub.Cancel()
+	sub.Cancel()
+	Publish(b, ActionPressed{Action: "crouch"})
+
+	want := []string{"a:jump", "b:jump", "b:crouch"}
+	if !slices.Equal(got, want) {
+		t.Error(fmt.Sprintf("TestPublish got %v", got))
+	}
+	if Count[ActionPressed](b) != 1 || Count[WindowResized](b) != 0 {
+		t.Error("TestPublish count")
+	}
+}
+
+func TestQueued(t *testing.T) {
+	b := NewBus()
+	got := []int32{}
+	Subscribe(b, func(e WindowResized) {
+		got = append(got, e.Width)
+		//posted while flushing so it waits for the next flush
+		if e.Width == 1 {
+			Post(b, WindowResized{Width: 3})
+		}
+	})
+
+	Post(b, WindowResized{Width: 1})
+	Post(b, WindowResized{Width: 2})
+	if len(got) != 0 {
+		t.Error("TestQueued delivered before flush")
+	}
+	b.Flush()
+	if !slices.Equal(got, []int32{1, 2}) {
+		t.Error(fmt.Sprintf("TestQueued first flush %v", got))
+	}
+	b.Flush()
+	if !slices.Equal(got, []int32{1, 2, 3}) {
+		t.Error(fmt.Sprintf("TestQueued second flush %v", got))
+	}
+}
+
+func TestScoped(t *testing.T) {
+	b := NewBus()
+	owner := &testOwner{}
+	calls := 0
+	SubscribeFor(b, owner, func(e PeerJoined) { calls++ })
+
+	Publish(b, PeerJoined{Addr: "a"})
+	owner.OnRemove()
+	Publish(b, PeerJoined{Addr: "b"})
+	if calls != 1 || Count[PeerJoined](b) != 0 {
+		t.Error(fmt.Sprintf("TestScoped calls %d", calls))
+	}
+}
+
+// publishers, subscribers and the flushing loop on separate goroutines, run with -race
+func TestConcurrent(t *testing.T) {
+	b := NewBus()
+	mu := sync.Mutex{}
+	count := 0
+	Subscribe(b, func(e BindingChanged) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+
+	wg := sync.WaitGroup{}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				Post(b, BindingChanged{Key: "K"})
+				sub := Subscribe(b, func(e StageChanged) {})
+				Publish(b, StageChanged{})
+				sub.Cancel()
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			b.Flush()
+		}
+	}()
+	wg.Wait()
+	<-done
+	b.Flush()
+
+	if count != 400 {
+		t.Error(fmt.Sprintf("TestConcurrent delivered %d", count))
+	}
+}
@@ -0,0 +1,48 @@
+package event
+
+import (
+	"karalis/pkg/object"
+	"karalis/pkg/stage"
+)
+
+// the player moved into a different cell of a world
+type CellEntered struct {
+	World object.Object
+	Cell  object.Object
+	X     int
+	Z     int
+}
+
+// the app switched to another stage
+type StageChanged struct {
+	Prev stage.Stage
+	Next stage.Stage
+}
+
+// the window changed size
+type WindowResized struct {
+	Width  int32
+	Height int32
+}
+
+// a key was bound to an action, or unbound when action is empty
+type BindingChanged struct {
+	Scope  string
+	Key    string
+	Action string
+}
+
+// an input action was pressed
+type ActionPressed struct {
+	Action string
+}
+
+// an input action was released
+type ActionReleased struct {
+	Action string
+}
+
+// another peer became known to the network layer
+type PeerJoined struct {
+	Addr string
+}
@@ -6,12 +6,13 @@ package goray
 #include "raymath.h"
 
 // Update buffers
-void UpdateModelUVs(Model* mdl) {
+static void UpdateModelUVs(Model* mdl) {
 	UpdateMeshBuffer(mdl->meshes[0], 1, &mdl->meshes->texcoords[0], mdl->meshes->vertexCount*2*sizeof(float), 0);
 }
 */
 import "C"
 import (
+	"sync"
 	"unsafe"
 
 	raylib "github.com/gen2brain/raylib-go/raylib"
@@ -38,6 +39,10 @@ var (
 	REPLACE = uint32(gl.REPLACE)
 	INCR_W  = uint32(gl.INCR_WRAP)
 	DECR_W  = uint32(gl.DECR_WRAP)
+
+	//gl functions are loaded on first use, once a context exists
+	initGL  sync.Once
+	initErr error
 )
 
 func UpdateModelUVs(mdl *raylib.Model) {
@@ -99,3 +104,31 @@ func SetStencilClearValue(value int32) {
 func SetStencilFuncSeparate(face uint32, fun uint32, ref int32, mask uint32) {
 	gl.StencilFuncSeparate(face, fun, ref, mask)
 }
+
+// load the gl functions, needs the window context current
+func Init() error {
+	initGL.Do(func() {
+		initErr = gl.Init()
+	})
+	return initErr
+}
+
+// make a combined depth and stencil renderbuffer and attach it to a framebuffer, returning the renderbuffer
+// unloading the framebuffer frees the renderbuffer with it
+func AttachDepthStencil(framebuffer uint32, width int32, height int32) (uint32, error) {
+	err := Init()
+	if err != nil {
+		return 0, err
+	}
+
+	var rb uint32
+	gl.GenRenderbuffers(1, &rb)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, rb)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, width, height)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, framebuffer)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, rb)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	return rb, nil
+}
@@ -0,0 +1,102 @@
+package input
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"karalis/pkg/console"
+)
+
+// console commands for changing bindings, changes are saved to bindings.json
+func init() {
+	console.Register(console.Command{
+		Name: "bind",
+		Args: "<scope> <key> <action>",
+		Help: "bind a key or key combo to an action",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) != 3 {
+				return fmt.Errorf("Usage: bind <scope> <key> <action>")
+			}
+			_, err := TryBindKey(args[0], args[1], args[2], true)
+			return err
+		},
+		Complete: completeBinding,
+	})
+	console.Register(console.Command{
+		Name: "unbind",
+		Args: "<scope> <key>",
+		Help: "remove the binding of a key",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("Usage: unbind <scope> <key>")
+			}
+			return UnbindKey(args[0], args[1])
+		},
+		Complete: func(args []string, prefix string) []string {
+			if len(args) > 1 {
+				return []string{}
+			}
+			return completeBinding(args, prefix)
+		},
+	})
+	console.Register(console.Command{
+		Name: "bindings",
+		Args: "[scope]",
+		Help: "list the bound keys",
+		Run: func(c *console.Console, args []string) error {
+			for _, scope := range Scopes {
+				if len(args) > 0 && args[0] != scope {
+					continue
+				}
+				keys := []string{}
+				for k := range Bindings[scope] {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					c.Printf("  %s %s %s\n", scope, k, Bindings[scope][k])
+				}
+			}
+			return nil
+		},
+		Complete: func(args []string, prefix string) []string {
+			if len(args) > 0 {
+				return []string{}
+			}
+			return matchPrefix(Scopes, prefix)
+		},
+	})
+}
+
+// complete the scope, then the keys bound in it, then the actions
+func completeBinding(args []string, prefix string) []string {
+	switch len(args) {
+	case 0:
+		return matchPrefix(Scopes, prefix)
+	case 1:
+		keys := []string{}
+		for k := range Bindings[args[0]] {
+			keys = append(keys, k)
+		}
+		return matchPrefix(keys, prefix)
+	case 2:
+		actions := []string{}
+		for action := range Actions {
+			actions = append(actions, action)
+		}
+		return matchPrefix(actions, prefix)
+	}
+	return []string{}
+}
+
+func matchPrefix(names []string, prefix string) []string {
+	matches := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) && !slices.Contains(matches, name) {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
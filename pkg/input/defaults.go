@@ -26,6 +26,22 @@ func DefaultBindings(scope string) map[string]string {
 			"Space":       "MoveUp",
 			"LeftControl": "MoveDown",
 			"LeftShift":   "MoveFast",
+			"Escape":      "Pause",
+			"Grave":       "ToggleConsole",
+			"F3":          "ToggleProfiler",
+			"F12":         "Screenshot",
+		}
+	case "Menu":
+		return map[string]string{
+			"Escape": "Pause",
+			"Grave":  "ToggleConsole",
+			"F3":     "ToggleProfiler",
+			"F12":    "Screenshot",
+		}
+	case "Console":
+		return map[string]string{
+			"Grave": "ToggleConsole",
+			"F3":    "ToggleProfiler",
 		}
 	default:
 		return map[string]string{}
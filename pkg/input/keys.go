@@ -5,12 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"karalis/pkg/config"
+	"karalis/pkg/event"
+	"karalis/pkg/logging"
 
 	raylib "github.com/gen2brain/raylib-go/raylib"
 	json5 "github.com/zyedidia/json5"
@@ -18,9 +19,12 @@ import (
 
 var (
 	Bindings map[string]map[string]string = make(map[string]map[string]string)
+	Scopes   []string                     = []string{"Character", "Menu", "Console"}
 
 	prevControls map[string]map[string]bool = make(map[string]map[string]bool)
 	lastGesture  raylib.Gestures            = raylib.GestureNone
+
+	logger = logging.For(logging.Input)
 )
 
 type Binding struct {
@@ -32,7 +36,9 @@ type Binding struct {
 
 // initialize bindings
 func InitBindings() error {
-	Bindings["Character"] = map[string]string{}
+	for _, scope := range Scopes {
+		Bindings[scope] = map[string]string{}
+	}
 	for scope, _ := range Bindings {
 		Bindings[scope] = DefaultBindings(scope)
 	}
@@ -241,8 +247,8 @@ func SearchCombos(scope string, key string, mods *map[string]bool, nextControls
 		keys = keys[1:]
 		if action, ok := Bindings[scope][combo]; ok {
 			//handle action being pressed
-			if err := handleBindingPress(action); err != nil {
-				log.Printf("%+v\n", err)
+			if err := handleBindingPress(scope, action); err != nil {
+				logger.Error("press", "action", action, "err", err)
 			}
 			//store that this action is pressed for tracking
 			(*nextControls)[action] = true
@@ -256,8 +262,13 @@ func SearchCombos(scope string, key string, mods *map[string]bool, nextControls
 	}
 }
 
-// handle input keys
+// handle input keys, while a replay plays its actions are used instead of the devices
 func HandleInput(scope string) {
+	if playing != nil {
+		playbackInput(scope)
+		return
+	}
+
 	keys := GetKeysPressed()
 	mods := GetModsPressed()
 	nextControls := map[string]bool{}
@@ -274,8 +285,8 @@ func HandleInput(scope string) {
 
 	//handle button releases
 	for control, _ := range prevControls[scope] {
-		if err := handleBindingRelease(control); err != nil {
-			log.Printf("%+v\n", err)
+		if err := handleBindingRelease(scope, control); err != nil {
+			logger.Error("release", "action", control, "err", err)
 		}
 	}
 	//update previous pressed
@@ -285,13 +296,15 @@ func HandleInput(scope string) {
 }
 
 // handle a binding action being pressed
-func handleBindingPress(action string) error {
+func handleBindingPress(scope string, action string) error {
 	if binding, ok := Actions[action]; ok {
 		if !binding.Pressed {
 			binding.Pressed = true
+			recordAction(scope, action, true)
 			if binding.OnPress != nil {
 				(*binding.OnPress)()
 			}
+			event.Emit(event.ActionPressed{Action: action})
 		}
 	} else {
 		return errors.New("Invalid action")
@@ -300,13 +313,15 @@ func handleBindingPress(action string) error {
 }
 
 // handle a binding action being released
-func handleBindingRelease(action string) error {
+func handleBindingRelease(scope string, action string) error {
 	if binding, ok := Actions[action]; ok {
 		if binding.Pressed {
 			binding.Pressed = false
+			recordAction(scope, action, false)
 			if binding.OnRelease != nil {
 				(*binding.OnRelease)()
 			}
+			event.Emit(event.ActionReleased{Action: action})
 		}
 	} else {
 		return errors.New("Invalid action")
@@ -321,6 +336,7 @@ func bindKey(scope string, k string, v string) {
 	}
 	Bindings[scope][k] = v
 	Actions[v].Keys[k] = true
+	event.Queue(event.BindingChanged{Scope: scope, Key: k, Action: v})
 }
 
 // unbind a key in a scope
@@ -328,6 +344,7 @@ func unbindKey(scope string, k string) {
 	if _, ok := Bindings[scope][k]; ok {
 		delete(Actions[Bindings[scope][k]].Keys, k)
 		delete(Bindings[scope], k)
+		event.Queue(event.BindingChanged{Scope: scope, Key: k})
 	}
 }
 
@@ -374,6 +391,13 @@ func TryBindKey(scope string, k string, v string, overwrite bool) (bool, error)
 			return false, errors.New("Error reading bindings.json: " + err.Error())
 		}
 
+		if parsed == nil {
+			parsed = map[string]map[string]string{}
+		}
+		if parsed[scope] == nil {
+			parsed[scope] = map[string]string{}
+		}
+
 		found := false
 		for key := range parsed[scope] {
 			if key == k {
@@ -407,7 +431,7 @@ func UnbindKey(scope string, k string) error {
 	var e error
 	var parsed map[string]map[string]string
 
-	if _, ok := Bindings[scope][k]; ok {
+	if _, ok := Bindings[scope][k]; !ok {
 		return errors.New("Key does not exist")
 	}
 	unbindKey(scope, k)
@@ -0,0 +1,113 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"karalis/pkg/config"
+)
+
+// bindings and actions for a test, with an empty bindings.json in a temp config dir
+func useBindings(t *testing.T, bindings map[string]string) {
+	prevDir, prevBindings, prevActions := config.ConfigDir, Bindings, Actions
+	t.Cleanup(func() {
+		config.ConfigDir, Bindings, Actions = prevDir, prevBindings, prevActions
+	})
+
+	config.ConfigDir = t.TempDir()
+	createBindingsIfNotExist(filepath.Join(config.ConfigDir, "bindings.json"))
+	Bindings = map[string]map[string]string{"Character": {}}
+	Actions = map[string]*Binding{
+		"MoveLeft":  {Keys: map[string]bool{}},
+		"MoveRight": {Keys: map[string]bool{}},
+	}
+	for k, v := range bindings {
+		bindKey("Character", k, v)
+	}
+}
+
+func readBindings(t *testing.T) string {
+	txt, err := os.ReadFile(filepath.Join(config.ConfigDir, "bindings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(txt)
+}
+
+func TestUnbindKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		err   bool
+		bound string
+	}{
+		//a bound key is removed
+		{"Q", false, ""},
+		//a key bound over a default gets the default back
+		{"A", false, "MoveLeft"},
+		//a key that is not bound is an error
+		{"Z", true, ""},
+	}
+	for _, tt := range tests {
+		useBindings(t, map[string]string{"Q": "MoveLeft", "A": "MoveRight"})
+		if !tt.err {
+			_, err := TryBindKey("Character", tt.key, "MoveRight", true)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := UnbindKey("Character", tt.key)
+		if (err != nil) != tt.err {
+			t.Error(fmt.Sprintf("TestUnbindKey %s: expected error %v, got %v", tt.key, tt.err, err))
+		}
+		if got := Bindings["Character"][tt.key]; got != tt.bound {
+			t.Error(fmt.Sprintf("TestUnbindKey %s: expected binding %q, got %q", tt.key, tt.bound, got))
+		}
+		if Actions["MoveRight"].Keys[tt.key] {
+			t.Error(fmt.Sprintf("TestUnbindKey %s: key still triggers MoveRight", tt.key))
+		}
+		if strings.Contains(readBindings(t), `"`+tt.key+`"`) {
+			t.Error(fmt.Sprintf("TestUnbindKey %s: key still in bindings.json", tt.key))
+		}
+	}
+}
+
+func TestTryBindKey(t *testing.T) {
+	tests := []struct {
+		file      string
+		overwrite bool
+		found     bool
+		bound     string
+	}{
+		//files without the scope or with nothing at all
+		{"{}", false, false, "MoveRight"},
+		{"null", false, false, "MoveRight"},
+		{`{"Character": {"Q": "MoveLeft"}}`, false, true, "MoveLeft"},
+		{`{"Character": {"Q": "MoveLeft"}}`, true, true, "MoveRight"},
+	}
+	for _, tt := range tests {
+		useBindings(t, nil)
+		err := os.WriteFile(filepath.Join(config.ConfigDir, "bindings.json"), []byte(tt.file), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = LoadConfigBindings()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := TryBindKey("Character", "Q", "MoveRight", tt.overwrite)
+		if err != nil || found != tt.found {
+			t.Error(fmt.Sprintf("TestTryBindKey %s: expected found %v, got %v, %v", tt.file, tt.found, found, err))
+		}
+		if got := Bindings["Character"]["Q"]; got != tt.bound {
+			t.Error(fmt.Sprintf("TestTryBindKey %s: expected binding %q, got %q", tt.file, tt.bound, got))
+		}
+		if !strings.Contains(readBindings(t), `"Q": "`+tt.bound+`"`) {
+			t.Error(fmt.Sprintf("TestTryBindKey %s: bindings.json not updated: %s", tt.file, readBindings(t)))
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"karalis/pkg/config"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	// format of replay files written by this version
+	ReplayVersion = 1
+)
+
+var (
+	recording *Replay
+	playing   *Replay
+	//ticks since recording or playback started
+	tick int
+	//first action and look event not yet played
+	nextAction int
+	nextLook   int
+	idle       map[int]bool
+)
+
+// where the player starts a replay
+type Pose struct {
+	Pos   raylib.Vector3 `json:"pos"`
+	Yaw   float32        `json:"yaw"`
+	Pitch float32        `json:"pitch"`
+	Dist  float32        `json:"dist"`
+}
+
+// an action changing state on a tick, scope is the one HandleInput was called with
+type ActionEvent struct {
+	Tick    int    `json:"tick"`
+	Scope   string `json:"scope"`
+	Action  string `json:"action"`
+	Pressed bool   `json:"pressed"`
+}
+
+// mouse movement applied on a tick
+// already scaled by the tick and sensitivity so playback does not depend on the settings
+type Look struct {
+	Tick int     `json:"tick"`
+	Zoom float32 `json:"zoom"`
+	DX   float32 `json:"dx"`
+	DY   float32 `json:"dy"`
+}
+
+// recorded input of a game session, played back tick by tick
+type Replay struct {
+	Version  int    `json:"version"`
+	World    string `json:"world"`
+	Seed     int64  `json:"seed"`
+	TickRate int    `json:"tickRate"`
+	Start    Pose   `json:"start"`
+	//number of ticks recorded
+	Ticks   int           `json:"ticks"`
+	Actions []ActionEvent `json:"actions"`
+	Looks   []Look        `json:"looks"`
+	//ticks no input was handled on, like while the cursor was off the window
+	Idle []int `json:"idle"`
+}
+
+// check a replay can be played back
+func (r *Replay) Validate() error {
+	if r == nil {
+		return fmt.Errorf("Invalid replay")
+	}
+	if r.Version != ReplayVersion {
+		return fmt.Errorf("Unsupported replay version: %d", r.Version)
+	}
+	if r.TickRate <= 0 {
+		return fmt.Errorf("Invalid tick rate: %d", r.TickRate)
+	}
+	if r.Ticks < 0 {
+		return fmt.Errorf("Invalid tick count: %d", r.Ticks)
+	}
+	prev := 0
+	for _, e := range r.Actions {
+		if e.Tick < prev || e.Tick >= r.Ticks {
+			return fmt.Errorf("Action %s out of order on tick %d", e.Action, e.Tick)
+		}
+		prev = e.Tick
+	}
+	prev = 0
+	for _, l := range r.Looks {
+		if l.Tick < prev || l.Tick >= r.Ticks {
+			return fmt.Errorf("Mouse look out of order on tick %d", l.Tick)
+		}
+		prev = l.Tick
+	}
+	return nil
+}
+
+// write a replay as json
+func (r *Replay) Save(path string) error {
+	if r == nil {
+		return fmt.Errorf("Invalid replay")
+	}
+
+	data, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// folder in the config dir replays are saved to when no file is given
+func ReplayDir() string {
+	return filepath.Join(config.ConfigDir, "replays")
+}
+
+// path for a new replay named after the current time
+func NewReplayPath() string {
+	return filepath.Join(ReplayDir(), "replay_"+time.Now().Format("20060102_150405")+".json")
+}
+
+// read a replay file
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replay{}
+	err = json.Unmarshal(data, r)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid replay %s: %w", path, err)
+	}
+	err = r.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// start recording input, header holds the world and pose the recording starts from
+// actions held down already are recorded as pressed on the first tick
+func StartRecording(header Replay) error {
+	if playing != nil {
+		return fmt.Errorf("Replay is playing")
+	}
+	if recording != nil {
+		return fmt.Errorf("Already recording")
+	}
+
+	r := header
+	r.Version = ReplayVersion
+	r.Ticks = 0
+	r.Actions = []ActionEvent{}
+	r.Looks = []Look{}
+	r.Idle = []int{}
+	scopes := make([]string, 0, len(prevControls))
+	for scope := range prevControls {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		held := make([]string, 0, len(prevControls[scope]))
+		for action := range prevControls[scope] {
+			held = append(held, action)
+		}
+		sort.Strings(held)
+		for _, action := range held {
+			r.Actions = append(r.Actions, ActionEvent{Scope: scope, Action: action, Pressed: true})
+		}
+	}
+	recording = &r
+	tick = 0
+	return nil
+}
+
+// stop recording and get the replay, nil when not recording
+func StopRecording() *Replay {
+	r := recording
+	if r != nil {
+		r.Ticks = tick
+	}
+	recording = nil
+	return r
+}
+
+// check if input is being recorded
+func Recording() bool {
+	return recording != nil
+}
+
+// feed a replay into HandleInput instead of the devices
+// actions still held are released first as the replay presses its own
+func StartPlayback(r *Replay) error {
+	err := r.Validate()
+	if err != nil {
+		return err
+	}
+	if recording != nil {
+		return fmt.Errorf("Recording input")
+	}
+
+	releaseAll()
+	playing = r
+	tick = 0
+	nextAction = 0
+	nextLook = 0
+	idle = map[int]bool{}
+	for _, t := range r.Idle {
+		idle[t] = true
+	}
+	return nil
+}
+
+// stop playing back, the devices are read again from the next tick
+func StopPlayback() {
+	if playing == nil {
+		return
+	}
+	playing = nil
+	releaseAll()
+}
+
+// check if a replay is being played back
+func Replaying() bool {
+	return playing != nil
+}
+
+// get the tick recording or playback is at
+func GetTick() int {
+	return tick
+}
+
+// check if input is handled this tick, live is whether the devices would be read
+// playback decides on its own, recording notes the ticks skipped
+func Active(live bool) bool {
+	if playing != nil {
+		return !idle[tick]
+	}
+	if recording != nil && !live {
+		recording.Idle = append(recording.Idle, tick)
+	}
+	return live
+}
+
+// end the tick, called once per logic tick after input was handled
+// true when a replay finished playing on this tick
+func Tick() bool {
+	if recording == nil && playing == nil {
+		return false
+	}
+
+	tick++
+	if playing == nil {
+		return false
+	}
+	for nextAction < len(playing.Actions) && playing.Actions[nextAction].Tick < tick {
+		nextAction++
+	}
+	for nextLook < len(playing.Looks) && playing.Looks[nextLook].Tick < tick {
+		nextLook++
+	}
+	if tick >= playing.Ticks {
+		StopPlayback()
+		return true
+	}
+	return false
+}
+
+// record mouse movement for this tick
+func RecordLook(zoom, dx, dy float32) {
+	if recording == nil || (zoom == 0 && dx == 0 && dy == 0) {
+		return
+	}
+	recording.Looks = append(recording.Looks, Look{Tick: tick, Zoom: zoom, DX: dx, DY: dy})
+}
+
+// get the recorded mouse movement for this tick, ok is false unless a replay is playing
+func PlaybackLook() (zoom, dx, dy float32, ok bool) {
+	if playing == nil {
+		return 0, 0, 0, false
+	}
+	for i := nextLook; i < len(playing.Looks) && playing.Looks[i].Tick == tick; i++ {
+		l := playing.Looks[i]
+		zoom += l.Zoom
+		dx += l.DX
+		dy += l.DY
+	}
+	return zoom, dx, dy, true
+}
+
+// apply the recorded action changes of this tick in a scope
+func playbackInput(scope string) {
+	next := map[string]bool{}
+	for action := range prevControls[scope] {
+		next[action] = true
+	}
+	for i := nextAction; i < len(playing.Actions) && playing.Actions[i].Tick == tick; i++ {
+		e := playing.Actions[i]
+		if e.Scope != scope {
+			continue
+		}
+		if e.Pressed {
+			next[e.Action] = true
+			if err := handleBindingPress(scope, e.Action); err != nil {
+				logger.Error("replay press", "action", e.Action, "err", err)
+			}
+		} else {
+			delete(next, e.Action)
+			if err := handleBindingRelease(scope, e.Action); err != nil {
+				logger.Error("replay release", "action", e.Action, "err", err)
+			}
+		}
+	}
+	prevControls[scope] = next
+}
+
+// note an action changing state while recording
+func recordAction(scope string, action string, pressed bool) {
+	if recording == nil {
+		return
+	}
+	recording.Actions = append(recording.Actions, ActionEvent{Tick: tick, Scope: scope, Action: action, Pressed: pressed})
+}
+
+// release every held action in every scope
+func releaseAll() {
+	for scope, held := range prevControls {
+		for action := range held {
+			if err := handleBindingRelease(scope, action); err != nil {
+				logger.Error("release", "action", action, "err", err)
+			}
+		}
+		prevControls[scope] = map[string]bool{}
+	}
+}
@@ -0,0 +1,128 @@
+package input
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	prevControls = map[string]map[string]bool{"Character": {"MoveLeft": true}}
+	Actions["MoveLeft"].Pressed = true
+	defer releaseAll()
+
+	//changes made by HandleInput on each tick, looks and ticks the cursor was away
+	steps := []struct {
+		press   []string
+		release []string
+		look    [3]float32
+		live    bool
+	}{
+		{[]string{"MoveForward"}, nil, [3]float32{}, true},
+		{nil, []string{"MoveLeft"}, [3]float32{0, 0.5, -0.25}, true},
+		{nil, nil, [3]float32{}, false},
+		{[]string{"MoveUp", "MoveFast"}, []string{"MoveForward"}, [3]float32{1, 0, 0}, true},
+		{nil, []string{"MoveUp"}, [3]float32{}, true},
+	}
+	held := func() []string {
+		got := []string{}
+		for _, action := range []string{"MoveForward", "MoveLeft", "MoveUp", "MoveFast"} {
+			if Actions[action].Pressed {
+				got = append(got, action)
+			}
+		}
+		return got
+	}
+
+	err := StartRecording(Replay{World: "city", Seed: 7, TickRate: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := [][]string{}
+	for _, step := range steps {
+		if Active(step.live) {
+			//what HandleInput does for keys going down and up
+			for _, action := range step.press {
+				handleBindingPress("Character", action)
+				prevControls["Character"][action] = true
+			}
+			for _, action := range step.release {
+				handleBindingRelease("Character", action)
+				delete(prevControls["Character"], action)
+			}
+			RecordLook(step.look[0], step.look[1], step.look[2])
+		}
+		expect = append(expect, held())
+		Tick()
+	}
+	r := StopRecording()
+	if r == nil || r.Ticks != len(steps) || !slices.Equal(r.Idle, []int{2}) || len(r.Looks) != 2 {
+		t.Fatal(fmt.Sprintf("Unexpected recording: %+v", r))
+	}
+
+	path := filepath.Join(t.TempDir(), "replays", "test.json")
+	err = r.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//held actions are replaced by the ones the replay starts with
+	Actions["MoveDown"].Pressed = true
+	prevControls["Character"]["MoveDown"] = true
+	err = StartPlayback(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Actions["MoveDown"].Pressed {
+		t.Error("Playback kept an action held before it started")
+	}
+	for i, step := range steps {
+		active := Active(false)
+		if active != step.live {
+			t.Error(fmt.Sprintf("Unexpected active state on tick %d: %v", i, active))
+		}
+		if active {
+			HandleInput("Character")
+		}
+		zoom, dx, dy, ok := PlaybackLook()
+		if !ok || [3]float32{zoom, dx, dy} != step.look && active {
+			t.Error(fmt.Sprintf("Unexpected look on tick %d: %v %v %v %v", i, zoom, dx, dy, ok))
+		}
+		if got := held(); !slices.Equal(got, expect[i]) {
+			t.Error(fmt.Sprintf("Unexpected actions on tick %d: expected %v, got %v", i, expect[i], got))
+		}
+		finished := Tick()
+		if finished != (i == len(steps)-1) {
+			t.Error(fmt.Sprintf("Unexpected finish on tick %d", i))
+		}
+	}
+	if Replaying() || Actions["MoveFast"].Pressed {
+		t.Error("Playback did not stop and release its actions")
+	}
+}
+
+func TestReplayValidate(t *testing.T) {
+	tests := []struct {
+		r     Replay
+		valid bool
+	}{
+		{Replay{Version: ReplayVersion, TickRate: 60}, true},
+		{Replay{Version: ReplayVersion, TickRate: 60, Ticks: 3, Actions: []ActionEvent{{Tick: 0}, {Tick: 2}}}, true},
+		{Replay{Version: 0, TickRate: 60}, false},
+		{Replay{Version: ReplayVersion}, false},
+		{Replay{Version: ReplayVersion, TickRate: 60, Ticks: 3, Actions: []ActionEvent{{Tick: 2}, {Tick: 1}}}, false},
+		{Replay{Version: ReplayVersion, TickRate: 60, Ticks: 3, Actions: []ActionEvent{{Tick: 3}}}, false},
+		{Replay{Version: ReplayVersion, TickRate: 60, Ticks: 3, Looks: []Look{{Tick: 5}}}, false},
+	}
+	for i, test := range tests {
+		err := test.r.Validate()
+		if (err == nil) != test.valid {
+			t.Error(fmt.Sprintf("Unexpected error for case %d: %+v", i, err))
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package input
+
+import (
+	"slices"
+)
+
+// json schema describing bindings.json
+// actions registered at runtime are valid as well so known ones are only listed as examples
+func Schema() map[string]interface{} {
+	actions := []string{}
+	scopes := map[string]interface{}{}
+	for _, scope := range Scopes {
+		for _, action := range DefaultBindings(scope) {
+			if !slices.Contains(actions, action) {
+				actions = append(actions, action)
+			}
+		}
+	}
+	for action := range Actions {
+		if !slices.Contains(actions, action) {
+			actions = append(actions, action)
+		}
+	}
+	slices.Sort(actions)
+
+	scope := map[string]interface{}{
+		"type":                 "object",
+		"description":          "keys or key combos mapped to action names",
+		"additionalProperties": map[string]interface{}{"type": "string", "examples": actions},
+	}
+	for _, name := range Scopes {
+		scopes[name] = scope
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "bindings",
+		"type":                 "object",
+		"properties":           scopes,
+		"additionalProperties": scope,
+	}
+}
@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"karalis/pkg/config"
+)
+
+const (
+	// size a log file is rotated at
+	MaxFileSize = 10 << 20
+	// rotated log files kept besides the current one
+	KeepFiles = 5
+)
+
+// folder in the config dir logs are written to
+func Dir() string {
+	return filepath.Join(config.ConfigDir, "logs")
+}
+
+// path of the current log file
+func FilePath() string {
+	return filepath.Join(Dir(), strings.ToLower(config.AppName)+".log")
+}
+
+// log file that starts fresh on opening and when it grows past maxSize
+// earlier files are kept as path.1 to path.keep, newest first
+type File struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	file    *os.File
+	size    int64
+}
+
+// open a log file, rotating the one left by the last run
+func OpenFile(path string, maxSize int64, keep int) (*File, error) {
+	if maxSize <= 0 || keep < 0 {
+		return nil, fmt.Errorf("Invalid rotation: %d bytes keeping %d", maxSize, keep)
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{path: path, maxSize: maxSize, keep: keep}
+	err = f.rotate()
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	if f == nil {
+		return 0, fmt.Errorf("Invalid log file")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		err := f.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *File) Close() error {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// shift the kept files up by one, dropping the oldest, and start a new file
+// callers hold mu once the file is open
+func (f *File) rotate() error {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+
+	for i := f.keep; i > 0; i-- {
+		from := f.path
+		if i > 1 {
+			from = fmt.Sprintf("%s.%d", f.path, i-1)
+		}
+		err := os.Rename(from, fmt.Sprintf("%s.%d", f.path, i))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	file, err := os.Create(f.path)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	f.size = 0
+	return nil
+}
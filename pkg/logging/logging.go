@@ -0,0 +1,272 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// subsystems with their own logger and level
+const (
+	App     = "app"
+	Net     = "net"
+	Res     = "res"
+	Shader  = "shader"
+	World   = "world"
+	Physics = "physics"
+	Input   = "input"
+)
+
+const (
+	// log lines kept in memory for the console and crash reports
+	MaxLines = 500
+)
+
+var (
+	Subsystems = []string{App, Net, Res, Shader, World, Physics, Input}
+
+	levels atomic.Pointer[Levels]
+
+	mu  sync.Mutex
+	out io.Writer = os.Stderr
+	// recent log output
+	recent = NewRing(MaxLines)
+)
+
+func init() {
+	levels.Store(&Levels{Default: slog.LevelInfo, Sys: map[string]slog.Level{}})
+}
+
+// get the logger of a subsystem
+func For(sys string) *slog.Logger {
+	return slog.New(&handler{sys: sys})
+}
+
+// set where log lines are written, the ring buffer keeps them regardless
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	out = w
+}
+
+// get the last lines logged oldest first
+func GetLines() []string {
+	return recent.GetLines()
+}
+
+func write(line string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	recent.Write([]byte(line))
+	_, err := io.WriteString(out, line)
+	return err
+}
+
+// minimum level logged by default and by each subsystem overriding it
+type Levels struct {
+	Default slog.Level
+	Sys     map[string]slog.Level
+}
+
+// parse levels such as "info,net=debug,world=warn"
+// a bare level sets the default, sys=level overrides one subsystem
+func ParseLevels(spec string) (Levels, error) {
+	l := Levels{Default: slog.LevelInfo, Sys: map[string]slog.Level{}}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		sys, name, ok := strings.Cut(part, "=")
+		if !ok {
+			sys, name = "", part
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return l, err
+		}
+		if sys == "" {
+			l.Default = level
+			continue
+		}
+		if !slices.Contains(Subsystems, sys) {
+			return l, fmt.Errorf("Unknown subsystem: %s", sys)
+		}
+		l.Sys[sys] = level
+	}
+	return l, nil
+}
+
+// parse a level name such as debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.TrimSpace(name)))
+	if err != nil {
+		return level, fmt.Errorf("Invalid level: %s", name)
+	}
+	return level, nil
+}
+
+// get the name of a level as ParseLevel takes it
+func LevelName(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+// get the level of a subsystem
+func (l Levels) Get(sys string) slog.Level {
+	level, ok := l.Sys[sys]
+	if !ok {
+		return l.Default
+	}
+	return level
+}
+
+// copy the levels with one subsystem overridden
+func (l Levels) With(sys string, level slog.Level) Levels {
+	next := Levels{Default: l.Default, Sys: map[string]slog.Level{}}
+	for k, v := range l.Sys {
+		next.Sys[k] = v
+	}
+	next.Sys[sys] = level
+	return next
+}
+
+// format the levels as ParseLevels takes them, overrides in subsystem order
+func (l Levels) String() string {
+	parts := []string{LevelName(l.Default)}
+	for _, sys := range Subsystems {
+		if level, ok := l.Sys[sys]; ok {
+			parts = append(parts, sys+"="+LevelName(level))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// apply levels parsed from spec
+func SetLevels(spec string) error {
+	l, err := ParseLevels(spec)
+	if err != nil {
+		return err
+	}
+	levels.Store(&l)
+	return nil
+}
+
+// get the levels in use
+func GetLevels() Levels {
+	return *levels.Load()
+}
+
+// writes records as one line each: time, level, subsystem, message then attributes
+type handler struct {
+	sys    string
+	attrs  string
+	prefix string
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= levels.Load().Get(h.sys)
+}
+
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	b := &strings.Builder{}
+	b.WriteString(r.Time.Format("2006/01/02 15:04:05"))
+	fmt.Fprintf(b, " %s %s: %s", r.Level, h.sys, r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(b, h.prefix, a)
+		return true
+	})
+	b.WriteByte('\n')
+	return write(b.String())
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	b := &strings.Builder{}
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		writeAttr(b, h.prefix, a)
+	}
+	return &handler{sys: h.sys, attrs: b.String(), prefix: h.prefix}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{sys: h.sys, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			writeAttr(b, prefix, ga)
+		}
+		return
+	}
+
+	val := a.Value.String()
+	if val == "" || strings.ContainsAny(val, " =\"\t\n") {
+		val = strconv.Quote(val)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, a.Key, val)
+}
+
+// keeps the last lines written to it
+type Ring struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial string
+}
+
+func NewRing(max int) *Ring {
+	return &Ring{max: max, lines: []string{}}
+}
+
+// add output, split into lines
+func (r *Ring) Write(p []byte) (int, error) {
+	if r == nil {
+		return len(p), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txt := r.partial + string(p)
+	parts := strings.Split(txt, "\n")
+	r.partial = parts[len(parts)-1]
+	r.lines = append(r.lines, parts[:len(parts)-1]...)
+	if over := len(r.lines) - r.max; over > 0 {
+		r.lines = append([]string{}, r.lines[over:]...)
+	}
+	return len(p), nil
+}
+
+// get the kept lines oldest first, including an unfinished last line
+func (r *Ring) GetLines() []string {
+	if r == nil {
+		return []string{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := append([]string{}, r.lines...)
+	if r.partial != "" {
+		out = append(out, r.partial)
+	}
+	return out
+}
@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		spec   string
+		valid  bool
+		expect string
+		net    slog.Level
+		world  slog.Level
+	}{
+		{"", true, "info", slog.LevelInfo, slog.LevelInfo},
+		{"debug", true, "debug", slog.LevelDebug, slog.LevelDebug},
+		{"info,net=debug", true, "info,net=debug", slog.LevelDebug, slog.LevelInfo},
+		{" world=ERROR , warn ", true, "warn,world=error", slog.LevelWarn, slog.LevelError},
+		{"net=debug,net=warn", true, "info,net=warn", slog.LevelWarn, slog.LevelInfo},
+		{"loud", false, "", 0, 0},
+		{"gpu=debug", false, "", 0, 0},
+		{"net=", false, "", 0, 0},
+	}
+	for i, test := range tests {
+		l, err := ParseLevels(test.spec)
+		if (err == nil) != test.valid {
+			t.Error(fmt.Sprintf("Unexpected error for case %d %q: %v", i, test.spec, err))
+			continue
+		}
+		if !test.valid {
+			continue
+		}
+		if l.String() != test.expect || l.Get(Net) != test.net || l.Get(World) != test.world {
+			t.Error(fmt.Sprintf("Unexpected levels for case %d %q: got %s", i, test.spec, l))
+		}
+	}
+
+	l, _ := ParseLevels("warn")
+	next := l.With(Res, slog.LevelDebug)
+	if l.Get(Res) != slog.LevelWarn || next.Get(Res) != slog.LevelDebug || next.String() != "warn,res=debug" {
+		t.Error(fmt.Sprintf("Unexpected levels from With: %s then %s", l, next))
+	}
+}
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	defer SetOutput(os.Stderr)
+	defer SetLevels("info")
+
+	err := SetLevels("warn,net=debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	For(World).Info("hidden")
+	For(World).Warn("shown", "cell", 3)
+	For(Net).Debug("packet", "from", "1.2.3.4:9595", "body", "Hello there")
+	For(Res).With("path", "tex/a.png").WithGroup("img").Error("load", "w", 2, slog.Group("fmt", "bits", 8))
+
+	expect := []string{
+		" WARN world: shown cell=3",
+		" DEBUG net: packet from=1.2.3.4:9595 body=\"Hello there\"",
+		" ERROR res: load path=tex/a.png img.w=2 img.fmt.bits=8",
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expect) {
+		t.Fatal(fmt.Sprintf("Unexpected log output:\n%s", buf.String()))
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, expect[i]) {
+			t.Error(fmt.Sprintf("Unexpected line %d: expected suffix %q, got %q", i, expect[i], line))
+		}
+	}
+
+	//the ring keeps lines whatever the output
+	kept := GetLines()
+	if !slices.Equal(kept[len(kept)-len(lines):], lines) {
+		t.Error(fmt.Sprintf("Ring is missing lines: %q", kept))
+	}
+}
+
+func TestRing(t *testing.T) {
+	tests := []struct {
+		max    int
+		writes []string
+		expect []string
+	}{
+		{3, []string{}, []string{}},
+		{3, []string{"a\n", "b\n"}, []string{"a", "b"}},
+		{3, []string{"a\nb\nc\nd\n"}, []string{"b", "c", "d"}},
+		{3, []string{"a", "b\nc"}, []string{"ab", "c"}},
+		{2, []string{"a\n", "b\n", "c\n", "d"}, []string{"b", "c", "d"}},
+		{2, []string{"\n\n"}, []string{"", ""}},
+	}
+	for i, test := range tests {
+		r := NewRing(test.max)
+		for _, w := range test.writes {
+			n, err := r.Write([]byte(w))
+			if err != nil || n != len(w) {
+				t.Error(fmt.Sprintf("Unexpected write for case %d: %d %v", i, n, err))
+			}
+		}
+		got := r.GetLines()
+		if !slices.Equal(got, test.expect) {
+			t.Error(fmt.Sprintf("Unexpected lines for case %d: expected %q, got %q", i, test.expect, got))
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "test.log")
+	read := func(p string) string {
+		data, _ := os.ReadFile(p)
+		return string(data)
+	}
+
+	//each open starts a new file, the oldest beyond keep are dropped
+	for run := range 4 {
+		f, err := OpenFile(path, 10, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(f, "run%d\n", run)
+		f.Close()
+	}
+	got := []string{read(path), read(path + ".1"), read(path + ".2"), read(path + ".3")}
+	expect := []string{"run3\n", "run2\n", "run1\n", ""}
+	if !slices.Equal(got, expect) {
+		t.Error(fmt.Sprintf("Unexpected files after reopening: expected %q, got %q", expect, got))
+	}
+
+	//writes past the size go to a new file
+	f, err := OpenFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "a line longer than max\n"} {
+		f.Write([]byte(line))
+	}
+	got = []string{read(path), read(path + ".1"), read(path + ".2")}
+	expect = []string{"a line longer than max\n", "cccc\n", "aaaa\nbbbb\n"}
+	if !slices.Equal(got, expect) {
+		t.Error(fmt.Sprintf("Unexpected files after growing: expected %q, got %q", expect, got))
+	}
+
+	_, err = OpenFile(path, 0, 2)
+	if err == nil {
+		t.Error("OpenFile accepted a max size of 0")
+	}
+}
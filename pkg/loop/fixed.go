@@ -0,0 +1,204 @@
+package loop
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTickRate = 60
+	DefaultMaxSteps = 5
+)
+
+// fixed timestep accumulator running logic ticks independent of the frame rate
+// the logic loop advances it while rendering reads the blend factor between ticks
+type FixedStep struct {
+	mu       sync.Mutex
+	step     time.Duration
+	maxSteps int
+
+	acc   time.Duration
+	last  time.Time
+	tick  uint64
+	start bool
+}
+
+func NewFixedStep(rate int, maxSteps int) (*FixedStep, error) {
+	f := &FixedStep{}
+	err := f.SetRate(rate)
+	if err != nil {
+		return nil, err
+	}
+	err = f.SetMaxSteps(maxSteps)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// set the number of ticks per second
+func (f *FixedStep) SetRate(rate int) error {
+	if f == nil {
+		return fmt.Errorf("Invalid timestep")
+	}
+	if rate <= 0 {
+		return fmt.Errorf("Invalid tick rate: %d", rate)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.step = time.Second / time.Duration(rate)
+	if f.acc > f.step {
+		f.acc = f.step
+	}
+	return nil
+}
+
+// get the number of ticks per second
+func (f *FixedStep) GetRate() int {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return int(time.Second / f.step)
+}
+
+// set how many ticks may run to catch up after a slow frame
+// time behind beyond that is dropped so the simulation slows down instead of spiraling
+func (f *FixedStep) SetMaxSteps(steps int) error {
+	if f == nil {
+		return fmt.Errorf("Invalid timestep")
+	}
+	if steps <= 0 {
+		return fmt.Errorf("Invalid catch up steps: %d", steps)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.maxSteps = steps
+	return nil
+}
+
+func (f *FixedStep) GetMaxSteps() int {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxSteps
+}
+
+// get the length of a tick
+func (f *FixedStep) GetStep() time.Duration {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.step
+}
+
+// get the length of a tick in seconds as passed to updates
+func (f *FixedStep) Dt() float32 {
+	return float32(f.GetStep().Seconds())
+}
+
+// get the number of ticks run so far
+func (f *FixedStep) GetTick() uint64 {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tick
+}
+
+// restart timing from now, dropping any accumulated time
+func (f *FixedStep) Reset(now time.Time) {
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.acc = 0
+	f.last = now
+	f.start = true
+}
+
+// accumulate the time since the last call and get how many ticks to run
+func (f *FixedStep) Advance(now time.Time) int {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.start {
+		f.last = now
+		f.start = true
+		return 0
+	}
+
+	elapsed := now.Sub(f.last)
+	if elapsed > 0 {
+		f.acc += elapsed
+	}
+	f.last = now
+
+	steps := int(f.acc / f.step)
+	if steps > f.maxSteps {
+		steps = f.maxSteps
+		f.acc = f.step * time.Duration(steps)
+	}
+	f.acc -= f.step * time.Duration(steps)
+	f.tick += uint64(steps)
+	return steps
+}
+
+// get how far now is between the previous and the current tick, from 0 to 1
+func (f *FixedStep) Alpha(now time.Time) float32 {
+	if f == nil {
+		return 1
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.start {
+		return 1
+	}
+
+	acc := f.acc
+	if since := now.Sub(f.last); since > 0 {
+		acc += since
+	}
+	if acc >= f.step {
+		return 1
+	}
+	return float32(acc) / float32(f.step)
+}
+
+// get the time left until the next tick is due
+func (f *FixedStep) Until(now time.Time) time.Duration {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.start {
+		return 0
+	}
+
+	left := f.step - f.acc - now.Sub(f.last)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
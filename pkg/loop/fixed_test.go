@@ -0,0 +1,111 @@
+package loop
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFixedStepAdvance(t *testing.T) {
+	base := time.Unix(0, 0)
+	tests := []struct {
+		name  string
+		at    []time.Duration
+		steps []int
+		alpha float32
+	}{
+		{"idle", []time.Duration{0, 5 * time.Millisecond}, []int{0, 0}, 0.5},
+		{"single", []time.Duration{0, 10 * time.Millisecond}, []int{0, 1}, 0},
+		{"accumulate", []time.Duration{0, 6 * time.Millisecond, 12 * time.Millisecond, 21 * time.Millisecond}, []int{0, 0, 1, 1}, 0.1},
+		{"catch up", []time.Duration{0, 35 * time.Millisecond}, []int{0, 3}, 0.5},
+		{"capped", []time.Duration{0, 500 * time.Millisecond, 505 * time.Millisecond}, []int{0, 4, 0}, 0.5},
+		{"backwards", []time.Duration{0, -5 * time.Millisecond}, []int{0, 0}, 0},
+	}
+
+	for _, tt := range tests {
+		f, err := NewFixedStep(100, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Reset(base)
+		total := 0
+		for i, at := range tt.at {
+			steps := f.Advance(base.Add(at))
+			if steps != tt.steps[i] {
+				t.Error(fmt.Sprintf("TestFixedStepAdvance %s step %d: got %d ticks, want %d", tt.name, i, steps, tt.steps[i]))
+			}
+			total += steps
+		}
+		if f.GetTick() != uint64(total) {
+			t.Error(fmt.Sprintf("TestFixedStepAdvance %s: tick %d, want %d", tt.name, f.GetTick(), total))
+		}
+
+		alpha := f.Alpha(base.Add(tt.at[len(tt.at)-1]))
+		if alpha < tt.alpha-0.001 || alpha > tt.alpha+0.001 {
+			t.Error(fmt.Sprintf("TestFixedStepAdvance %s: alpha %f, want %f", tt.name, alpha, tt.alpha))
+		}
+	}
+}
+
+func TestFixedStepAlpha(t *testing.T) {
+	base := time.Unix(0, 0)
+	f, err := NewFixedStep(50, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Reset(base)
+	f.Advance(base.Add(25 * time.Millisecond))
+
+	tests := []struct {
+		since time.Duration
+		alpha float32
+		until time.Duration
+	}{
+		{0, 0.25, 15 * time.Millisecond},
+		{10 * time.Millisecond, 0.75, 5 * time.Millisecond},
+		{15 * time.Millisecond, 1, 0},
+		{time.Second, 1, 0},
+	}
+	for _, tt := range tests {
+		now := base.Add(25*time.Millisecond + tt.since)
+		alpha := f.Alpha(now)
+		if alpha < tt.alpha-0.001 || alpha > tt.alpha+0.001 {
+			t.Error(fmt.Sprintf("TestFixedStepAlpha %v: alpha %f, want %f", tt.since, alpha, tt.alpha))
+		}
+		until := f.Until(now)
+		if until != tt.until {
+			t.Error(fmt.Sprintf("TestFixedStepAlpha %v: until %v, want %v", tt.since, until, tt.until))
+		}
+	}
+}
+
+func TestFixedStepConfig(t *testing.T) {
+	tests := []struct {
+		rate  int
+		steps int
+		valid bool
+	}{
+		{60, 5, true},
+		{1, 1, true},
+		{0, 5, false},
+		{-30, 5, false},
+		{60, 0, false},
+	}
+	for _, tt := range tests {
+		f, err := NewFixedStep(tt.rate, tt.steps)
+		if (err == nil) != tt.valid {
+			t.Error(fmt.Sprintf("TestFixedStepConfig %d %d: err %v", tt.rate, tt.steps, err))
+			continue
+		}
+		if !tt.valid {
+			continue
+		}
+		if f.GetRate() != tt.rate || f.GetMaxSteps() != tt.steps {
+			t.Error(fmt.Sprintf("TestFixedStepConfig %d %d: got %d %d", tt.rate, tt.steps, f.GetRate(), f.GetMaxSteps()))
+		}
+		want := float32(1) / float32(tt.rate)
+		if dt := f.Dt(); dt < want*0.999 || dt > want*1.001 {
+			t.Error(fmt.Sprintf("TestFixedStepConfig %d: dt %f, want %f", tt.rate, dt, want))
+		}
+	}
+}
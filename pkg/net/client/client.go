@@ -1,24 +1,31 @@
 package client
 
 import (
-	"log"
 	"net"
-	"os"
 	"strings"
 	"time"
+
+	"karalis/pkg/event"
+	"karalis/pkg/logging"
 )
 
-// Client --
-func Client() {
-	register()
-}
+var (
+	peers = map[string]bool{}
+
+	logger = logging.For(logging.Net)
+)
+
+// default local address clients listen on
+const DefaultLocal = ":9595"
 
-func register() {
-	signalAddress := os.Args[2]
+// Client -- register with the signal server at connect and chat with the peers it reports
+func Client(connect string, local string) {
+	register(connect, local)
+}
 
-	localAddress := ":9595" // default port
-	if len(os.Args) > 3 {
-		localAddress = os.Args[3]
+func register(signalAddress string, localAddress string) {
+	if localAddress == "" {
+		localAddress = DefaultLocal
 	}
 
 	remote, _ := net.ResolveUDPAddr("udp", signalAddress)
@@ -30,29 +37,31 @@ func register() {
 			panic(err)
 		}
 
-		log.Println(bytesWritten, " bytes written")
+		logger.Debug("registered", "signal", signalAddress, "bytes", bytesWritten)
 	}()
 
 	listen(conn, local.String())
 }
 
 func listen(conn *net.UDPConn, local string) {
+	logger.Info("listening", "addr", local)
 	for {
-		log.Println("listening")
 		buffer := make([]byte, 1024)
 		bytesRead, err := conn.Read(buffer)
 		if err != nil {
-			log.Println("[ERROR]", err)
+			logger.Error("read", "err", err)
 			continue
 		}
 
-		log.Println("[INCOMING]", string(buffer[0:bytesRead]))
+		logger.Debug("incoming", "msg", string(buffer[0:bytesRead]))
 		if string(buffer[0:bytesRead]) == "Hello!" {
 			continue
 		}
 
 		for _, a := range strings.Split(string(buffer[0:bytesRead]), ",") {
-			if a != local {
+			if a != local && !peers[a] {
+				peers[a] = true
+				event.Queue(event.PeerJoined{Addr: a})
 				go chatter(conn, a)
 			}
 		}
@@ -63,7 +72,7 @@ func chatter(conn *net.UDPConn, remote string) {
 	addr, _ := net.ResolveUDPAddr("udp", remote)
 	for {
 		conn.WriteTo([]byte("Hello!"), addr)
-		log.Println("sent Hello! to ", remote)
+		logger.Debug("sent hello", "to", remote)
 		time.Sleep(5 * time.Second)
 	}
 }
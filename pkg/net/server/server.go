@@ -1,15 +1,23 @@
 package server
 
 import (
-	"log"
 	"net"
-	"os"
 	"strings"
+
+	"karalis/pkg/event"
+	"karalis/pkg/logging"
 )
 
 type clientType map[string]bool
 
-var clients = clientType{}
+var (
+	clients = clientType{}
+
+	logger = logging.For(logging.Net)
+)
+
+// default address the signal server listens on
+const DefaultListen = ":9595"
 
 func (c clientType) keys(filter string) string {
 	output := []string{}
@@ -22,15 +30,16 @@ func (c clientType) keys(filter string) string {
 	return strings.Join(output, ",")
 }
 
-// Server --
-func Server() {
-	localAddress := ":9595"
-	if len(os.Args) > 2 {
-		localAddress = os.Args[2]
+// Server -- run the signal server on the listen address
+func Server(listen string) {
+	localAddress := listen
+	if localAddress == "" {
+		localAddress = DefaultListen
 	}
 
 	addr, _ := net.ResolveUDPAddr("udp", localAddress)
 	conn, _ := net.ListenUDP("udp", addr)
+	logger.Info("listening", "addr", localAddress)
 
 	for {
 		buffer := make([]byte, 1024)
@@ -40,11 +49,14 @@ func Server() {
 		}
 
 		incoming := string(buffer[0:bytesRead])
-		log.Println("[INCOMING]", incoming)
+		logger.Debug("incoming", "from", remoteAddr, "msg", incoming)
 		if incoming != "register" {
 			continue
 		}
 
+		if !clients[remoteAddr.String()] {
+			event.Queue(event.PeerJoined{Addr: remoteAddr.String()})
+		}
 		clients[remoteAddr.String()] = true
 
 		for client := range clients {
@@ -52,7 +64,7 @@ func Server() {
 			if len(resp) > 0 {
 				r, _ := net.ResolveUDPAddr("udp", client)
 				conn.WriteTo([]byte(resp), r)
-				log.Printf("[INFO] Responded to %s with %s\n", client, string(resp))
+				logger.Info("responded", "to", client, "peers", resp)
 			}
 		}
 	}
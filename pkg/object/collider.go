@@ -6,6 +6,14 @@ import (
 
 var ()
 
+// bounding shapes a collider can use
+const (
+	ShapeAABB   = "aabb"
+	ShapeSphere = "sphere"
+	ShapeOBB    = "obb"
+	ShapeNone   = "none"
+)
+
 //
 // ========================================
 // HELPER TYPES
@@ -48,6 +56,7 @@ type CollisionData struct {
 
 type Collider interface {
 	GetObj() Object
+	GetShape() string
 	GetBoundingSphere() Sphere
 	GetAABB() raylib.BoundingBox
 	GetOOBB() OrientedBox
@@ -0,0 +1,47 @@
+package object
+
+import (
+	"math"
+
+	"karalis/pkg/lmath"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+// objects drawn blended between their state at the previous and the current logic tick
+type Interpolated interface {
+	// remember the current state as the previous tick, called before every tick
+	SaveState()
+}
+
+// placement of an object at one tick, rotation is pitch yaw roll
+type Transform struct {
+	Pos   raylib.Vector3
+	Rot   raylib.Vector3
+	Scale raylib.Vector3
+}
+
+// blend towards another transform, alpha 0 keeps t and 1 gives to
+func (t Transform) Lerp(to Transform, alpha float32) Transform {
+	return Transform{
+		Pos:   raylib.Vector3Lerp(t.Pos, to.Pos, alpha),
+		Rot:   raylib.NewVector3(lerpAngle(t.Rot.X, to.Rot.X, alpha), lerpAngle(t.Rot.Y, to.Rot.Y, alpha), lerpAngle(t.Rot.Z, to.Rot.Z, alpha)),
+		Scale: raylib.Vector3Lerp(t.Scale, to.Scale, alpha),
+	}
+}
+
+// blend between two angles in radians along the shortest way round
+func lerpAngle(from, to, alpha float32) float32 {
+	diff := float32(math.Remainder(float64(to-from), 2*math.Pi))
+	return from + diff*alpha
+}
+
+// build the model matrix scaling, then rotating, then translating
+func (t Transform) Matrix() raylib.Matrix {
+	matScale := raylib.MatrixScale(t.Scale.X, t.Scale.Y, t.Scale.Z)
+	Quat := lmath.Quat{}
+	Quat = *Quat.FromEuler(float64(t.Rot.X), float64(t.Rot.Y), float64(t.Rot.Z))
+	matRotation := raylib.QuaternionToMatrix(raylib.NewQuaternion(float32(Quat.X), float32(Quat.Y), float32(Quat.Z), float32(Quat.W)))
+	matTranslation := raylib.MatrixTranslate(t.Pos.X, t.Pos.Y, t.Pos.Z)
+	return raylib.MatrixMultiply(raylib.MatrixMultiply(matScale, matRotation), matTranslation)
+}
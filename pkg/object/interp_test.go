@@ -0,0 +1,71 @@
+package object
+
+import (
+	"fmt"
+	"testing"
+
+	"karalis/pkg/lmath"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestTransformLerp(t *testing.T) {
+	from := Transform{
+		Pos:   raylib.NewVector3(0, 0, 0),
+		Rot:   raylib.NewVector3(0, 0, 0),
+		Scale: raylib.NewVector3(1, 1, 1),
+	}
+	to := Transform{
+		Pos:   raylib.NewVector3(10, -4, 2),
+		Rot:   raylib.NewVector3(0, 1, 0),
+		Scale: raylib.NewVector3(3, 1, 1),
+	}
+
+	tests := []struct {
+		alpha float32
+		pos   raylib.Vector3
+		yaw   float32
+		scale float32
+	}{
+		{0, raylib.NewVector3(0, 0, 0), 0, 1},
+		{0.5, raylib.NewVector3(5, -2, 1), 0.5, 2},
+		{1, raylib.NewVector3(10, -4, 2), 1, 3},
+	}
+	for _, tt := range tests {
+		got := from.Lerp(to, tt.alpha)
+		if raylib.Vector3Distance(got.Pos, tt.pos) > 0.0001 || got.Rot.Y != tt.yaw || got.Scale.X != tt.scale {
+			t.Error(fmt.Sprintf("TestTransformLerp %f: got %+v", tt.alpha, got))
+		}
+
+		mat := got.Matrix()
+		if raylib.Vector3Distance(raylib.NewVector3(mat.M12, mat.M13, mat.M14), tt.pos) > 0.0001 {
+			t.Error(fmt.Sprintf("TestTransformLerp %f: matrix translation %f %f %f", tt.alpha, mat.M12, mat.M13, mat.M14))
+		}
+	}
+}
+
+func TestTransformLerpWrap(t *testing.T) {
+	deg := func(a float64) float32 { return float32(lmath.Radians(a)) }
+
+	tests := []struct {
+		from  float64
+		to    float64
+		alpha float32
+		want  float64
+	}{
+		{359, 1, 0.5, 360},
+		{1, 359, 0.5, 0},
+		{350, 20, 0.25, 357.5},
+		{-170, 170, 0.5, -180},
+		{10, 30, 0.5, 20},
+	}
+	for _, tt := range tests {
+		from := Transform{Rot: raylib.NewVector3(deg(tt.from), deg(tt.from), deg(tt.from))}
+		to := Transform{Rot: raylib.NewVector3(deg(tt.to), deg(tt.to), deg(tt.to))}
+		got := from.Lerp(to, tt.alpha)
+		want := raylib.NewVector3(deg(tt.want), deg(tt.want), deg(tt.want))
+		if raylib.Vector3Distance(got.Rot, want) > 0.0001 {
+			t.Error(fmt.Sprintf("TestTransformLerpWrap %f to %f: got %+v, want %+v", tt.from, tt.to, got.Rot, want))
+		}
+	}
+}
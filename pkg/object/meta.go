@@ -0,0 +1,164 @@
+package object
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	lastID atomic.Uint64
+)
+
+// identity carried by every object
+type Meta struct {
+	id       atomic.Uint64
+	name     string
+	tags     map[string]bool
+	releases []func()
+}
+
+// objects able to visit their direct children without building a slice
+type ChildVisitor interface {
+	EachChild(fn func(Object) bool) bool
+}
+
+// get the objects id, assigning one on first use, safe to call from the logic and render loops at once
+// ids are unique for the run only, they are not saved so a loaded scene or save gets new ones
+func (m *Meta) GetID() uint64 {
+	if m == nil {
+		return 0
+	}
+
+	if id := m.id.Load(); id != 0 {
+		return id
+	}
+	//a caller losing the race takes the id the winner stored
+	m.id.CompareAndSwap(0, lastID.Add(1))
+	return m.id.Load()
+}
+
+func (m *Meta) GetName() string {
+	if m == nil {
+		return ""
+	}
+
+	return m.name
+}
+
+func (m *Meta) SetName(name string) {
+	if m == nil {
+		return
+	}
+
+	m.name = name
+}
+
+func (m *Meta) AddTag(tag string) {
+	if m == nil {
+		return
+	}
+
+	if m.tags == nil {
+		m.tags = map[string]bool{}
+	}
+	m.tags[tag] = true
+}
+
+func (m *Meta) RemTag(tag string) {
+	if m == nil {
+		return
+	}
+
+	delete(m.tags, tag)
+}
+
+func (m *Meta) HasTag(tag string) bool {
+	if m == nil {
+		return false
+	}
+
+	return m.tags[tag]
+}
+
+// get the objects tags in sorted order
+func (m *Meta) GetTags() []string {
+	if m == nil {
+		return []string{}
+	}
+
+	tags := make([]string, 0, len(m.tags))
+	for tag := range m.tags {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// register fn to run when the object is released from the tree
+func (m *Meta) OnRelease(fn func()) {
+	if m == nil || fn == nil {
+		return
+	}
+
+	m.releases = append(m.releases, fn)
+}
+
+// run and forget the release callbacks, called from the objects OnRemove
+func (m *Meta) Release() {
+	if m == nil {
+		return
+	}
+
+	releases := m.releases
+	m.releases = nil
+	for _, fn := range releases {
+		fn()
+	}
+}
+
+// remove the direct children of obj along with it, called from a containers OnRemove
+// each child does the same for its own, so releases run through the whole subtree
+func RemoveChilds(obj Object) {
+	EachChild(obj, func(child Object) bool {
+		child.OnRemove()
+		return true
+	})
+}
+
+// visit the direct children of an object until fn returns false
+func EachChild(obj Object, fn func(Object) bool) bool {
+	if obj == nil {
+		return true
+	}
+	if v, ok := obj.(ChildVisitor); ok {
+		return v.EachChild(fn)
+	}
+	for _, child := range obj.GetChilds() {
+		if !fn(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// visit all descendants of an object depth first until fn returns false
+func Walk(obj Object, fn func(Object) bool) bool {
+	return EachChild(obj, func(child Object) bool {
+		if !fn(child) {
+			return false
+		}
+		return Walk(child, fn)
+	})
+}
+
+// get the slash separated path of names from the root to an object
+func GetPath(obj Object) string {
+	names := []string{}
+	for obj != nil && obj.GetParent() != nil {
+		names = append(names, obj.GetMeta().GetName())
+		obj = obj.GetParent()
+	}
+	slices.Reverse(names)
+	return strings.Join(names, "/")
+}
@@ -50,8 +50,10 @@ type Object interface {
 	OnResize(w int32, h int32)
 	AddChild(obj Object)
 	RemChild(obj Object)
+	//direct children only, Walk visits every descendant
 	GetChilds() []Object
 	GetParent() Object
+	GetMeta() *Meta
 }
 
 type Portal interface {
@@ -93,6 +95,7 @@ type Portal interface {
 	OnResize(w int32, h int32)
 	AddChild(obj Object)
 	RemChild(obj Object)
+	//direct children only, Walk visits every descendant
 	GetChilds() []Object
 }
 
@@ -117,6 +120,8 @@ type Camera interface {
 	GetDist() float32
 	SetDist(d float32)
 	ZoomCam(z float32)
+	GetFovy() float32
+	SetFovy(fovy float32)
 	SetTar(p raylib.Vector3)
 	GetTar() raylib.Vector3
 	GetModelMatrix() raylib.Matrix
@@ -0,0 +1,62 @@
+package object
+
+import (
+	"strings"
+)
+
+// find a descendant of root by its id
+func FindByID(root Object, id uint64) Object {
+	var found Object
+	Walk(root, func(obj Object) bool {
+		if obj.GetMeta().GetID() == id {
+			found = obj
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// find a descendant of root by a slash separated path of names
+func FindByPath(root Object, path string) Object {
+	obj := root
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		if obj == nil {
+			return nil
+		}
+		var next Object
+		EachChild(obj, func(child Object) bool {
+			if child.GetMeta().GetName() == name {
+				next = child
+				return false
+			}
+			return true
+		})
+		obj = next
+	}
+	return obj
+}
+
+// find all descendants of root carrying a tag
+func FindByTag(root Object, tag string) []Object {
+	found := []Object{}
+	Walk(root, func(obj Object) bool {
+		if obj.GetMeta().HasTag(tag) {
+			found = append(found, obj)
+		}
+		return true
+	})
+	return found
+}
+
+// find all descendants of root of a go type
+func OfType[T Object](root Object) []T {
+	found := []T{}
+	Walk(root, func(obj Object) bool {
+		if t, ok := obj.(T); ok {
+			found = append(found, t)
+		}
+		return true
+	})
+	return found
+}
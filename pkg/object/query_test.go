@@ -0,0 +1,157 @@
+package object
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testNode struct {
+	Object
+
+	meta   Meta
+	parent Object
+	childs []Object
+}
+
+type testOther struct {
+	testNode
+}
+
+func (n *testNode) GetMeta() *Meta      { return &n.meta }
+func (n *testNode) GetParent() Object   { return n.parent }
+func (n *testNode) GetChilds() []Object { return n.childs }
+func (n *testNode) OnRemove() {
+	n.parent = nil
+	n.meta.Release()
+	RemoveChilds(n)
+}
+
+func newTestNode(parent *testNode, name string, tags ...string) *testNode {
+	n := &testNode{}
+	n.meta.SetName(name)
+	for _, tag := range tags {
+		n.meta.AddTag(tag)
+	}
+	if parent != nil {
+		n.parent = parent
+		parent.childs = append(parent.childs, n)
+	}
+	return n
+}
+
+func TestMeta(t *testing.T) {
+	a := &Meta{}
+	b := &Meta{}
+	if a.GetID() == 0 || a.GetID() == b.GetID() || a.GetID() != a.GetID() {
+		t.Error(fmt.Sprintf("TestMeta ids %d %d", a.GetID(), b.GetID()))
+	}
+
+	a.AddTag("solid")
+	a.AddTag("enemy")
+	a.RemTag("missing")
+	if !a.HasTag("solid") || a.HasTag("missing") {
+		t.Error("TestMeta HasTag")
+	}
+	if tags := a.GetTags(); len(tags) != 2 || tags[0] != "enemy" || tags[1] != "solid" {
+		t.Error(fmt.Sprintf("TestMeta GetTags %v", tags))
+	}
+	a.RemTag("enemy")
+	if a.HasTag("enemy") {
+		t.Error("TestMeta RemTag")
+	}
+
+	var nilMeta *Meta
+	if nilMeta.GetID() != 0 || nilMeta.HasTag("x") || len(nilMeta.GetTags()) != 0 {
+		t.Error("TestMeta nil")
+	}
+}
+
+func TestMetaIDRace(t *testing.T) {
+	//the logic and render loops may both ask first
+	for range 100 {
+		m := &Meta{}
+		ids := make(chan uint64, 2)
+		for range 2 {
+			go func() { ids <- m.GetID() }()
+		}
+		a, b := <-ids, <-ids
+		if a == 0 || a != b || m.GetID() != a {
+			t.Error(fmt.Sprintf("TestMetaIDRace got ids %d %d %d", a, b, m.GetID()))
+			return
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	root := newTestNode(nil, "scene")
+	world := newTestNode(root, "world")
+	cell := newTestNode(world, "cell_3_4")
+	terrain := newTestNode(cell, "terrain", "solid")
+	other := &testOther{}
+	other.meta.SetName("other")
+	other.meta.AddTag("solid")
+	other.parent = world
+	world.childs = append(world.childs, other)
+
+	var paths = []struct {
+		path string
+		want Object
+	}{
+		{"world", world},
+		{"world/cell_3_4/terrain", terrain},
+		{"/world/cell_3_4/", cell},
+		{"world/other", other},
+		{"world/cell_0_0", nil},
+		{"terrain", nil},
+	}
+	for testIndex, test := range paths {
+		if got := FindByPath(root, test.path); got != test.want {
+			t.Error(fmt.Sprintf("TestQuery path %d: %s", testIndex, test.path))
+		}
+	}
+
+	if GetPath(terrain) != "world/cell_3_4/terrain" {
+		t.Error(fmt.Sprintf("TestQuery GetPath %s", GetPath(terrain)))
+	}
+	if FindByID(root, terrain.meta.GetID()) != Object(terrain) || FindByID(root, 0) != nil {
+		t.Error("TestQuery FindByID")
+	}
+	if found := FindByTag(root, "solid"); len(found) != 2 || found[0] != Object(terrain) || found[1] != Object(other) {
+		t.Error(fmt.Sprintf("TestQuery FindByTag %d", len(found)))
+	}
+	if found := OfType[*testOther](root); len(found) != 1 || found[0] != other {
+		t.Error("TestQuery OfType")
+	}
+
+	//stopping early must not visit further objects
+	visits := 0
+	Walk(root, func(obj Object) bool {
+		visits++
+		return obj != Object(cell)
+	})
+	if visits != 2 {
+		t.Error(fmt.Sprintf("TestQuery Walk visits %d", visits))
+	}
+}
+
+func TestRemoveChilds(t *testing.T) {
+	root := newTestNode(nil, "scene")
+	world := newTestNode(root, "world")
+	cell := newTestNode(world, "cell_0_0")
+	terrain := newTestNode(cell, "terrain")
+	other := newTestNode(root, "other")
+
+	released := []string{}
+	for _, n := range []*testNode{root, world, cell, terrain, other} {
+		n.meta.OnRelease(func() {
+			released = append(released, n.meta.GetName())
+		})
+	}
+
+	//removing an object releases everything below it and nothing beside it
+	world.OnRemove()
+	want := []string{"world", "cell_0_0", "terrain"}
+	if fmt.Sprint(released) != fmt.Sprint(want) || terrain.parent != nil {
+		t.Error(fmt.Sprintf("TestRemoveChilds expected %v released, got %v", want, released))
+	}
+}
@@ -0,0 +1,50 @@
+package object
+
+import (
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+// objects without a collider that still take up space to cull and query, such as world cells
+type Bounded interface {
+	GetBounds() raylib.BoundingBox
+}
+
+// roots told when objects join or leave their tree, such as a scene keeping its spatial index
+// objects moving every tick are interpolated and are checked by the root on its own
+type TreeWatcher interface {
+	// obj and its descendants joined the tree
+	OnAttach(obj Object)
+	// obj and its descendants are leaving the tree
+	OnDetach(obj Object)
+}
+
+// find the nearest watcher at or above obj
+func findWatcher(obj Object) TreeWatcher {
+	for obj != nil {
+		if w, ok := obj.(TreeWatcher); ok {
+			return w
+		}
+		obj = obj.GetParent()
+	}
+	return nil
+}
+
+// tell the tree parent is in that obj was added to it, called by containers after OnAdd
+func Attached(parent Object, obj Object) {
+	if obj == nil {
+		return
+	}
+	if w := findWatcher(parent); w != nil {
+		w.OnAttach(obj)
+	}
+}
+
+// tell the tree parent is in that obj is being removed from it, called by containers before OnRemove
+func Detached(parent Object, obj Object) {
+	if obj == nil {
+		return
+	}
+	if w := findWatcher(parent); w != nil {
+		w.OnDetach(obj)
+	}
+}
@@ -229,13 +229,12 @@ func RaycastPortal(inRay raylib.Ray, p pub_object.Portal, entryHit raylib.RayCol
 	}
 
 	// 6) Raycast into the exit portal scene.
-	childs := lmath.Filter(exit.GetScene().GetChilds(), func(i int, v pub_object.Object) bool {
-		switch t := v.(type) {
-		case pub_object.Portal:
-			return t != exit
-		default:
-			return true
+	childs := []pub_object.Object{}
+	pub_object.Walk(exit.GetScene(), func(v pub_object.Object) bool {
+		if t, ok := v.(pub_object.Portal); !ok || t != exit {
+			childs = append(childs, v)
 		}
+		return true
 	})
 	col := RaycastCell(subRay, childs, depth)
 	col.Distance += entryHit.Distance
@@ -0,0 +1,51 @@
+package profile
+
+import (
+	"fmt"
+
+	"karalis/pkg/console"
+)
+
+// console commands for taking captures
+func init() {
+	console.Register(console.Command{
+		Name: "profile_start",
+		Help: "start capturing a trace",
+		Run: func(c *console.Console, args []string) error {
+			StartCapture()
+			c.Printf("capturing\n")
+			return nil
+		},
+	})
+	console.Register(console.Command{
+		Name: "profile_stop",
+		Args: "[file]",
+		Help: "stop capturing and write the chrome trace, trace.json by default",
+		Run: func(c *console.Console, args []string) error {
+			if !Capturing() {
+				return fmt.Errorf("No capture running")
+			}
+			path := "trace.json"
+			if len(args) > 0 {
+				path = args[0]
+			}
+			dropped, err := SaveCapture(path)
+			if err != nil {
+				return err
+			}
+			if dropped > 0 {
+				c.Printf("%d events did not fit\n", dropped)
+			}
+			c.Printf("wrote %s\n", path)
+			return nil
+		},
+	})
+	console.Register(console.Command{
+		Name: "profile_reset",
+		Help: "clear the rolling stats",
+		Run: func(c *console.Console, args []string) error {
+			Reset()
+			return nil
+		},
+	})
+}
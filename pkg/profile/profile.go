@@ -0,0 +1,283 @@
+package profile
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// frames the rolling stats are taken over
+	Window = 120
+	// events kept by a capture, later ones are dropped
+	MaxEvents = 1 << 20
+)
+
+// tracks spans are grouped in, one per loop as go has no goroutine ids
+const (
+	TrackMain  = "main"
+	TrackLogic = "logic"
+	TrackLoad  = "load"
+)
+
+var (
+	Tracks = []string{TrackMain, TrackLogic, TrackLoad}
+
+	enabled   atomic.Bool
+	capturing atomic.Bool
+
+	mu      sync.Mutex
+	start   = time.Now()
+	series  = map[string]*stat{}
+	frame   = 0
+	events  = []Event{}
+	dropped = 0
+
+	names sync.Map
+)
+
+// timed span or counter sample recorded during a capture
+type Event struct {
+	Name  string
+	Track string
+	// start since the profiler started
+	Start time.Duration
+	Dur   time.Duration
+	// counters have no duration
+	Counter bool
+	Value   float64
+}
+
+// rolling stats of a span or counter over the last Window frames
+type Stats struct {
+	Name    string
+	Counter bool
+	// per frame average of the time spent or the counter value
+	Avg time.Duration
+	Max time.Duration
+	// per frame average of the spans or samples
+	Calls float64
+	Value float64
+	Peak  float64
+}
+
+type stat struct {
+	counter bool
+	totals  [Window]time.Duration
+	calls   [Window]int
+	values  [Window]float64
+}
+
+// open span, End records it
+type Span struct {
+	name  string
+	track string
+	start time.Time
+}
+
+// turn recording on or off, captures record while on regardless
+func Enable(on bool) {
+	enabled.Store(on)
+}
+
+// whether spans are being recorded
+func Enabled() bool {
+	return enabled.Load() || capturing.Load()
+}
+
+// start timing a span, spans on one track must nest
+// does nothing when the profiler is off
+func Begin(track string, name string) Span {
+	if !Enabled() {
+		return Span{}
+	}
+	return Span{name: name, track: track, start: time.Now()}
+}
+
+// start timing a span named after a phase and the type of v
+func BeginType(track string, phase string, v interface{}) Span {
+	if !Enabled() {
+		return Span{}
+	}
+	return Begin(track, phase+" "+TypeName(v))
+}
+
+// stop timing the span
+func (s Span) End() {
+	if s.name == "" {
+		return
+	}
+	end := time.Now()
+	record(s.name, s.track, s.start, end.Sub(s.start))
+}
+
+// record a span measured by the caller
+func Observe(track string, name string, begin time.Time, dur time.Duration) {
+	if !Enabled() {
+		return
+	}
+	record(name, track, begin, dur)
+}
+
+// sample a counter such as a queue depth
+func Count(track string, name string, value float64) {
+	if !Enabled() {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	s := getStat(name)
+	s.counter = true
+	s.values[frame] = max(s.values[frame], value)
+	s.calls[frame]++
+	if capturing.Load() {
+		addEvent(Event{Name: name, Track: track, Start: time.Since(start), Counter: true, Value: value})
+	}
+}
+
+func record(name string, track string, begin time.Time, dur time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	s := getStat(name)
+	s.totals[frame] += dur
+	s.calls[frame]++
+	if capturing.Load() {
+		addEvent(Event{Name: name, Track: track, Start: begin.Sub(start), Dur: dur})
+	}
+}
+
+// callers hold mu
+func getStat(name string) *stat {
+	s, ok := series[name]
+	if !ok {
+		s = &stat{}
+		series[name] = s
+	}
+	return s
+}
+
+// callers hold mu
+func addEvent(e Event) {
+	if len(events) >= MaxEvents {
+		dropped++
+		return
+	}
+	events = append(events, e)
+}
+
+// wrap draw commands of an object so running them is timed as one span
+func Cmds(track string, phase string, obj interface{}, cmds []func()) []func() {
+	if !Enabled() || len(cmds) == 0 {
+		return cmds
+	}
+
+	name := phase + " " + TypeName(obj)
+	return []func(){func() {
+		defer Begin(track, name).End()
+		for _, cmd := range cmds {
+			cmd()
+		}
+	}}
+}
+
+// short type name of v such as world.World
+func TypeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return "nil"
+	}
+	if name, ok := names.Load(t); ok {
+		return name.(string)
+	}
+	name := t.String()
+	for len(name) > 0 && name[0] == '*' {
+		name = name[1:]
+	}
+	names.Store(t, name)
+	return name
+}
+
+// close the current frame of the rolling stats, called once per rendered frame
+func Frame() {
+	if !Enabled() {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	frame = (frame + 1) % Window
+	for _, s := range series {
+		s.totals[frame] = 0
+		s.calls[frame] = 0
+		s.values[frame] = 0
+	}
+}
+
+// get the rolling stats, spans by most time spent first then counters by name
+func GetStats() []Stats {
+	mu.Lock()
+	defer mu.Unlock()
+
+	out := []Stats{}
+	for name, s := range series {
+		st := Stats{Name: name, Counter: s.counter}
+		calls := 0
+		for i := range Window {
+			st.Avg += s.totals[i]
+			st.Max = max(st.Max, s.totals[i])
+			st.Value += s.values[i]
+			st.Peak = max(st.Peak, s.values[i])
+			calls += s.calls[i]
+		}
+		st.Avg /= Window
+		st.Value /= Window
+		st.Calls = float64(calls) / Window
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Counter != out[j].Counter {
+			return !out[i].Counter
+		}
+		if out[i].Avg != out[j].Avg {
+			return out[i].Avg > out[j].Avg
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// drop the rolling stats
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	series = map[string]*stat{}
+	frame = 0
+}
+
+// start recording events for a trace, dropping any earlier capture
+func StartCapture() {
+	mu.Lock()
+	defer mu.Unlock()
+	events = []Event{}
+	dropped = 0
+	capturing.Store(true)
+}
+
+// whether a capture is running
+func Capturing() bool {
+	return capturing.Load()
+}
+
+// stop recording and get the captured events and how many did not fit
+func StopCapture() ([]Event, int) {
+	mu.Lock()
+	defer mu.Unlock()
+	capturing.Store(false)
+	out := slices.Clone(events)
+	events = []Event{}
+	return out, dropped
+}
@@ -0,0 +1,123 @@
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDisabled(t *testing.T) {
+	Enable(false)
+	Reset()
+	Begin(TrackMain, "off").End()
+	Count(TrackMain, "off count", 3)
+	Frame()
+	if stats := GetStats(); len(stats) != 0 {
+		t.Error(fmt.Sprintf("Disabled profiler recorded stats: %+v", stats))
+	}
+	cmds := []func(){func() {}}
+	if out := Cmds(TrackMain, "Render", 1, cmds); len(out) != 1 || &out[0] != &cmds[0] {
+		t.Error("Disabled profiler wrapped commands")
+	}
+}
+
+func TestStats(t *testing.T) {
+	Enable(true)
+	defer Enable(false)
+	Reset()
+
+	tests := []struct {
+		name  string
+		calls int
+		dur   time.Duration
+	}{
+		{"a", 2, 3 * time.Millisecond},
+		{"b", 1, 1 * time.Millisecond},
+	}
+	begin := time.Now()
+	for f := 0; f < Window; f++ {
+		for _, test := range tests {
+			for range test.calls {
+				Observe(TrackMain, test.name, begin, test.dur)
+			}
+		}
+		Count(TrackMain, "depth", float64(f%4))
+		Frame()
+	}
+	//the newest frame is the one just started and still empty
+	stats := GetStats()
+	if len(stats) != 3 || stats[0].Name != "a" || stats[1].Name != "b" || stats[2].Name != "depth" {
+		t.Fatal(fmt.Sprintf("Unexpected stats order: %+v", stats))
+	}
+	for i, test := range tests {
+		s := stats[i]
+		expect := time.Duration(test.calls) * test.dur
+		if s.Max != expect || s.Avg != expect*(Window-1)/Window {
+			t.Error(fmt.Sprintf("Unexpected times for %s: avg %v max %v", test.name, s.Avg, s.Max))
+		}
+		if s.Calls != float64(test.calls*(Window-1))/Window {
+			t.Error(fmt.Sprintf("Unexpected calls for %s: %v", test.name, s.Calls))
+		}
+	}
+	if !stats[2].Counter || stats[2].Peak != 3 {
+		t.Error(fmt.Sprintf("Unexpected counter stats: %+v", stats[2]))
+	}
+}
+
+func TestTrace(t *testing.T) {
+	Reset()
+	StartCapture()
+	func() {
+		defer Begin(TrackLogic, "outer").End()
+		Begin(TrackLogic, "inner").End()
+	}()
+	Count(TrackMain, "depth", 2)
+	for _, cmd := range Cmds(TrackMain, "Render", &bytes.Buffer{}, []func(){func() {}, func() {}}) {
+		cmd()
+	}
+	events, dropped := StopCapture()
+	if dropped != 0 || len(events) != 4 {
+		t.Fatal(fmt.Sprintf("Unexpected capture: %d dropped, %+v", dropped, events))
+	}
+	if Enabled() {
+		t.Error("Profiler left enabled after the capture")
+	}
+
+	buf := bytes.Buffer{}
+	err := WriteTrace(&buf, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}{}
+	err = json.Unmarshal(buf.Bytes(), &parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		phase string
+		tid   float64
+	}{
+		{"inner", "X", 2},
+		{"outer", "X", 2},
+		{"depth", "C", 1},
+		{"Render bytes.Buffer", "X", 1},
+		{"thread_name", "M", 1},
+		{"thread_name", "M", 2},
+		{"thread_name", "M", 3},
+	}
+	if len(parsed.TraceEvents) != len(tests) {
+		t.Fatal(fmt.Sprintf("Unexpected trace events: %+v", parsed.TraceEvents))
+	}
+	for i, test := range tests {
+		e := parsed.TraceEvents[i]
+		if e["name"] != test.name || e["ph"] != test.phase || e["tid"] != test.tid {
+			t.Error(fmt.Sprintf("Unexpected trace event %d: expected %s %s %v, got %+v", i, test.name, test.phase, test.tid, e))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package profile
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"slices"
+)
+
+// event in the chrome trace event format, loadable in chrome://tracing or perfetto
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Phase string                 `json:"ph"`
+	Ts    float64                `json:"ts"`
+	Dur   float64                `json:"dur,omitempty"`
+	Pid   int                    `json:"pid"`
+	Tid   int                    `json:"tid"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+type trace struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// write captured events as chrome trace event json, each track becomes a thread
+func WriteTrace(w io.Writer, events []Event) error {
+	tracks := slices.Clone(Tracks)
+	tid := func(track string) int {
+		i := slices.Index(tracks, track)
+		if i < 0 {
+			tracks = append(tracks, track)
+			i = len(tracks) - 1
+		}
+		return i + 1
+	}
+
+	out := trace{TraceEvents: []traceEvent{}, DisplayTimeUnit: "ms"}
+	for _, e := range events {
+		te := traceEvent{
+			Name:  e.Name,
+			Phase: "X",
+			Ts:    float64(e.Start.Nanoseconds()) / 1e3,
+			Dur:   float64(e.Dur.Nanoseconds()) / 1e3,
+			Pid:   1,
+			Tid:   tid(e.Track),
+		}
+		if e.Counter {
+			te.Phase = "C"
+			te.Dur = 0
+			te.Args = map[string]interface{}{"value": e.Value}
+		}
+		out.TraceEvents = append(out.TraceEvents, te)
+	}
+	for i, track := range tracks {
+		out.TraceEvents = append(out.TraceEvents, traceEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			Pid:   1,
+			Tid:   i + 1,
+			Args:  map[string]interface{}{"name": track},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(out)
+}
+
+// stop the capture and write it to a file
+func SaveCapture(path string) (int, error) {
+	events, dropped := StopCapture()
+	file, err := os.Create(path)
+	if err != nil {
+		return dropped, err
+	}
+	defer file.Close()
+
+	err = WriteTrace(file, events)
+	if err != nil {
+		return dropped, err
+	}
+	return dropped, file.Close()
+}
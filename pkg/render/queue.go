@@ -0,0 +1,193 @@
+package render
+
+import (
+	"sort"
+
+	"karalis/pkg/profile"
+
+	pub_object "karalis/pkg/object"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	LayerBackground = -1
+	LayerDefault    = 0
+	LayerOverlay    = 1
+)
+
+// single draw submitted by an object
+type Item struct {
+	Draw        func()
+	Pos         raylib.Vector3
+	Shader      uint32
+	Texture     uint32
+	Layer       int
+	Transparent bool
+
+	depth float32
+}
+
+// objects that submit render items instead of drawing in tree order
+type Submitter interface {
+	Submit(cam pub_object.Camera, q *Queue)
+}
+
+// collects render items for a frame and orders them to cut state changes
+// within each layer opaque items draw grouped by shader and texture then front to back
+// and transparent items draw after them back to front
+type Queue struct {
+	items []Item
+
+	//how far the frame is between the previous and current logic tick
+	//submitters blend moving transforms by it
+	Alpha float32
+
+	//set by the scene while it submits to skip objects outside the view, nil collects everything
+	Visible func(obj pub_object.Object) bool
+}
+
+func NewQueue() *Queue {
+	return &Queue{
+		items: []Item{},
+		Alpha: 1,
+	}
+}
+
+// build an item drawing a mesh, keyed by the materials shader and albedo texture
+func MeshItem(mesh raylib.Mesh, mat raylib.Material, transform raylib.Matrix, draw func(raylib.Mesh, raylib.Material, raylib.Matrix)) Item {
+	item := Item{
+		Draw: func() {
+			draw(mesh, mat, transform)
+		},
+		Pos:    raylib.NewVector3(transform.M12, transform.M13, transform.M14),
+		Shader: mat.Shader.ID,
+	}
+	if mat.Maps != nil {
+		item.Texture = mat.Maps.Texture.ID
+	}
+	return item
+}
+
+func (q *Queue) Add(item Item) {
+	if q == nil || item.Draw == nil {
+		return
+	}
+
+	q.items = append(q.items, item)
+}
+
+// add draw commands from objects without depth or state information
+// these keep their submission order ahead of keyed opaque items on the same layer
+func (q *Queue) AddCmds(cmds []func()) {
+	for _, cmd := range cmds {
+		q.Add(Item{Draw: cmd, depth: -1})
+	}
+}
+
+// submit an object through the queue if it supports it, otherwise its render commands
+// objects the queue finds out of view are skipped along with their children
+func Collect(q *Queue, cam pub_object.Camera, obj pub_object.Object) {
+	if obj == nil {
+		return
+	}
+	if !q.IsVisible(obj) {
+		return
+	}
+
+	start := len(q.items)
+	if sub, ok := obj.(Submitter); ok {
+		sub.Submit(cam, q)
+	} else {
+		q.AddCmds(obj.Render(cam))
+	}
+
+	//time the draws of the object wherever sorting puts them
+	if profile.Enabled() {
+		name := "Render " + profile.TypeName(obj)
+		for i := start; i < len(q.items); i++ {
+			draw := q.items[i].Draw
+			q.items[i].Draw = func() {
+				defer profile.Begin(profile.TrackMain, name).End()
+				draw()
+			}
+		}
+	}
+}
+
+// check if an object is in view of the submitting scene, containers culling only part of themselves ask this directly
+func (q *Queue) IsVisible(obj pub_object.Object) bool {
+	if q == nil || q.Visible == nil {
+		return true
+	}
+
+	return q.Visible(obj)
+}
+
+func (q *Queue) Len() int {
+	if q == nil {
+		return 0
+	}
+
+	return len(q.items)
+}
+
+// drop all items keeping the allocated space for the next frame
+func (q *Queue) Reset() {
+	if q == nil {
+		return
+	}
+
+	clear(q.items)
+	q.items = q.items[:0]
+}
+
+// order the items as seen from eye
+func (q *Queue) Sort(eye raylib.Vector3) {
+	if q == nil {
+		return
+	}
+
+	for i := range q.items {
+		if q.items[i].depth >= 0 || q.items[i].Transparent {
+			q.items[i].depth = raylib.Vector3DistanceSqr(eye, q.items[i].Pos)
+		}
+	}
+
+	sort.SliceStable(q.items, func(i, j int) bool {
+		a, b := &q.items[i], &q.items[j]
+		if a.Layer != b.Layer {
+			return a.Layer < b.Layer
+		}
+		if a.Transparent != b.Transparent {
+			return b.Transparent
+		}
+		if a.Transparent {
+			return a.depth > b.depth
+		}
+		if (a.depth < 0) != (b.depth < 0) {
+			return a.depth < 0
+		}
+		if a.Shader != b.Shader {
+			return a.Shader < b.Shader
+		}
+		if a.Texture != b.Texture {
+			return a.Texture < b.Texture
+		}
+		return a.depth < b.depth
+	})
+}
+
+// sort the items and get their draw commands in order
+func (q *Queue) Cmds(eye raylib.Vector3) []func() {
+	if q == nil {
+		return []func(){}
+	}
+
+	q.Sort(eye)
+	cmds := make([]func(), 0, len(q.items))
+	for _, item := range q.items {
+		cmds = append(cmds, item.Draw)
+	}
+	return cmds
+}
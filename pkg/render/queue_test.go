@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestQueueOrder(t *testing.T) {
+	order := []string{}
+	item := func(name string, z float32, shader, tex uint32, layer int, transparent bool) Item {
+		return Item{
+			Draw:        func() { order = append(order, name) },
+			Pos:         raylib.NewVector3(0, 0, z),
+			Shader:      shader,
+			Texture:     tex,
+			Layer:       layer,
+			Transparent: transparent,
+		}
+	}
+
+	q := NewQueue()
+	q.Add(item("glass far", 20, 1, 1, LayerDefault, true))
+	q.Add(item("rock near b", 1, 2, 1, LayerDefault, false))
+	q.Add(item("rock far a", 10, 1, 1, LayerDefault, false))
+	q.Add(item("rock near a", 2, 1, 1, LayerDefault, false))
+	q.Add(item("tex2 a", 0, 1, 2, LayerDefault, false))
+	q.Add(item("glass near", 5, 1, 1, LayerDefault, true))
+	q.Add(item("hud", 0, 1, 1, LayerOverlay, false))
+	q.Add(item("sky", 100, 9, 9, LayerBackground, false))
+	q.AddCmds([]func(){
+		func() { order = append(order, "cmd 1") },
+		func() { order = append(order, "cmd 2") },
+	})
+	q.Add(Item{})
+
+	if q.Len() != 10 {
+		t.Error(fmt.Sprintf("TestQueueOrder len %d", q.Len()))
+	}
+	for _, cmd := range q.Cmds(raylib.Vector3{}) {
+		cmd()
+	}
+
+	want := []string{
+		"sky",
+		"cmd 1", "cmd 2",
+		"rock near a", "rock far a", "tex2 a", "rock near b",
+		"glass far", "glass near",
+		"hud",
+	}
+	if !slices.Equal(order, want) {
+		t.Error(fmt.Sprintf("TestQueueOrder got %v", order))
+	}
+
+	q.Reset()
+	if q.Len() != 0 || len(q.Cmds(raylib.Vector3{})) != 0 {
+		t.Error("TestQueueOrder reset")
+	}
+}
+
+func TestMeshItem(t *testing.T) {
+	tex := raylib.MaterialMap{Texture: raylib.Texture2D{ID: 7}}
+	mat := raylib.Material{Shader: raylib.Shader{ID: 3}, Maps: &tex}
+	drawn := false
+	item := MeshItem(raylib.Mesh{}, mat, raylib.MatrixTranslate(1, 2, 3), func(raylib.Mesh, raylib.Material, raylib.Matrix) {
+		drawn = true
+	})
+
+	if item.Shader != 3 || item.Texture != 7 || item.Pos != raylib.NewVector3(1, 2, 3) {
+		t.Error(fmt.Sprintf("TestMeshItem got %d %d %v", item.Shader, item.Texture, item.Pos))
+	}
+	item.Draw()
+	if !drawn {
+		t.Error("TestMeshItem draw")
+	}
+}
@@ -0,0 +1,39 @@
+package rng
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// generate a size x size top down preview of a world kind without a window
+// the tile at the origin is generated with the same scales world cells use
+func Preview(kind string, seed int64, size int) (*image.RGBA, error) {
+	if size < 2 {
+		return nil, fmt.Errorf("Invalid preview size: %d", size)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	switch kind {
+	case "terrain":
+		h := GenerateHeightmapTiledWorldSize(size, size, seed, 0, 0, 10)
+		cols := ColorizeHeightmapTiled(h, size, size, seed, 0, 0, 10, size, size, true)
+		for i, col := range cols {
+			img.Set(i%size, i/size, col)
+		}
+	case "city":
+		h := GenerateCityTile(size, size, seed, 0, 0, 10)
+		for i, v := range h {
+			img.Set(i%size, i/size, color.RGBA{v, v, v, 255})
+		}
+	case "dungeon":
+		cols := ToGrayscaleColors(GenerateDungeonTile(size, size, seed, 0, 0), size, size)
+		for i, col := range cols {
+			img.Set(i%size, i/size, col)
+		}
+	default:
+		return nil, fmt.Errorf("Invalid world kind: %s", kind)
+	}
+
+	return img, nil
+}
@@ -0,0 +1,280 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"karalis/pkg/config"
+	"karalis/pkg/scene"
+)
+
+const (
+	// current save file format version
+	Version = 1
+
+	StateFile   = "save.json"
+	ThumbFile   = "thumb.png"
+	ThumbWidth  = 256
+	DefaultSlot = "quick"
+)
+
+var (
+	migrations = map[int]func(*State) error{}
+	slotName   = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+// where the player stands and looks
+type Player struct {
+	Pos   [3]float32 `json:"pos"`
+	Yaw   float32    `json:"yaw"`
+	Pitch float32    `json:"pitch"`
+	Dist  float32    `json:"dist"`
+	// 0 first person, 1 orbiting
+	Mode int `json:"mode"`
+}
+
+// play state kept in a slot
+type State struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	World   string    `json:"world"`
+	Seed    int64     `json:"seed"`
+	Player  Player    `json:"player"`
+	// children of world cells changed since they were generated, by cell key
+	Cells map[string][]scene.Node `json:"cells,omitempty"`
+	// scene file of the game, objects keep their state through scene.Serializable
+	// it carries its own version and is migrated by the scene package
+	Scene json.RawMessage `json:"scene"`
+}
+
+// summary of a saved slot
+type Slot struct {
+	Name  string
+	Time  time.Time
+	World string
+	Seed  int64
+	// empty when the slot has no thumbnail
+	Thumb string
+}
+
+// register a migration upgrading saves written at version from to from+1
+func RegisterMigration(from int, migrate func(*State) error) {
+	migrations[from] = migrate
+}
+
+// folder in the config dir slots are kept in
+func Dir() string {
+	return filepath.Join(config.ConfigDir, "saves")
+}
+
+// check a slot name can be used as a folder name
+func ValidSlot(name string) error {
+	if !slotName.MatchString(name) {
+		return fmt.Errorf("Invalid slot name: %q", name)
+	}
+	return nil
+}
+
+// write a slot, replacing what it held
+// files are swapped in whole so a crash while saving leaves the previous save readable
+func Write(dir string, slot string, s *State, thumb image.Image) error {
+	if s == nil {
+		return fmt.Errorf("Invalid save")
+	}
+	err := ValidSlot(slot)
+	if err != nil {
+		return err
+	}
+
+	s.Version = Version
+	txt, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, slot)
+	err = os.MkdirAll(path, 0755)
+	if err != nil {
+		return err
+	}
+
+	//the thumbnail is encoded up front so a failing one leaves the slot untouched
+	var pic []byte
+	if thumb != nil {
+		buf := &bytes.Buffer{}
+		err = png.Encode(buf, Thumbnail(thumb, ThumbWidth))
+		if err != nil {
+			return err
+		}
+		pic = buf.Bytes()
+	}
+
+	//the state goes first, a crash before the thumbnail at worst leaves an old picture
+	err = WriteFile(filepath.Join(path, StateFile), append(txt, '\n'))
+	if err != nil {
+		return err
+	}
+	thumbPath := filepath.Join(path, ThumbFile)
+	if pic == nil {
+		//a thumbnail of an older save would not match
+		err = os.Remove(thumbPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return WriteFile(thumbPath, pic)
+}
+
+// read a slot, upgrading it to the current version
+func Read(dir string, slot string) (*State, error) {
+	err := ValidSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, slot, StateFile))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{}
+	err = json.Unmarshal(data, s)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid save %s: %w", slot, err)
+	}
+	if s.Version > Version {
+		return nil, fmt.Errorf("Save version %d is newer than supported version %d", s.Version, Version)
+	}
+	for s.Version < Version {
+		migrate, ok := migrations[s.Version]
+		if !ok {
+			return nil, fmt.Errorf("No migration from save version %d", s.Version)
+		}
+		err = migrate(s)
+		if err != nil {
+			return nil, err
+		}
+		s.Version++
+	}
+	if len(s.Scene) == 0 {
+		return nil, fmt.Errorf("Save %s has no scene", slot)
+	}
+	return s, nil
+}
+
+// list the slots in dir, newest first
+// folders without a readable save are left out
+func List(dir string) ([]Slot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Slot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []Slot{}
+	for _, entry := range entries {
+		if !entry.IsDir() || ValidSlot(entry.Name()) != nil {
+			continue
+		}
+		s, err := Read(dir, entry.Name())
+		if err != nil {
+			continue
+		}
+		slot := Slot{Name: entry.Name(), Time: s.Time, World: s.World, Seed: s.Seed}
+		thumb := filepath.Join(dir, entry.Name(), ThumbFile)
+		if _, err := os.Stat(thumb); err == nil {
+			slot.Thumb = thumb
+		}
+		slots = append(slots, slot)
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Time.After(slots[j].Time)
+	})
+	return slots, nil
+}
+
+// remove a slot and its files
+func Delete(dir string, slot string) error {
+	err := ValidSlot(slot)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, slot)
+	if _, err := os.Stat(filepath.Join(path, StateFile)); err != nil {
+		return fmt.Errorf("No save in slot %s", slot)
+	}
+	return os.RemoveAll(path)
+}
+
+// write a file through a temporary one renamed over it, readers see the old or new data and never a part
+func WriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	//no-op once renamed
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	cerr := tmp.Close()
+	if err != nil {
+		return err
+	}
+	if cerr != nil {
+		return cerr
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// scale an image down to width keeping its aspect, averaging the pixels each one covers
+func Thumbnail(img image.Image, width int) *image.RGBA {
+	b := img.Bounds()
+	if b.Dx() <= width || width <= 0 {
+		out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		for y := range b.Dy() {
+			for x := range b.Dx() {
+				out.Set(x, y, img.At(b.Min.X+x, b.Min.Y+y))
+			}
+		}
+		return out
+	}
+
+	height := max(b.Dy()*width/b.Dx(), 1)
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := max(b.Min.Y+(y+1)*b.Dy()/height, y0+1)
+		for x := range width {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := max(b.Min.X+(x+1)*b.Dx()/width, x0+1)
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+cr, g+cg, bl+cb, a+ca, n+1
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(bl / n >> 8), uint8(a / n >> 8)})
+		}
+	}
+	return out
+}
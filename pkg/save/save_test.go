@@ -0,0 +1,140 @@
+package save
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"karalis/pkg/scene"
+)
+
+func testState(seed int64, t time.Time) *State {
+	return &State{
+		Time:   t,
+		World:  "city",
+		Seed:   seed,
+		Player: Player{Pos: [3]float32{1, 2, 3}, Yaw: 45, Pitch: -30, Dist: 4, Mode: 1},
+		Cells:  map[string][]scene.Node{"1 -2": {{Type: "Prim", Name: "crate"}}},
+		Scene:  []byte(`{"version": 1, "root": {"type": "Scene"}}`),
+	}
+}
+
+func TestSlots(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	thumb := image.NewRGBA(image.Rect(0, 0, 640, 360))
+
+	err := Write(dir, "first", testState(1, now), thumb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Write(dir, "second", testState(2, now.Add(time.Minute)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//not a slot
+	os.MkdirAll(filepath.Join(dir, "empty"), 0755)
+
+	s, err := Read(dir, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := testState(1, now)
+	if s.Version != Version || s.Seed != expect.Seed || s.Player != expect.Player || s.Cells["1 -2"][0].Name != "crate" || !s.Time.Equal(now) {
+		t.Error(fmt.Sprintf("Unexpected save: %+v", s))
+	}
+
+	slots, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, slot := range slots {
+		names = append(names, slot.Name)
+	}
+	if !slices.Equal(names, []string{"second", "first"}) || slots[0].Thumb != "" || slots[1].Thumb == "" {
+		t.Error(fmt.Sprintf("Unexpected slots: %+v", slots))
+	}
+
+	//overwriting without a thumbnail drops the old one, no temporary files are left
+	err = Write(dir, "first", testState(3, now), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, _ := os.ReadDir(filepath.Join(dir, "first"))
+	if len(files) != 1 || files[0].Name() != StateFile {
+		t.Error(fmt.Sprintf("Unexpected files in slot: %v", files))
+	}
+
+	err = Delete(dir, "second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slot := range []string{"second", "empty", "../first", ""} {
+		if _, err := Read(dir, slot); err == nil {
+			t.Error(fmt.Sprintf("Read slot %q", slot))
+		}
+		if err := Delete(dir, slot); err == nil {
+			t.Error(fmt.Sprintf("Deleted slot %q", slot))
+		}
+	}
+}
+
+func TestMigration(t *testing.T) {
+	dir := t.TempDir()
+	write := func(slot string, txt string) {
+		os.MkdirAll(filepath.Join(dir, slot), 0755)
+		os.WriteFile(filepath.Join(dir, slot, StateFile), []byte(txt), 0644)
+	}
+	write("old", `{"version": 0, "world": "dungeon", "scene": {"version": 1}}`)
+	write("new", fmt.Sprintf(`{"version": %d, "scene": {"version": 1}}`, Version+1))
+	write("noscene", fmt.Sprintf(`{"version": %d}`, Version))
+	write("broken", `{"version": `)
+
+	_, err := Read(dir, "old")
+	if err == nil {
+		t.Error("Read a save without a migration")
+	}
+	RegisterMigration(0, func(s *State) error {
+		s.World = "city"
+		return nil
+	})
+	defer delete(migrations, 0)
+	s, err := Read(dir, "old")
+	if err != nil || s.Version != Version || s.World != "city" {
+		t.Error(fmt.Sprintf("Unexpected migrated save: %+v %+v", s, err))
+	}
+	for _, slot := range []string{"new", "noscene", "broken"} {
+		if _, err := Read(dir, slot); err == nil {
+			t.Error(fmt.Sprintf("Read invalid save %s", slot))
+		}
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := range 4 {
+		img.SetRGBA(x, 0, color.RGBA{200, 0, 0, 255})
+		img.SetRGBA(x, 1, color.RGBA{0, 100, 0, 255})
+	}
+	tests := []struct {
+		width  int
+		size   image.Point
+		center color.RGBA
+	}{
+		{2, image.Pt(2, 1), color.RGBA{100, 50, 0, 255}},
+		{4, image.Pt(4, 2), color.RGBA{200, 0, 0, 255}},
+		{8, image.Pt(4, 2), color.RGBA{200, 0, 0, 255}},
+	}
+	for i, test := range tests {
+		got := Thumbnail(img, test.width)
+		if got.Bounds().Size() != test.size || got.RGBAAt(0, 0) != test.center {
+			t.Error(fmt.Sprintf("Unexpected thumbnail for case %d: %v %v", i, got.Bounds().Size(), got.RGBAAt(0, 0)))
+		}
+	}
+}
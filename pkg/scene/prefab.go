@@ -0,0 +1,192 @@
+package scene
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"karalis/pkg/object"
+)
+
+var (
+	// reads prefab files, replaced by the engine to read from its resources
+	PrefabReader func(path string) ([]byte, error) = os.ReadFile
+
+	// how deep prefabs may nest before a cycle is assumed
+	MaxPrefabDepth = 16
+
+	prefabMu    sync.Mutex
+	prefabCache map[string]map[string]interface{} = map[string]map[string]interface{}{}
+)
+
+// node fields every prefab starts from
+func prefabDefaults() map[string]interface{} {
+	return map[string]interface{}{
+		"pos":   []interface{}{0.0, 0.0, 0.0},
+		"rot":   []interface{}{0.0, 0.0, 0.0},
+		"scale": []interface{}{1.0, 1.0, 1.0},
+	}
+}
+
+// build the object tree described by a prefab file and add it to parent
+func Instantiate(prefab string, parent object.Object) (object.Object, error) {
+	return InstantiateWith(prefab, parent, nil)
+}
+
+// build a prefab with some of its root node fields overridden
+// overrides use the node keys of the file, maps such as props merge key by key and childs are appended
+func InstantiateWith(prefab string, parent object.Object, overrides map[string]interface{}) (object.Object, error) {
+	raw := map[string]interface{}{"prefab": prefab}
+	for key, val := range overrides {
+		raw[key] = val
+	}
+
+	n, err := ResolvePrefab(raw)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := Decode(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent != nil {
+		parent.AddChild(obj)
+	}
+	return obj, nil
+}
+
+// expand prefab references in a raw node into a plain node
+func ResolvePrefab(raw map[string]interface{}) (Node, error) {
+	n := Node{}
+	resolved, err := resolvePrefab(raw, 0)
+	if err != nil {
+		return n, err
+	}
+
+	txt, err := json.Marshal(resolved)
+	if err != nil {
+		return n, err
+	}
+	err = json.Unmarshal(txt, &n)
+	return n, err
+}
+
+// drop cached prefab files so they are read again on next use
+func ClearPrefabs() {
+	prefabMu.Lock()
+	defer prefabMu.Unlock()
+
+	prefabCache = map[string]map[string]interface{}{}
+}
+
+func resolvePrefab(raw map[string]interface{}, depth int) (map[string]interface{}, error) {
+	if depth > MaxPrefabDepth {
+		return nil, fmt.Errorf("Prefabs nested too deep")
+	}
+
+	node := mergeNode(prefabDefaults(), raw)
+	if path, ok := raw["prefab"].(string); ok {
+		base, err := readPrefab(path)
+		if err != nil {
+			return nil, err
+		}
+		base, err = resolvePrefab(base, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("Prefab %s: %w", path, err)
+		}
+		node = mergeNode(base, raw)
+	}
+	delete(node, "prefab")
+
+	childs, _ := node["childs"].([]interface{})
+	for i, child := range childs {
+		childRaw, ok := child.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Invalid prefab child: %v", child)
+		}
+		resolved, err := resolvePrefab(childRaw, depth)
+		if err != nil {
+			return nil, err
+		}
+		childs[i] = resolved
+	}
+
+	return node, nil
+}
+
+// get a fresh copy of a prefab files root node
+func readPrefab(path string) (map[string]interface{}, error) {
+	prefabMu.Lock()
+	defer prefabMu.Unlock()
+
+	root, ok := prefabCache[path]
+	if !ok {
+		data, err := PrefabReader(path)
+		if err != nil {
+			return nil, err
+		}
+
+		f := struct {
+			Version int                    `json:"version"`
+			Root    map[string]interface{} `json:"root"`
+		}{}
+		err = json.Unmarshal(data, &f)
+		if err != nil {
+			return nil, fmt.Errorf("Prefab %s: %w", path, err)
+		}
+		if f.Version != Version {
+			return nil, fmt.Errorf("Prefab %s has unsupported version %d", path, f.Version)
+		}
+		if f.Root == nil {
+			return nil, fmt.Errorf("Prefab %s has no root", path)
+		}
+
+		root = f.Root
+		prefabCache[path] = root
+	}
+
+	return copyValue(root).(map[string]interface{}), nil
+}
+
+// overlay the set fields of over onto base
+func mergeNode(base map[string]interface{}, over map[string]interface{}) map[string]interface{} {
+	out := copyValue(base).(map[string]interface{})
+	for key, val := range over {
+		switch key {
+		case "childs":
+			baseChilds, _ := out[key].([]interface{})
+			overChilds, _ := val.([]interface{})
+			out[key] = append(baseChilds, copyValue(overChilds).([]interface{})...)
+		default:
+			baseMap, baseOk := out[key].(map[string]interface{})
+			overMap, overOk := val.(map[string]interface{})
+			if baseOk && overOk {
+				out[key] = mergeNode(baseMap, overMap)
+			} else {
+				out[key] = copyValue(val)
+			}
+		}
+	}
+	return out
+}
+
+func copyValue(val interface{}) interface{} {
+	switch t := val.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for key, v := range t {
+			out[key] = copyValue(v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, v := range t {
+			out[i] = copyValue(v)
+		}
+		return out
+	default:
+		return val
+	}
+}
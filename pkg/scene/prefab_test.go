@@ -0,0 +1,124 @@
+package scene
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+	"testing"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+var testPrefabs = map[string]string{
+	"leaf.json": `{"version": 1, "root": {"type": "TestLeaf", "name": "leaf", "color": [1, 2, 3, 4], "props": {"hp": 10, "team": "red"}}}`,
+	"tree.json": `{"version": 1, "root": {"type": "TestObj", "name": "tree", "pos": [5, 0, 0], "childs": [
+		{"prefab": "leaf.json", "name": "left", "pos": [-1, 0, 0]},
+		{"prefab": "leaf.json", "name": "right", "props": {"hp": 20}},
+		{"type": "TestLeaf", "name": "plain"}
+	]}}`,
+	"loop.json": `{"version": 1, "root": {"prefab": "loop.json"}}`,
+	"old.json":  `{"version": 0, "root": {"type": "TestObj"}}`,
+}
+
+func readTestPrefab(path string) ([]byte, error) {
+	data, ok := testPrefabs[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return []byte(data), nil
+}
+
+func TestInstantiate(t *testing.T) {
+	PrefabReader = readTestPrefab
+	defer ClearPrefabs()
+
+	parent := &testObj{}
+	obj, err := InstantiateWith("tree.json", parent, map[string]interface{}{
+		"name":   "oak",
+		"childs": []interface{}{map[string]interface{}{"prefab": "leaf.json", "name": "extra"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := obj.(*testObj)
+	if len(parent.childs) != 1 || tree.GetParent() != parent {
+		t.Error("TestInstantiate not added to parent")
+	}
+	if tree.meta.GetName() != "oak" || tree.pos != raylib.NewVector3(5, 0, 0) || tree.scale != raylib.NewVector3(1, 1, 1) {
+		t.Error(fmt.Sprintf("TestInstantiate root %s %v %v", tree.meta.GetName(), tree.pos, tree.scale))
+	}
+
+	var cases = []struct {
+		name string
+		pos  raylib.Vector3
+		hp   interface{}
+		col  color.RGBA
+	}{
+		{"left", raylib.NewVector3(-1, 0, 0), 10.0, color.RGBA{1, 2, 3, 4}},
+		{"right", raylib.NewVector3(0, 0, 0), 20.0, color.RGBA{1, 2, 3, 4}},
+		{"plain", raylib.NewVector3(0, 0, 0), nil, color.RGBA{}},
+		{"extra", raylib.NewVector3(0, 0, 0), 10.0, color.RGBA{1, 2, 3, 4}},
+	}
+	if len(tree.childs) != len(cases) {
+		t.Fatal(fmt.Sprintf("TestInstantiate childs %d", len(tree.childs)))
+	}
+	for testIndex, test := range cases {
+		leaf := tree.childs[testIndex].(*testLeaf)
+		if leaf.meta.GetName() != test.name || leaf.pos != test.pos || leaf.color != test.col || leaf.props["hp"] != test.hp {
+			t.Error(fmt.Sprintf("TestInstantiate child %d: %s %v %v %v", testIndex, leaf.meta.GetName(), leaf.pos, leaf.color, leaf.props))
+		}
+		if leaf.scale != raylib.NewVector3(1, 1, 1) {
+			t.Error(fmt.Sprintf("TestInstantiate child %d scale %v", testIndex, leaf.scale))
+		}
+	}
+	//props merge key by key so the untouched team survives the override
+	if tree.childs[1].(*testLeaf).props["team"] != "red" {
+		t.Error("TestInstantiate props merge")
+	}
+
+	//instances must not share state with the cached prefab
+	other, err := Instantiate("tree.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other.(*testObj).childs) != 3 || other.(*testObj).meta.GetName() != "tree" {
+		t.Error("TestInstantiate cache modified by overrides")
+	}
+}
+
+func TestInstantiateErrors(t *testing.T) {
+	PrefabReader = readTestPrefab
+	defer ClearPrefabs()
+
+	var cases = []struct {
+		prefab string
+		want   string
+	}{
+		{"missing.json", "not exist"},
+		{"loop.json", "nested too deep"},
+		{"old.json", "unsupported version"},
+	}
+
+	for testIndex, test := range cases {
+		_, err := Instantiate(test.prefab, nil)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Error(fmt.Sprintf("TestInstantiateErrors %d: got %v", testIndex, err))
+		}
+	}
+}
+
+func TestLoadPrefabRef(t *testing.T) {
+	PrefabReader = readTestPrefab
+	defer ClearPrefabs()
+
+	obj, err := Load(strings.NewReader(`{"version": 1, "root": {"type": "TestObj", "childs": [{"prefab": "leaf.json", "pos": [0, 3, 0]}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := obj.(*testObj).childs[0].(*testLeaf)
+	if leaf.pos != raylib.NewVector3(0, 3, 0) || leaf.meta.GetName() != "leaf" {
+		t.Error(fmt.Sprintf("TestLoadPrefabRef got %s %v", leaf.meta.GetName(), leaf.pos))
+	}
+}
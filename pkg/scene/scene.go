@@ -0,0 +1,263 @@
+package scene
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"reflect"
+
+	"karalis/pkg/object"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+// current scene file format version
+const Version = 1
+
+var (
+	factories  map[string]Factory        = map[string]Factory{}
+	typeNames  map[reflect.Type]string   = map[reflect.Type]string{}
+	migrations map[int]func(*File) error = map[int]func(*File) error{}
+)
+
+// builds an empty object of a registered type from its saved node
+type Factory func(n Node) (object.Object, error)
+
+// objects that persist state beyond their transform and color
+type Serializable interface {
+	Serialize() map[string]interface{}
+	Deserialize(props map[string]interface{}) error
+}
+
+// objects generated from a world seed
+type Seeded interface {
+	GetSeed() int64
+	SetSeed(seed int64)
+}
+
+// objects whose collider shape can be chosen, "none" disables collisions
+type Collidable interface {
+	GetColliderShape() string
+	SetColliderShape(shape string) error
+}
+
+// objects textured from a resource path
+type Textured interface {
+	GetTextureRes() string
+	SetTextureRes(path string) error
+}
+
+type File struct {
+	Version int  `json:"version"`
+	Root    Node `json:"root"`
+}
+
+// seed is nil for objects without one, so a seed of 0 is kept
+type Node struct {
+	Type     string                 `json:"type"`
+	Name     string                 `json:"name,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+	Pos      [3]float32             `json:"pos"`
+	Rot      [3]float32             `json:"rot"`
+	Scale    [3]float32             `json:"scale"`
+	Color    *[4]uint8              `json:"color,omitempty"`
+	Texture  string                 `json:"texture,omitempty"`
+	Collider string                 `json:"collider,omitempty"`
+	Seed     *int64                 `json:"seed,omitempty"`
+	Props    map[string]interface{} `json:"props,omitempty"`
+	Childs   []Node                 `json:"childs,omitempty"`
+}
+
+// register an object type under a name used in scene files
+func Register[T object.Object](name string, factory func(n Node) (T, error)) {
+	factories[name] = func(n Node) (object.Object, error) {
+		return factory(n)
+	}
+	typeNames[reflect.TypeFor[T]()] = name
+}
+
+// register a migration upgrading files saved at version from to from+1
+func RegisterMigration(from int, migrate func(*File) error) {
+	migrations[from] = migrate
+}
+
+// get the registered name of an objects type
+func TypeName(obj object.Object) (string, bool) {
+	if obj == nil {
+		return "", false
+	}
+	name, ok := typeNames[reflect.TypeOf(obj)]
+	return name, ok
+}
+
+// encode an object and its direct children into a node
+// objects of unregistered types are runtime only and are skipped
+func Encode(obj object.Object) (Node, bool) {
+	name, ok := TypeName(obj)
+	if !ok {
+		return Node{}, false
+	}
+
+	pos := obj.GetPos()
+	scale := obj.GetScale()
+	n := Node{
+		Type:  name,
+		Pos:   [3]float32{pos.X, pos.Y, pos.Z},
+		Rot:   [3]float32{obj.GetPitch(), obj.GetYaw(), obj.GetRoll()},
+		Scale: [3]float32{scale.X, scale.Y, scale.Z},
+	}
+	if meta := obj.GetMeta(); meta != nil {
+		n.Name = meta.GetName()
+		if tags := meta.GetTags(); len(tags) > 0 {
+			n.Tags = tags
+		}
+	}
+	if col := obj.GetColor(); col != nil {
+		rgba := color.RGBAModel.Convert(col).(color.RGBA)
+		n.Color = &[4]uint8{rgba.R, rgba.G, rgba.B, rgba.A}
+	}
+	if tex, ok := obj.(Textured); ok {
+		n.Texture = tex.GetTextureRes()
+	}
+	if seeded, ok := obj.(Seeded); ok {
+		seed := seeded.GetSeed()
+		n.Seed = &seed
+	}
+	if col, ok := obj.(Collidable); ok {
+		n.Collider = col.GetColliderShape()
+	}
+	if ser, ok := obj.(Serializable); ok {
+		n.Props = ser.Serialize()
+	}
+
+	for _, child := range obj.GetChilds() {
+		if cn, ok := Encode(child); ok {
+			n.Childs = append(n.Childs, cn)
+		}
+	}
+
+	return n, true
+}
+
+// rebuild an object and its children from a node
+func Decode(n Node) (object.Object, error) {
+	factory, ok := factories[n.Type]
+	if !ok {
+		return nil, fmt.Errorf("Unknown object type: %s", n.Type)
+	}
+	obj, err := factory(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if meta := obj.GetMeta(); meta != nil {
+		if n.Name != "" {
+			meta.SetName(n.Name)
+		}
+		for _, tag := range n.Tags {
+			meta.AddTag(tag)
+		}
+	}
+	obj.SetPos(raylib.NewVector3(n.Pos[0], n.Pos[1], n.Pos[2]))
+	obj.SetPitch(n.Rot[0])
+	obj.SetYaw(n.Rot[1])
+	obj.SetRoll(n.Rot[2])
+	obj.SetScale(raylib.NewVector3(n.Scale[0], n.Scale[1], n.Scale[2]))
+	if n.Color != nil {
+		obj.SetColor(color.RGBA{n.Color[0], n.Color[1], n.Color[2], n.Color[3]})
+	}
+	if seeded, ok := obj.(Seeded); ok && n.Seed != nil {
+		seeded.SetSeed(*n.Seed)
+	}
+	if tex, ok := obj.(Textured); ok && n.Texture != "" {
+		err = tex.SetTextureRes(n.Texture)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if col, ok := obj.(Collidable); ok && n.Collider != "" {
+		err = col.SetColliderShape(n.Collider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if ser, ok := obj.(Serializable); ok && n.Props != nil {
+		err = ser.Deserialize(n.Props)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cn := range n.Childs {
+		child, err := Decode(cn)
+		if err != nil {
+			return nil, err
+		}
+		obj.AddChild(child)
+	}
+
+	return obj, nil
+}
+
+// write an object tree as a versioned scene file
+func Save(w io.Writer, root object.Object) error {
+	n, ok := Encode(root)
+	if !ok {
+		return fmt.Errorf("Unregistered scene root: %T", root)
+	}
+
+	txt, err := json.MarshalIndent(File{Version: Version, Root: n}, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(txt, '\n'))
+	return err
+}
+
+// read a scene file, upgrading it to the current version
+func Read(r io.Reader) (*File, error) {
+	raw := struct {
+		Version int                    `json:"version"`
+		Root    map[string]interface{} `json:"root"`
+	}{}
+	err := json.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw.Version > Version {
+		return nil, fmt.Errorf("Scene version %d is newer than supported version %d", raw.Version, Version)
+	}
+
+	//nodes may reference prefabs which are expanded before decoding
+	f := &File{Version: raw.Version}
+	if raw.Root != nil {
+		f.Root, err = ResolvePrefab(raw.Root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for f.Version < Version {
+		migrate, ok := migrations[f.Version]
+		if !ok {
+			return nil, fmt.Errorf("No migration from scene version %d", f.Version)
+		}
+		err = migrate(f)
+		if err != nil {
+			return nil, err
+		}
+		f.Version++
+	}
+
+	return f, nil
+}
+
+// read a scene file and rebuild its object tree
+func Load(r io.Reader) (object.Object, error) {
+	f, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(f.Root)
+}
@@ -0,0 +1,219 @@
+package scene
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+	"testing"
+
+	"karalis/pkg/object"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+type testObj struct {
+	object.Object
+
+	meta   object.Meta
+	pos    raylib.Vector3
+	rot    raylib.Vector3
+	scale  raylib.Vector3
+	color  color.RGBA
+	seed   int64
+	tex    string
+	props  map[string]interface{}
+	parent object.Object
+	childs []object.Object
+}
+
+type testLeaf struct {
+	testObj
+}
+
+type testRuntime struct {
+	testObj
+}
+
+func (o *testObj) GetPos() raylib.Vector3       { return o.pos }
+func (o *testObj) SetPos(p raylib.Vector3)      { o.pos = p }
+func (o *testObj) GetPitch() float32            { return o.rot.X }
+func (o *testObj) SetPitch(p float32)           { o.rot.X = p }
+func (o *testObj) GetYaw() float32              { return o.rot.Y }
+func (o *testObj) SetYaw(y float32)             { o.rot.Y = y }
+func (o *testObj) GetRoll() float32             { return o.rot.Z }
+func (o *testObj) SetRoll(r float32)            { o.rot.Z = r }
+func (o *testObj) GetScale() raylib.Vector3     { return o.scale }
+func (o *testObj) SetScale(s raylib.Vector3)    { o.scale = s }
+func (o *testObj) GetColor() color.Color        { return o.color }
+func (o *testObj) SetColor(c color.Color)       { o.color = c.(color.RGBA) }
+func (o *testObj) GetSeed() int64               { return o.seed }
+func (o *testObj) SetSeed(s int64)              { o.seed = s }
+func (o *testObj) GetTextureRes() string        { return o.tex }
+func (o *testObj) SetTextureRes(p string) error { o.tex = p; return nil }
+func (o *testObj) GetParent() object.Object     { return o.parent }
+func (o *testObj) OnAdd(p object.Object)        { o.parent = p }
+func (o *testObj) GetChilds() []object.Object   { return o.childs }
+func (o *testObj) GetMeta() *object.Meta        { return &o.meta }
+
+func (o *testObj) Serialize() map[string]interface{} {
+	return o.props
+}
+
+func (o *testObj) Deserialize(props map[string]interface{}) error {
+	o.props = props
+	return nil
+}
+
+// attach a child, with self being the outer object so parents compare equal
+func addChild(parent object.Object, self *testObj, child object.Object) {
+	self.childs = append(self.childs, child)
+	child.OnAdd(parent)
+}
+
+func init() {
+	Register("TestObj", func(n Node) (*testObj, error) {
+		return &testObj{}, nil
+	})
+	Register("TestLeaf", func(n Node) (*testLeaf, error) {
+		if n.Props["fail"] == true {
+			return nil, fmt.Errorf("leaf failed")
+		}
+		return &testLeaf{}, nil
+	})
+}
+
+func (o *testLeaf) AddChild(obj object.Object) { addChild(o, &o.testObj, obj) }
+func (o *testObj) AddChild(obj object.Object)  { addChild(o, o, obj) }
+
+func TestTypeName(t *testing.T) {
+	var cases = []struct {
+		obj  object.Object
+		name string
+		ok   bool
+	}{
+		{&testObj{}, "TestObj", true},
+		{&testLeaf{}, "TestLeaf", true},
+		{&testRuntime{}, "", false},
+		{nil, "", false},
+	}
+
+	for testIndex, test := range cases {
+		name, ok := TypeName(test.obj)
+		if name != test.name || ok != test.ok {
+			t.Error(fmt.Sprintf("TestTypeName %d: got %s %v", testIndex, name, ok))
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	root := &testObj{scale: raylib.NewVector3(1, 1, 1), color: color.RGBA{1, 2, 3, 4}}
+	child := &testObj{
+		pos:   raylib.NewVector3(1, 2, 3),
+		rot:   raylib.NewVector3(10, 20, 30),
+		scale: raylib.NewVector3(2, 2, 2),
+		color: color.RGBA{255, 0, 0, 255},
+		seed:  42,
+		tex:   "tex/guytex.png",
+		props: map[string]interface{}{"kind": "terrain"},
+	}
+	child.meta.SetName("world")
+	child.meta.AddTag("b")
+	child.meta.AddTag("a")
+	leaf := &testLeaf{}
+	leaf.pos = raylib.NewVector3(-1, 0, 1)
+	root.AddChild(child)
+	root.AddChild(&testRuntime{})
+	child.AddChild(leaf)
+
+	buf := &bytes.Buffer{}
+	err := Save(buf, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := obj.(*testObj)
+	if !ok {
+		t.Fatalf("TestRoundTrip root type %T", obj)
+	}
+	if got.color != root.color || got.scale != root.scale {
+		t.Error("TestRoundTrip root fields")
+	}
+	if len(got.childs) != 1 {
+		t.Fatalf("TestRoundTrip root childs %d", len(got.childs))
+	}
+
+	gotChild := got.childs[0].(*testObj)
+	if gotChild.pos != child.pos || gotChild.rot != child.rot || gotChild.scale != child.scale {
+		t.Error("TestRoundTrip child transform")
+	}
+	if gotChild.color != child.color || gotChild.seed != child.seed || gotChild.tex != child.tex {
+		t.Error("TestRoundTrip child fields")
+	}
+	if gotChild.meta.GetName() != "world" || !slices.Equal(gotChild.meta.GetTags(), []string{"a", "b"}) {
+		t.Error("TestRoundTrip child meta")
+	}
+	if gotChild.props["kind"] != "terrain" {
+		t.Error("TestRoundTrip child props")
+	}
+	if gotChild.GetParent() != object.Object(got) {
+		t.Error("TestRoundTrip child parent")
+	}
+	if len(gotChild.childs) != 1 {
+		t.Fatalf("TestRoundTrip grandchilds %d", len(gotChild.childs))
+	}
+	if gotLeaf, ok := gotChild.childs[0].(*testLeaf); !ok || gotLeaf.pos != leaf.pos {
+		t.Error("TestRoundTrip leaf")
+	}
+
+	//saving the loaded tree must reproduce the same file
+	buf2 := &bytes.Buffer{}
+	err = Save(buf2, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != buf2.String() {
+		t.Error("TestRoundTrip resave differs")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	var cases = []struct {
+		data string
+		want string
+	}{
+		{`{"version": 99, "root": {"type": "TestObj"}}`, "newer"},
+		{`{"version": 0, "root": {"type": "TestObj"}}`, "No migration"},
+		{`{"version": 1, "root": {"type": "Missing"}}`, "Unknown object type"},
+		{`{"version": 1, "root": {"type": "TestObj", "childs": [{"type": "TestLeaf", "props": {"fail": true}}]}}`, "leaf failed"},
+		{`{"version": `, "unexpected EOF"},
+	}
+
+	for testIndex, test := range cases {
+		_, err := Load(strings.NewReader(test.data))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Error(fmt.Sprintf("TestLoadErrors %d: got %v", testIndex, err))
+		}
+	}
+}
+
+func TestMigration(t *testing.T) {
+	RegisterMigration(0, func(f *File) error {
+		f.Root.Type = "TestObj"
+		return nil
+	})
+	defer delete(migrations, 0)
+
+	f, err := Read(strings.NewReader(`{"version": 0, "root": {"type": "OldObj"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Version != Version || f.Root.Type != "TestObj" {
+		t.Error(fmt.Sprintf("TestMigration got %d %s", f.Version, f.Root.Type))
+	}
+}
@@ -0,0 +1,72 @@
+package scene
+
+import (
+	"slices"
+)
+
+// get the registered object type names in order
+func TypeNames() []string {
+	names := []string{}
+	for name := range factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// json schema describing scene and prefab files
+func Schema() map[string]interface{} {
+	vec3 := map[string]interface{}{
+		"type":     "array",
+		"items":    map[string]interface{}{"type": "number"},
+		"minItems": 3,
+		"maxItems": 3,
+	}
+	node := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type":   map[string]interface{}{"enum": TypeNames()},
+			"prefab": map[string]interface{}{"type": "string", "description": "prefab file this node starts from"},
+			"name":   map[string]interface{}{"type": "string"},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+			"pos":   vec3,
+			"rot":   vec3,
+			"scale": vec3,
+			"color": map[string]interface{}{
+				"type":     "array",
+				"items":    map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 255},
+				"minItems": 4,
+				"maxItems": 4,
+			},
+			"texture":  map[string]interface{}{"type": "string"},
+			"collider": map[string]interface{}{"type": "string"},
+			"seed":     map[string]interface{}{"type": "integer"},
+			"props":    map[string]interface{}{"type": "object"},
+			"childs": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"$ref": "#/$defs/node"},
+			},
+		},
+		"anyOf": []interface{}{
+			map[string]interface{}{"required": []string{"type"}},
+			map[string]interface{}{"required": []string{"prefab"}},
+		},
+	}
+
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "scene",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"version": map[string]interface{}{"type": "integer", "maximum": Version},
+			"root":    map[string]interface{}{"$ref": "#/$defs/node"},
+		},
+		"required": []string{"version", "root"},
+		"$defs": map[string]interface{}{
+			"node": node,
+		},
+	}
+}
@@ -0,0 +1,206 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"karalis/pkg/config"
+	"karalis/pkg/event"
+	"karalis/pkg/logging"
+
+	json5 "github.com/zyedidia/json5"
+)
+
+var (
+	mu  sync.RWMutex
+	cur Settings = Defaults()
+)
+
+// user settings kept in settings.json in the config dir
+type Settings struct {
+	Width       int32   `json:"width"`
+	Height      int32   `json:"height"`
+	FPS         int32   `json:"fps"`
+	FOV         float32 `json:"fov"`
+	Sensitivity float32 `json:"mouse_sensitivity"`
+	CellRender  int     `json:"cell_render"`
+	GrassLOD    float64 `json:"grass_lod"`
+	Volume      float32 `json:"volume"`
+	// log levels such as "info,net=debug", see logging.ParseLevels
+	Log string `json:"log"`
+}
+
+// settings were changed, queued on the default event bus
+type Changed struct {
+	Prev Settings
+	Next Settings
+}
+
+// allowed range of a setting
+type bounds struct {
+	name    string
+	integer bool
+	min     float64
+	max     float64
+	get     func(s *Settings) float64
+}
+
+// ranges checked by Validate and published by Schema
+var limits = []bounds{
+	{"width", true, 320, 16384, func(s *Settings) float64 { return float64(s.Width) }},
+	{"height", true, 200, 16384, func(s *Settings) float64 { return float64(s.Height) }},
+	{"fps", true, 0, 1000, func(s *Settings) float64 { return float64(s.FPS) }},
+	{"fov", false, 10, 170, func(s *Settings) float64 { return float64(s.FOV) }},
+	{"mouse_sensitivity", false, 0.01, 10, func(s *Settings) float64 { return float64(s.Sensitivity) }},
+	{"cell_render", true, 0, 8, func(s *Settings) float64 { return float64(s.CellRender) }},
+	{"grass_lod", false, 0, 200, func(s *Settings) float64 { return s.GrassLOD }},
+	{"volume", false, 0, 1, func(s *Settings) float64 { return float64(s.Volume) }},
+}
+
+// settings used when the file is missing or leaves a field out
+func Defaults() Settings {
+	return Settings{
+		Width:       800,
+		Height:      512,
+		FPS:         60,
+		FOV:         45,
+		Sensitivity: 1,
+		CellRender:  1,
+		GrassLOD:    30,
+		Volume:      1,
+		Log:         "info",
+	}
+}
+
+// check every setting is in range
+func (s Settings) Validate() error {
+	for _, b := range limits {
+		v := b.get(&s)
+		if v < b.min || v > b.max {
+			return fmt.Errorf("Invalid %s: %v not in [%v, %v]", b.name, v, b.min, b.max)
+		}
+	}
+	_, err := logging.ParseLevels(s.Log)
+	if err != nil {
+		return fmt.Errorf("Invalid log: %+v", err)
+	}
+	return nil
+}
+
+// get the path of the settings file
+func Path() string {
+	return filepath.Join(config.ConfigDir, "settings.json")
+}
+
+// get the current settings
+func Get() Settings {
+	mu.RLock()
+	defer mu.RUnlock()
+	return cur
+}
+
+// replace the current settings, saving them and notifying subscribers
+func Set(next Settings) error {
+	err := next.Validate()
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	prev := cur
+	cur = next
+	mu.Unlock()
+
+	if prev == next {
+		return nil
+	}
+	event.Queue(Changed{Prev: prev, Next: next})
+	return Save()
+}
+
+// change some settings starting from the current ones
+func Update(fn func(s *Settings)) error {
+	next := Get()
+	fn(&next)
+	return Set(next)
+}
+
+// load the settings file, writing the defaults if there is none
+// fields missing from the file keep their defaults
+func Load() error {
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return Save()
+	}
+	if err != nil {
+		return err
+	}
+
+	next := Defaults()
+	err = json5.Unmarshal(data, &next)
+	if err != nil {
+		return fmt.Errorf("Error reading settings.json: %+v", err)
+	}
+	err = next.Validate()
+	if err != nil {
+		return fmt.Errorf("Error reading settings.json: %+v", err)
+	}
+
+	mu.Lock()
+	prev := cur
+	cur = next
+	mu.Unlock()
+
+	if prev != next {
+		event.Queue(Changed{Prev: prev, Next: next})
+	}
+	return nil
+}
+
+// write the current settings to the settings file
+func Save() error {
+	txt, err := json.MarshalIndent(Get(), "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(Path()), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(), append(txt, '\n'), 0644)
+}
+
+// json schema describing settings.json
+func Schema() map[string]interface{} {
+	defaults := Defaults()
+	props := map[string]interface{}{}
+	for _, b := range limits {
+		kind := "number"
+		if b.integer {
+			kind = "integer"
+		}
+		props[b.name] = map[string]interface{}{
+			"type":    kind,
+			"minimum": b.min,
+			"maximum": b.max,
+			"default": b.get(&defaults),
+		}
+	}
+	props["log"] = map[string]interface{}{
+		"type":    "string",
+		"default": defaults.Log,
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "settings",
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,113 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"karalis/pkg/config"
+	"karalis/pkg/event"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		change func(s *Settings)
+		valid  bool
+	}{
+		{func(s *Settings) {}, true},
+		{func(s *Settings) { s.FOV = 90 }, true},
+		{func(s *Settings) { s.FOV = 5 }, false},
+		{func(s *Settings) { s.Width = 100 }, false},
+		{func(s *Settings) { s.FPS = 0 }, true},
+		{func(s *Settings) { s.Volume = 1.5 }, false},
+		{func(s *Settings) { s.CellRender = -1 }, false},
+		{func(s *Settings) { s.Sensitivity = 0 }, false},
+		{func(s *Settings) { s.Log = "warn,net=debug" }, true},
+		{func(s *Settings) { s.Log = "net=loud" }, false},
+	}
+	for i, test := range tests {
+		s := Defaults()
+		test.change(&s)
+		err := s.Validate()
+		if (err == nil) != test.valid {
+			t.Error(fmt.Sprintf("Unexpected validation for case %d: expected valid %v, got %+v", i, test.valid, err))
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		file   string
+		err    bool
+		expect func(s *Settings)
+	}{
+		{"", false, func(s *Settings) {}},
+		{`{"fov": 70, "cell_render": 2}`, false, func(s *Settings) { s.FOV = 70; s.CellRender = 2 }},
+		{`{// comments are allowed
+			"volume": 0.5}`, false, func(s *Settings) { s.Volume = 0.5 }},
+		{`{"fov": 500}`, true, func(s *Settings) {}},
+		{`{"log": "debug,world=warn"}`, false, func(s *Settings) { s.Log = "debug,world=warn" }},
+		{`{"fov": `, true, func(s *Settings) {}},
+	}
+	for i, test := range tests {
+		config.ConfigDir = t.TempDir()
+		cur = Defaults()
+		if test.file != "" {
+			err := os.WriteFile(Path(), []byte(test.file), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := Load()
+		if (err != nil) != test.err {
+			t.Error(fmt.Sprintf("Unexpected load error for case %d: %+v", i, err))
+		}
+		expect := Defaults()
+		test.expect(&expect)
+		if Get() != expect {
+			t.Error(fmt.Sprintf("Unexpected settings for case %d: expected %+v, got %+v", i, expect, Get()))
+		}
+		if _, err := os.Stat(Path()); err != nil {
+			t.Error(fmt.Sprintf("Settings file missing for case %d: %+v", i, err))
+		}
+	}
+	event.Default.Flush()
+}
+
+func TestSetNotifies(t *testing.T) {
+	config.ConfigDir = t.TempDir()
+	cur = Defaults()
+
+	changes := []Changed{}
+	sub := event.On(func(c Changed) {
+		changes = append(changes, c)
+	})
+	defer sub.Cancel()
+
+	err := Update(func(s *Settings) { s.FOV = 60 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Update(func(s *Settings) { s.FOV = 60 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Update(func(s *Settings) { s.FOV = 1 })
+	if err == nil || Get().FOV != 60 {
+		t.Error(fmt.Sprintf("Invalid setting was applied: %v", Get().FOV))
+	}
+	event.Default.Flush()
+
+	if len(changes) != 1 || changes[0].Prev.FOV != 45 || changes[0].Next.FOV != 60 {
+		t.Error(fmt.Sprintf("Unexpected change notifications: %+v", changes))
+	}
+
+	//the saved file loads back to the same settings
+	cur = Defaults()
+	err = Load()
+	if err != nil || Get().FOV != 60 {
+		t.Error(fmt.Sprintf("Saved settings did not load back: %v, %+v", Get().FOV, err))
+	}
+	event.Default.Flush()
+}
@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"karalis/pkg/console"
+	"karalis/pkg/logging"
+)
+
+// console variables for the settings, setting one saves and applies it like any other change
+func init() {
+	console.RegisterVar(console.IntVar("r_cellrender", "cells drawn around the player",
+		func() int { return Get().CellRender },
+		func(v int) error { return Update(func(s *Settings) { s.CellRender = v }) }))
+	console.RegisterVar(console.FloatVar("r_grasslod", "grass blades per unit of cell radius",
+		func() float64 { return Get().GrassLOD },
+		func(v float64) error { return Update(func(s *Settings) { s.GrassLOD = v }) }))
+	console.RegisterVar(console.FloatVar("r_fov", "vertical field of view in degrees",
+		func() float64 { return float64(Get().FOV) },
+		func(v float64) error { return Update(func(s *Settings) { s.FOV = float32(v) }) }))
+	console.RegisterVar(console.IntVar("r_fps", "target frames per second, 0 for no limit",
+		func() int { return int(Get().FPS) },
+		func(v int) error { return Update(func(s *Settings) { s.FPS = int32(v) }) }))
+	console.RegisterVar(console.FloatVar("m_sensitivity", "mouse look sensitivity",
+		func() float64 { return float64(Get().Sensitivity) },
+		func(v float64) error { return Update(func(s *Settings) { s.Sensitivity = float32(v) }) }))
+	console.RegisterVar(console.FloatVar("s_volume", "master volume from 0 to 1",
+		func() float64 { return float64(Get().Volume) },
+		func(v float64) error { return Update(func(s *Settings) { s.Volume = float32(v) }) }))
+	console.RegisterVar(console.StringVar("log", "log levels, a default then subsystem overrides such as info,net=debug",
+		func() string { return Get().Log },
+		func(v string) error { return Update(func(s *Settings) { s.Log = v }) }))
+	console.Register(console.Command{
+		Name: "log_level",
+		Args: "<subsystem> [level]",
+		Help: "show or set the level a subsystem logs at",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return fmt.Errorf("Usage: log_level <subsystem> [level]")
+			}
+			if !slices.Contains(logging.Subsystems, args[0]) {
+				return fmt.Errorf("Unknown subsystem: %s", args[0])
+			}
+			levels, err := logging.ParseLevels(Get().Log)
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				c.Printf("%s %s\n", args[0], logging.LevelName(levels.Get(args[0])))
+				return nil
+			}
+			level, err := logging.ParseLevel(args[1])
+			if err != nil {
+				return err
+			}
+			return Update(func(s *Settings) { s.Log = levels.With(args[0], level).String() })
+		},
+		Complete: func(args []string, prefix string) []string {
+			names := logging.Subsystems
+			if len(args) == 1 {
+				names = []string{"debug", "info", "warn", "error"}
+			} else if len(args) > 1 {
+				return []string{}
+			}
+			matches := []string{}
+			for _, name := range names {
+				if strings.HasPrefix(name, prefix) {
+					matches = append(matches, name)
+				}
+			}
+			return matches
+		},
+	})
+}
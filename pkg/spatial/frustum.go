@@ -0,0 +1,77 @@
+package spatial
+
+import (
+	pub_object "karalis/pkg/object"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+// view volume as six inward facing planes: left, right, bottom, top, near, far
+type Frustum [6]pub_object.Plane
+
+// extract the frustum planes from a combined view projection matrix
+func NewFrustum(viewProj raylib.Matrix) Frustum {
+	m := viewProj
+	rows := [4][4]float32{
+		{m.M0, m.M4, m.M8, m.M12},
+		{m.M1, m.M5, m.M9, m.M13},
+		{m.M2, m.M6, m.M10, m.M14},
+		{m.M3, m.M7, m.M11, m.M15},
+	}
+
+	f := Frustum{}
+	for i := range 3 {
+		for j, sign := range [2]float32{1, -1} {
+			p := pub_object.Plane{
+				Normal: raylib.NewVector3(rows[3][0]+sign*rows[i][0], rows[3][1]+sign*rows[i][1], rows[3][2]+sign*rows[i][2]),
+				Offset: rows[3][3] + sign*rows[i][3],
+			}
+			l := raylib.Vector3Length(p.Normal)
+			if l > 0 {
+				p.Normal = raylib.Vector3Scale(p.Normal, 1/l)
+				p.Offset /= l
+			}
+			f[i*2+j] = p
+		}
+	}
+	return f
+}
+
+// check if a box is at least partially inside the frustum
+func (f *Frustum) ContainsBox(box raylib.BoundingBox) bool {
+	if f == nil {
+		return false
+	}
+
+	for _, p := range f {
+		//test the corner furthest along the plane normal
+		v := box.Min
+		if p.Normal.X >= 0 {
+			v.X = box.Max.X
+		}
+		if p.Normal.Y >= 0 {
+			v.Y = box.Max.Y
+		}
+		if p.Normal.Z >= 0 {
+			v.Z = box.Max.Z
+		}
+		if raylib.Vector3DotProduct(p.Normal, v)+p.Offset < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// check if a sphere is at least partially inside the frustum
+func (f *Frustum) ContainsSphere(sp pub_object.Sphere) bool {
+	if f == nil {
+		return false
+	}
+
+	for _, p := range f {
+		if raylib.Vector3DotProduct(p.Normal, sp.Center)+p.Offset < -sp.Radius {
+			return false
+		}
+	}
+	return true
+}
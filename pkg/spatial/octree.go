@@ -0,0 +1,390 @@
+package spatial
+
+import (
+	"container/heap"
+
+	pub_object "karalis/pkg/object"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	// limit on how often the root may double to fit a far away item
+	MaxGrow = 32
+)
+
+// loose octree indexing items by their bounding boxes
+// each node's loose bounds are twice its tight bounds so items never straddle siblings
+type Octree[T comparable] struct {
+	root    *octNode[T]
+	minHalf float32
+	items   map[T]*octItem[T]
+}
+
+type octNode[T comparable] struct {
+	center raylib.Vector3
+	half   float32
+	parent *octNode[T]
+	childs [8]*octNode[T]
+	items  []*octItem[T]
+	count  int
+}
+
+type octItem[T comparable] struct {
+	val   T
+	box   raylib.BoundingBox
+	node  *octNode[T]
+	index int
+}
+
+// create an octree around center, nodes are never split below minHalf
+func NewOctree[T comparable](center raylib.Vector3, half float32, minHalf float32) *Octree[T] {
+	return &Octree[T]{
+		root:    &octNode[T]{center: center, half: half},
+		minHalf: minHalf,
+		items:   map[T]*octItem[T]{},
+	}
+}
+
+func (o *Octree[T]) Len() int {
+	if o == nil {
+		return 0
+	}
+
+	return len(o.items)
+}
+
+func (o *Octree[T]) Has(val T) bool {
+	if o == nil {
+		return false
+	}
+
+	_, ok := o.items[val]
+	return ok
+}
+
+// get the box an item was last indexed with
+func (o *Octree[T]) GetBox(val T) (raylib.BoundingBox, bool) {
+	if o == nil {
+		return raylib.BoundingBox{}, false
+	}
+
+	it, ok := o.items[val]
+	if !ok {
+		return raylib.BoundingBox{}, false
+	}
+	return it.box, true
+}
+
+// add an item or move it to its new bounds
+func (o *Octree[T]) Insert(val T, box raylib.BoundingBox) {
+	if o == nil {
+		return
+	}
+
+	it, ok := o.items[val]
+	if ok {
+		//stay put while the box still fits and cannot sink any deeper
+		if looseContains(it.node, box) && o.childFor(it.node, box) < 0 {
+			it.box = box
+			return
+		}
+		it.node.remove(it)
+	} else {
+		it = &octItem[T]{val: val}
+		o.items[val] = it
+	}
+	it.box = box
+
+	for i := 0; i < MaxGrow && !looseContains(o.root, box); i++ {
+		o.grow(boxCenter(box))
+	}
+
+	node := o.root
+	for {
+		index := o.childFor(node, box)
+		if index < 0 {
+			break
+		}
+		if node.childs[index] == nil {
+			node.childs[index] = node.newChild(index)
+		}
+		node = node.childs[index]
+	}
+	node.add(it)
+}
+
+func (o *Octree[T]) Remove(val T) {
+	if o == nil {
+		return
+	}
+
+	it, ok := o.items[val]
+	if !ok {
+		return
+	}
+	it.node.remove(it)
+	delete(o.items, val)
+}
+
+// visit all items whose boxes overlap box until fn returns false
+func (o *Octree[T]) EachInBox(box raylib.BoundingBox, fn func(T) bool) bool {
+	if o == nil {
+		return true
+	}
+
+	return o.root.each(func(b raylib.BoundingBox) bool { return overlaps(b, box) }, fn)
+}
+
+// visit all items whose boxes touch the sphere until fn returns false
+func (o *Octree[T]) EachInSphere(sp pub_object.Sphere, fn func(T) bool) bool {
+	if o == nil {
+		return true
+	}
+
+	r2 := sp.Radius * sp.Radius
+	return o.root.each(func(b raylib.BoundingBox) bool { return distSqr(b, sp.Center) <= r2 }, fn)
+}
+
+// visit all items whose boxes are at least partially inside the frustum until fn returns false
+func (o *Octree[T]) EachInFrustum(f Frustum, fn func(T) bool) bool {
+	if o == nil {
+		return true
+	}
+
+	return o.root.each(f.ContainsBox, fn)
+}
+
+func (o *Octree[T]) QueryBox(box raylib.BoundingBox) []T {
+	found := []T{}
+	o.EachInBox(box, func(val T) bool {
+		found = append(found, val)
+		return true
+	})
+	return found
+}
+
+func (o *Octree[T]) QuerySphere(sp pub_object.Sphere) []T {
+	found := []T{}
+	o.EachInSphere(sp, func(val T) bool {
+		found = append(found, val)
+		return true
+	})
+	return found
+}
+
+func (o *Octree[T]) QueryFrustum(f Frustum) []T {
+	found := []T{}
+	o.EachInFrustum(f, func(val T) bool {
+		found = append(found, val)
+		return true
+	})
+	return found
+}
+
+// get up to k items closest to pos, nearest first, measured to their boxes
+func (o *Octree[T]) Nearest(pos raylib.Vector3, k int) []T {
+	found := []T{}
+	if o == nil || k <= 0 {
+		return found
+	}
+
+	//best first search over nodes and items ordered by distance
+	q := &octQueue[T]{{dist: distSqr(o.root.looseBox(), pos), node: o.root}}
+	for q.Len() > 0 && len(found) < k {
+		e := heap.Pop(q).(octEntry[T])
+		if e.item != nil {
+			found = append(found, e.item.val)
+			continue
+		}
+		for _, it := range e.node.items {
+			heap.Push(q, octEntry[T]{dist: distSqr(it.box, pos), item: it})
+		}
+		for _, child := range e.node.childs {
+			if child != nil && child.count > 0 {
+				heap.Push(q, octEntry[T]{dist: distSqr(child.looseBox(), pos), node: child})
+			}
+		}
+	}
+	return found
+}
+
+// double the root towards pos keeping the old root as one of its octants
+func (o *Octree[T]) grow(pos raylib.Vector3) {
+	old := o.root
+	dir := raylib.NewVector3(1, 1, 1)
+	if pos.X < old.center.X {
+		dir.X = -1
+	}
+	if pos.Y < old.center.Y {
+		dir.Y = -1
+	}
+	if pos.Z < old.center.Z {
+		dir.Z = -1
+	}
+
+	root := &octNode[T]{
+		center: raylib.Vector3Add(old.center, raylib.Vector3Scale(dir, old.half)),
+		half:   old.half * 2,
+		count:  old.count,
+	}
+	root.childs[root.octant(old.center)] = old
+	old.parent = root
+	o.root = root
+}
+
+// get the octant of node that fully holds box, or -1 if it must stay in node
+func (o *Octree[T]) childFor(node *octNode[T], box raylib.BoundingBox) int {
+	if node.half/2 < o.minHalf {
+		return -1
+	}
+
+	index := node.octant(boxCenter(box))
+	center, half := node.childBounds(index)
+	loose := half * 2
+	if box.Min.X < center.X-loose || box.Max.X > center.X+loose ||
+		box.Min.Y < center.Y-loose || box.Max.Y > center.Y+loose ||
+		box.Min.Z < center.Z-loose || box.Max.Z > center.Z+loose {
+		return -1
+	}
+	return index
+}
+
+func (n *octNode[T]) octant(pos raylib.Vector3) int {
+	index := 0
+	if pos.X >= n.center.X {
+		index |= 1
+	}
+	if pos.Y >= n.center.Y {
+		index |= 2
+	}
+	if pos.Z >= n.center.Z {
+		index |= 4
+	}
+	return index
+}
+
+func (n *octNode[T]) childBounds(index int) (raylib.Vector3, float32) {
+	half := n.half / 2
+	center := n.center
+	if index&1 != 0 {
+		center.X += half
+	} else {
+		center.X -= half
+	}
+	if index&2 != 0 {
+		center.Y += half
+	} else {
+		center.Y -= half
+	}
+	if index&4 != 0 {
+		center.Z += half
+	} else {
+		center.Z -= half
+	}
+	return center, half
+}
+
+func (n *octNode[T]) newChild(index int) *octNode[T] {
+	center, half := n.childBounds(index)
+	return &octNode[T]{center: center, half: half, parent: n}
+}
+
+func (n *octNode[T]) looseBox() raylib.BoundingBox {
+	loose := raylib.NewVector3(n.half*2, n.half*2, n.half*2)
+	return raylib.NewBoundingBox(raylib.Vector3Subtract(n.center, loose), raylib.Vector3Add(n.center, loose))
+}
+
+func (n *octNode[T]) add(it *octItem[T]) {
+	it.node = n
+	it.index = len(n.items)
+	n.items = append(n.items, it)
+	for node := n; node != nil; node = node.parent {
+		node.count++
+	}
+}
+
+func (n *octNode[T]) remove(it *octItem[T]) {
+	last := n.items[len(n.items)-1]
+	n.items[it.index] = last
+	last.index = it.index
+	n.items[len(n.items)-1] = nil
+	n.items = n.items[:len(n.items)-1]
+	it.node = nil
+
+	//drop branches left empty
+	for node := n; node != nil; node = node.parent {
+		node.count--
+		if node.count == 0 && node.parent != nil {
+			node.parent.childs[node.parent.octant(node.center)] = nil
+		}
+	}
+}
+
+func (n *octNode[T]) each(test func(raylib.BoundingBox) bool, fn func(T) bool) bool {
+	if n.count == 0 || !test(n.looseBox()) {
+		return true
+	}
+
+	for _, it := range n.items {
+		if test(it.box) && !fn(it.val) {
+			return false
+		}
+	}
+	for _, child := range n.childs {
+		if child != nil && !child.each(test, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func looseContains[T comparable](n *octNode[T], box raylib.BoundingBox) bool {
+	loose := n.looseBox()
+	return box.Min.X >= loose.Min.X && box.Max.X <= loose.Max.X &&
+		box.Min.Y >= loose.Min.Y && box.Max.Y <= loose.Max.Y &&
+		box.Min.Z >= loose.Min.Z && box.Max.Z <= loose.Max.Z
+}
+
+func boxCenter(box raylib.BoundingBox) raylib.Vector3 {
+	return raylib.Vector3Scale(raylib.Vector3Add(box.Min, box.Max), 0.5)
+}
+
+// inclusive overlap so touching and flat boxes are still found
+func overlaps(a, b raylib.BoundingBox) bool {
+	return a.Min.X <= b.Max.X && a.Max.X >= b.Min.X &&
+		a.Min.Y <= b.Max.Y && a.Max.Y >= b.Min.Y &&
+		a.Min.Z <= b.Max.Z && a.Max.Z >= b.Min.Z
+}
+
+// squared distance from pos to the closest point of box
+func distSqr(box raylib.BoundingBox, pos raylib.Vector3) float32 {
+	closest := raylib.Vector3Clamp(pos, box.Min, box.Max)
+	d := raylib.Vector3Subtract(pos, closest)
+	return raylib.Vector3DotProduct(d, d)
+}
+
+type octEntry[T comparable] struct {
+	dist float32
+	node *octNode[T]
+	item *octItem[T]
+}
+
+type octQueue[T comparable] []octEntry[T]
+
+func (q octQueue[T]) Len() int { return len(q) }
+func (q octQueue[T]) Less(i, j int) bool {
+	//items before nodes at equal distance so ties resolve without expanding more nodes
+	if q[i].dist == q[j].dist {
+		return q[i].item != nil && q[j].item == nil
+	}
+	return q[i].dist < q[j].dist
+}
+func (q octQueue[T]) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *octQueue[T]) Push(x interface{}) { *q = append(*q, x.(octEntry[T])) }
+func (q *octQueue[T]) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,146 @@
+package spatial
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	pub_object "karalis/pkg/object"
+
+	raylib "github.com/gen2brain/raylib-go/raylib"
+)
+
+func randBox(r *rand.Rand, spread float32) raylib.BoundingBox {
+	pos := raylib.NewVector3((r.Float32()-0.5)*spread, (r.Float32()-0.5)*spread, (r.Float32()-0.5)*spread)
+	size := raylib.NewVector3(r.Float32()*4, r.Float32()*4, r.Float32()*4)
+	return raylib.NewBoundingBox(pos, raylib.Vector3Add(pos, size))
+}
+
+func sorted(vals []int) []int {
+	slices.Sort(vals)
+	return vals
+}
+
+func TestOctreeQueries(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	o := NewOctree[int](raylib.Vector3{}, 16, 1)
+	boxes := map[int]raylib.BoundingBox{}
+
+	//insert, move far outside the root and remove to exercise growth and pruning
+	for i := range 500 {
+		boxes[i] = randBox(r, 100)
+		o.Insert(i, boxes[i])
+	}
+	for i := range 200 {
+		boxes[i] = randBox(r, 400)
+		o.Insert(i, boxes[i])
+	}
+	for i := 400; i < 500; i++ {
+		delete(boxes, i)
+		o.Remove(i)
+	}
+	if o.Len() != len(boxes) {
+		t.Fatal(fmt.Sprintf("TestOctreeQueries len %d want %d", o.Len(), len(boxes)))
+	}
+
+	for testIndex := range 50 {
+		query := randBox(r, 200)
+		query.Max = raylib.Vector3Add(query.Max, raylib.NewVector3(20, 20, 20))
+		want := []int{}
+		for i, box := range boxes {
+			if overlaps(box, query) {
+				want = append(want, i)
+			}
+		}
+		if got := sorted(o.QueryBox(query)); !slices.Equal(got, sorted(want)) {
+			t.Error(fmt.Sprintf("TestOctreeQueries box %d: got %d want %d", testIndex, len(got), len(want)))
+		}
+
+		sp := pub_object.Sphere{Center: boxCenter(query), Radius: r.Float32() * 40}
+		want = []int{}
+		for i, box := range boxes {
+			if distSqr(box, sp.Center) <= sp.Radius*sp.Radius {
+				want = append(want, i)
+			}
+		}
+		if got := sorted(o.QuerySphere(sp)); !slices.Equal(got, sorted(want)) {
+			t.Error(fmt.Sprintf("TestOctreeQueries sphere %d: got %d want %d", testIndex, len(got), len(want)))
+		}
+
+		k := 1 + r.Intn(10)
+		got := o.Nearest(sp.Center, k)
+		dists := []float32{}
+		for _, box := range boxes {
+			dists = append(dists, distSqr(box, sp.Center))
+		}
+		slices.Sort(dists)
+		if len(got) != k {
+			t.Error(fmt.Sprintf("TestOctreeQueries nearest %d: got %d want %d", testIndex, len(got), k))
+			continue
+		}
+		for i, val := range got {
+			if distSqr(boxes[val], sp.Center) != dists[i] {
+				t.Error(fmt.Sprintf("TestOctreeQueries nearest %d: rank %d out of order", testIndex, i))
+			}
+		}
+	}
+}
+
+func TestOctreeMove(t *testing.T) {
+	o := NewOctree[string](raylib.Vector3{}, 8, 1)
+	o.Insert("a", raylib.NewBoundingBox(raylib.NewVector3(1, 1, 1), raylib.NewVector3(2, 2, 2)))
+	o.Insert("a", raylib.NewBoundingBox(raylib.NewVector3(-50, 0, 0), raylib.NewVector3(-49, 1, 1)))
+
+	if o.Len() != 1 {
+		t.Error(fmt.Sprintf("TestOctreeMove len %d", o.Len()))
+	}
+	if got := o.QueryBox(raylib.NewBoundingBox(raylib.NewVector3(0, 0, 0), raylib.NewVector3(3, 3, 3))); len(got) != 0 {
+		t.Error("TestOctreeMove stale position found")
+	}
+	if got := o.QueryBox(raylib.NewBoundingBox(raylib.NewVector3(-51, 0, 0), raylib.NewVector3(-48, 1, 1))); len(got) != 1 {
+		t.Error("TestOctreeMove new position missing")
+	}
+
+	o.Remove("a")
+	o.Remove("missing")
+	if o.Len() != 0 || o.root.count != 0 {
+		t.Error("TestOctreeMove remove")
+	}
+}
+
+func TestFrustum(t *testing.T) {
+	view := raylib.MatrixLookAt(raylib.NewVector3(0, 0, 0), raylib.NewVector3(0, 0, -1), raylib.NewVector3(0, 1, 0))
+	//90 degree perspective with near 0.1 and far 100
+	proj := raylib.Matrix{M0: 1, M5: 1, M10: -100.1 / 99.9, M11: -1, M14: -20 / 99.9}
+	f := NewFrustum(raylib.MatrixMultiply(view, proj))
+
+	unit := func(x, y, z float32) raylib.BoundingBox {
+		return raylib.NewBoundingBox(raylib.NewVector3(x-0.5, y-0.5, z-0.5), raylib.NewVector3(x+0.5, y+0.5, z+0.5))
+	}
+	var cases = []struct {
+		box  raylib.BoundingBox
+		want bool
+	}{
+		{unit(0, 0, -10), true},
+		{unit(0, 0, 10), false},
+		{unit(0, 0, -200), false},
+		{unit(30, 0, -10), false},
+		{unit(0, -30, -10), false},
+		{unit(9, 0, -10), true},
+	}
+
+	for testIndex, test := range cases {
+		if got := f.ContainsBox(test.box); got != test.want {
+			t.Error(fmt.Sprintf("TestFrustum %d: got %v want %v", testIndex, got, test.want))
+		}
+	}
+
+	o := NewOctree[int](raylib.Vector3{}, 16, 1)
+	for i, test := range cases {
+		o.Insert(i, test.box)
+	}
+	if got := sorted(o.QueryFrustum(f)); !slices.Equal(got, []int{0, 5}) {
+		t.Error(fmt.Sprintf("TestFrustum octree got %v", got))
+	}
+}
@@ -9,7 +9,7 @@ var ()
 type Stage interface {
 	Init() error
 	OnResize(w int32, h int32)
-	Render()
+	Render(alpha float32)
 	Update(dt float32)
 	OnInput(dt float32)
 	OnAdd()
@@ -17,3 +17,26 @@ type Stage interface {
 	GetPlayer() object.Object
 	GetCurrentScene() object.Object
 }
+
+// stages pushed over others such as a pause menu
+// stages below an overlay keep rendering but stop receiving input
+type Overlay interface {
+	// stop the stages below from updating while this one is on top
+	PausesBelow() bool
+}
+
+// effect drawn while the stage stack changes
+type Transition interface {
+	// length of the effect in seconds
+	GetDuration() float32
+	// draw the effect at t from 0 to 1, from and to draw the stacks before and after the change
+	Render(t float32, from func(), to func())
+	// called once the effect is done to release what it holds
+	OnEnd()
+}
+
+// stages reporting how far their Init has come, shown while loading them
+type Progress interface {
+	// progress from 0 to 1
+	GetProgress() float32
+}
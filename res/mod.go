@@ -0,0 +1,495 @@
+package res
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"karalis/pkg/config"
+	"karalis/pkg/console"
+
+	json5 "github.com/zyedidia/json5"
+)
+
+const (
+	// folder mod archives are loaded from
+	ModDir = "lib"
+	// manifest at the root of every mod archive
+	ManifestName = "mod.json"
+)
+
+var (
+	// mods found at startup in load order, then the ones that did not load
+	mods = []*Mod{}
+	// mod each modded resource came from, resources missing here are the base ones
+	owners    = map[string]string{}
+	conflicts = []Conflict{}
+)
+
+// console commands for the mods
+func init() {
+	console.Register(console.Command{
+		Name: "mods",
+		Help: "list the mods in load order and the resources they conflict on",
+		Run: func(c *console.Console, args []string) error {
+			for _, m := range GetMods() {
+				state := "loaded"
+				if !m.Enabled {
+					state = "disabled"
+				} else if !m.Loaded {
+					state = fmt.Sprintf("not loaded: %v", m.Err)
+				}
+				c.Printf("  %s %s %s\n", m.ID, m.Version, state)
+			}
+			for _, conflict := range GetConflicts() {
+				c.Printf("  conflict %s\n", conflict)
+			}
+			return nil
+		},
+	})
+	console.Register(console.Command{
+		Name: "mod_enable",
+		Args: "<id> <0|1>",
+		Help: "enable or disable a mod in mods.json, applied on the next start",
+		Run: func(c *console.Console, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("Usage: mod_enable <id> <0|1>")
+			}
+			on, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return fmt.Errorf("Invalid value: %s", args[1])
+			}
+			enabled, err := LoadModConfig(ModsPath())
+			if err != nil {
+				return err
+			}
+			if _, ok := enabled[args[0]]; !ok {
+				return fmt.Errorf("Unknown mod: %s", args[0])
+			}
+			enabled[args[0]] = on
+			return SaveModConfig(ModsPath(), enabled)
+		},
+		Complete: func(args []string, prefix string) []string {
+			matches := []string{}
+			if len(args) > 0 {
+				return matches
+			}
+			for _, m := range GetMods() {
+				if strings.HasPrefix(m.ID, prefix) {
+					matches = append(matches, m.ID)
+				}
+			}
+			return matches
+		},
+	})
+}
+
+// describes a mod, read from mod.json in its archive
+type Manifest struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version"`
+	// ids of the mods this one needs mapped to the lowest version it works with
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+	// mods needing nothing from each other load lowest priority first, so higher ones win overrides
+	Priority int `json:"priority,omitempty"`
+	// resources the mod replaces, adding a resource that exists without listing it is a conflict
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// mod archive and whether it loaded
+type Mod struct {
+	Manifest
+	Path    string
+	Enabled bool
+	Loaded  bool
+	// why an enabled mod did not load
+	Err error
+
+	files map[string]resource
+}
+
+// resource two sources both provide
+type Conflict struct {
+	Path string
+	// mod providing the resource again
+	Mod string
+	// mod the resource came from before, empty for the base resources
+	Prev string
+	// whether the mods copy replaced the previous one
+	Replaced bool
+}
+
+func (c Conflict) String() string {
+	prev := c.Prev
+	if prev == "" {
+		prev = "base"
+	}
+	if c.Replaced {
+		return fmt.Sprintf("%s: %s overrides %s without depending on it", c.Path, c.Mod, prev)
+	}
+	return fmt.Sprintf("%s: %s does not list it in overrides, keeping the one from %s", c.Path, c.Mod, prev)
+}
+
+// check the manifest has what load ordering needs
+func (m Manifest) Validate() error {
+	if m.ID == "" || strings.ContainsAny(m.ID, " \t/\\@") {
+		return fmt.Errorf("Invalid mod id: %q", m.ID)
+	}
+	_, err := ParseVersion(m.Version)
+	if err != nil {
+		return err
+	}
+	for id, min := range m.Dependencies {
+		if id == m.ID {
+			return fmt.Errorf("Mod %s depends on itself", m.ID)
+		}
+		_, err := ParseVersion(min)
+		if err != nil {
+			return fmt.Errorf("Invalid version for dependency %s: %+v", id, err)
+		}
+	}
+	return nil
+}
+
+// parse a dotted version such as 1.2.0, missing parts count as 0
+func ParseVersion(v string) ([]int, error) {
+	if v == "" {
+		return nil, fmt.Errorf("Invalid version: %q", v)
+	}
+	parts := strings.Split(v, ".")
+	out := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("Invalid version: %q", v)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// compare two versions, -1 when a is older, 1 when it is newer
+// callers have validated both
+func CompareVersions(a string, b string) int {
+	va, _ := ParseVersion(a)
+	vb, _ := ParseVersion(b)
+	for i := range max(len(va), len(vb)) {
+		x, y := 0, 0
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// read a mod archive and its manifest
+func ReadMod(path string) (*Mod, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".zip") {
+		return nil, fmt.Errorf("Archive not supported: %s", path)
+	}
+	files, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, ok := files[ManifestName]
+	if !ok || manifest.err != nil {
+		return nil, fmt.Errorf("Mod has no %s: %s", ManifestName, path)
+	}
+	delete(files, ManifestName)
+	mod := &Mod{Path: path, Enabled: true, files: files}
+	err = json5.Unmarshal(manifest.data.([]byte), &mod.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s of %s: %+v", ManifestName, path, err)
+	}
+	err = mod.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s of %s: %+v", ManifestName, path, err)
+	}
+	return mod, nil
+}
+
+// get the load order of the enabled mods, dependencies first
+// mods whose dependencies are missing, disabled, too old or circular get an Err and are left out
+func SortMods(list []*Mod) []*Mod {
+	byID := map[string]*Mod{}
+	for _, m := range list {
+		if prev, ok := byID[m.ID]; ok {
+			m.Err = fmt.Errorf("Duplicate of mod %s in %s", m.ID, prev.Path)
+			continue
+		}
+		byID[m.ID] = m
+	}
+	usable := func(m *Mod) bool {
+		return m.Enabled && m.Err == nil
+	}
+
+	//a mod missing a dependency takes the mods depending on it down too
+	for changed := true; changed; {
+		changed = false
+		for _, m := range list {
+			if !usable(m) {
+				continue
+			}
+			for _, id := range sortedKeys(m.Dependencies) {
+				dep, ok := byID[id]
+				switch {
+				case !ok:
+					m.Err = fmt.Errorf("Missing dependency %s", id)
+				case !dep.Enabled:
+					m.Err = fmt.Errorf("Dependency %s is disabled", id)
+				case dep.Err != nil:
+					m.Err = fmt.Errorf("Dependency %s did not load", id)
+				case CompareVersions(dep.Version, m.Dependencies[id]) < 0:
+					m.Err = fmt.Errorf("Dependency %s is %s, needs %s", id, dep.Version, m.Dependencies[id])
+				}
+				if m.Err != nil {
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	//repeatedly take the ready mod with the lowest priority
+	order := []*Mod{}
+	placed := map[string]bool{}
+	pending := []*Mod{}
+	for _, m := range list {
+		if usable(m) {
+			pending = append(pending, m)
+		}
+	}
+	for len(pending) > 0 {
+		ready := []*Mod{}
+		for _, m := range pending {
+			ok := true
+			for id := range m.Dependencies {
+				ok = ok && placed[id]
+			}
+			if ok {
+				ready = append(ready, m)
+			}
+		}
+		if len(ready) == 0 {
+			for _, m := range pending {
+				m.Err = fmt.Errorf("Dependency cycle")
+			}
+			break
+		}
+		sort.Slice(ready, func(i, j int) bool {
+			if ready[i].Priority != ready[j].Priority {
+				return ready[i].Priority < ready[j].Priority
+			}
+			return ready[i].ID < ready[j].ID
+		})
+		next := ready[0]
+		order = append(order, next)
+		placed[next.ID] = true
+		pending = slices.DeleteFunc(pending, func(m *Mod) bool { return m == next })
+	}
+	return order
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// add the resources of mods in load order and get the conflicts found
+// a resource that already exists is only replaced when the mod lists it in its overrides
+func Mount(order []*Mod) []Conflict {
+	found := []Conflict{}
+	deps := map[string]map[string]bool{}
+	for _, m := range order {
+		//everything a mod depends on, directly or not, loaded before it
+		deps[m.ID] = map[string]bool{}
+		for id := range m.Dependencies {
+			deps[m.ID][id] = true
+			for sub := range deps[id] {
+				deps[m.ID][sub] = true
+			}
+		}
+
+		for _, path := range sortedResources(m.files) {
+			_, exists := getRes(path)
+			mu.RLock()
+			prev := owners[path]
+			mu.RUnlock()
+			if exists && !slices.Contains(m.Overrides, path) {
+				found = append(found, Conflict{Path: path, Mod: m.ID, Prev: prev})
+				continue
+			}
+			if exists && prev != "" && !deps[m.ID][prev] {
+				found = append(found, Conflict{Path: path, Mod: m.ID, Prev: prev, Replaced: true})
+			}
+			setRes(path, m.files[path])
+			mu.Lock()
+			owners[path] = m.ID
+			mu.Unlock()
+		}
+		m.Loaded = true
+		m.files = nil
+	}
+	return found
+}
+
+func sortedResources(files map[string]resource) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		//folders are entries of their own in zips
+		if !strings.HasSuffix(path, "/") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// get the path of the file enabling and disabling mods
+func ModsPath() string {
+	return filepath.Join(config.ConfigDir, "mods.json")
+}
+
+// read which mods are enabled by id, an empty map when there is no file
+func LoadModConfig(path string) (map[string]bool, error) {
+	enabled := map[string]bool{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return enabled, nil
+	}
+	if err != nil {
+		return enabled, err
+	}
+	err = json5.Unmarshal(data, &enabled)
+	if err != nil {
+		return map[string]bool{}, fmt.Errorf("Error reading mods.json: %+v", err)
+	}
+	return enabled, nil
+}
+
+// write which mods are enabled by id
+func SaveModConfig(path string, enabled map[string]bool) error {
+	txt, err := json.MarshalIndent(enabled, "", "    ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(txt, '\n'), 0644)
+}
+
+// load the mods in dir enabled by the config file, new mods are added to it enabled
+func LoadMods(dir string, cfgPath string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	enabled, err := LoadModConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	found := []*Mod{}
+	added := false
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		m, err := ReadMod(path)
+		if err != nil {
+			logger.Error("read mod", "path", path, "err", err)
+			continue
+		}
+		on, ok := enabled[m.ID]
+		if !ok {
+			enabled[m.ID] = true
+			on = true
+			added = true
+		}
+		m.Enabled = on
+		found = append(found, m)
+	}
+	if added {
+		err = SaveModConfig(cfgPath, enabled)
+		if err != nil {
+			logger.Error("save mods.json", "err", err)
+		}
+	}
+
+	order := SortMods(found)
+	found = slices.DeleteFunc(found, func(m *Mod) bool { return slices.Contains(order, m) })
+	list := append(order, found...)
+	mounted := Mount(order)
+
+	for _, m := range list {
+		switch {
+		case m.Loaded:
+			logger.Info("mod loaded", "id", m.ID, "version", m.Version, "path", m.Path)
+		case !m.Enabled:
+			logger.Info("mod disabled", "id", m.ID)
+		default:
+			logger.Error("mod not loaded", "id", m.ID, "err", m.Err)
+		}
+	}
+	for _, c := range mounted {
+		logger.Warn("mod conflict", "path", c.Path, "mod", c.Mod, "prev", c.Prev, "replaced", c.Replaced)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	mods = list
+	conflicts = mounted
+	return nil
+}
+
+// get the mods found at startup, loaded ones first in load order
+func GetMods() []*Mod {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(mods)
+}
+
+// get the mod a resource came from, empty for the base resources
+func owner(path string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return owners[path]
+}
+
+// get the resources provided twice when mounting the mods
+func GetConflicts() []Conflict {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(conflicts)
+}
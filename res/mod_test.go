@@ -0,0 +1,212 @@
+package res
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// write a mod archive with a manifest and files holding their own names
+func writeMod(t *testing.T, dir string, name string, manifest string, files ...string) string {
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zw := zip.NewWriter(file)
+	if manifest != "" {
+		files = append([]string{ManifestName}, files...)
+	}
+	for _, f := range files {
+		w, err := zw.Create(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := name + ":" + f
+		if f == ManifestName {
+			data = manifest
+		}
+		w.Write([]byte(data))
+	}
+	err = zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadMod(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		manifest string
+		valid    bool
+	}{
+		{`{"id": "trees", "version": "1.0"}`, true},
+		{`{// comments are allowed
+			"id": "trees", "version": "2.1.3", "dependencies": {"base": "1"}, "priority": -2, "overrides": ["tex/a.png"]}`, true},
+		{``, false},
+		{`{"version": "1.0"}`, false},
+		{`{"id": "my trees", "version": "1.0"}`, false},
+		{`{"id": "trees", "version": "1.x"}`, false},
+		{`{"id": "trees"}`, false},
+		{`{"id": "trees", "version": "1", "dependencies": {"trees": "1"}}`, false},
+		{`{"id": "trees", "version": "1", "dependencies": {"base": ""}}`, false},
+		{`{"id": `, false},
+	}
+	for i, test := range tests {
+		path := writeMod(t, dir, fmt.Sprintf("mod%d.zip", i), test.manifest, "tex/a.png")
+		m, err := ReadMod(path)
+		if (err == nil) != test.valid {
+			t.Error(fmt.Sprintf("Unexpected error for case %d: %+v", i, err))
+			continue
+		}
+		if test.valid && (m.ID != "trees" || len(m.files) != 1 || !m.Enabled) {
+			t.Error(fmt.Sprintf("Unexpected mod for case %d: %+v", i, m))
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a      string
+		b      string
+		expect int
+	}{
+		{"1.0", "1.0", 0},
+		{"1", "1.0.0", 0},
+		{"1.2", "1.10", -1},
+		{"2", "1.9.9", 1},
+		{"0.0.1", "0.0", 1},
+	}
+	for i, test := range tests {
+		got := CompareVersions(test.a, test.b)
+		if got != test.expect {
+			t.Error(fmt.Sprintf("Unexpected comparison for case %d: %s to %s expected %d, got %d", i, test.a, test.b, test.expect, got))
+		}
+	}
+}
+
+func TestSortMods(t *testing.T) {
+	mod := func(id string, version string, priority int, deps ...string) *Mod {
+		m := &Mod{Manifest: Manifest{ID: id, Version: version, Priority: priority, Dependencies: map[string]string{}}, Enabled: true}
+		for _, dep := range deps {
+			id, min, _ := strings.Cut(dep, "@")
+			m.Dependencies[id] = min
+		}
+		return m
+	}
+	tests := []struct {
+		mods     []*Mod
+		disabled []string
+		order    []string
+		failed   []string
+	}{
+		//independent mods by priority then id
+		{[]*Mod{mod("c", "1", 0), mod("b", "1", 0), mod("a", "1", 5)}, nil, []string{"b", "c", "a"}, nil},
+		//dependencies first whatever their priority
+		{[]*Mod{mod("a", "1", 0, "b@1"), mod("b", "1", 10)}, nil, []string{"b", "a"}, nil},
+		{[]*Mod{mod("a", "1", 0, "b@1"), mod("b", "1", 0, "c@1"), mod("c", "1", 0)}, nil, []string{"c", "b", "a"}, nil},
+		//a missing or too old dependency takes its dependents down
+		{[]*Mod{mod("a", "1", 0, "b@1"), mod("b", "1", 0, "x@1"), mod("c", "1", 0)}, nil, []string{"c"}, []string{"a", "b"}},
+		{[]*Mod{mod("a", "1", 0, "b@2.1"), mod("b", "2.0.5", 0)}, nil, []string{"b"}, []string{"a"}},
+		{[]*Mod{mod("a", "1", 0, "b@2"), mod("b", "2.0.0", 0)}, nil, []string{"b", "a"}, nil},
+		//disabled mods and mods needing them are left out
+		{[]*Mod{mod("a", "1", 0, "b@1"), mod("b", "1", 0), mod("c", "1", 0)}, []string{"b"}, []string{"c"}, []string{"a"}},
+		//cycles and duplicates
+		{[]*Mod{mod("a", "1", 0, "b@1"), mod("b", "1", 0, "a@1"), mod("c", "1", 0)}, nil, []string{"c"}, []string{"a", "b"}},
+		{[]*Mod{mod("a", "1", 0), mod("a", "2", 0)}, nil, []string{"a"}, []string{"a"}},
+	}
+	for i, test := range tests {
+		for _, m := range test.mods {
+			m.Enabled = !slices.Contains(test.disabled, m.ID)
+		}
+		order := []string{}
+		for _, m := range SortMods(test.mods) {
+			order = append(order, m.ID)
+		}
+		failed := []string{}
+		for _, m := range test.mods {
+			if m.Err != nil {
+				failed = append(failed, m.ID)
+			}
+		}
+		if !slices.Equal(order, test.order) {
+			t.Error(fmt.Sprintf("Unexpected order for case %d: expected %v, got %v", i, test.order, order))
+		}
+		if !slices.Equal(failed, test.failed) {
+			t.Error(fmt.Sprintf("Unexpected failures for case %d: expected %v, got %v", i, test.failed, failed))
+		}
+	}
+}
+
+func TestLoadMods(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "cfg", "mods.json")
+	lib := filepath.Join(dir, "lib")
+	os.MkdirAll(lib, 0755)
+	setRes("modtest/base.txt", resource{[]byte("base"), nil})
+
+	writeMod(t, lib, "a.zip", `{"id": "a", "version": "1"}`, "modtest/a.txt", "modtest/base.txt")
+	writeMod(t, lib, "b.zip", `{"id": "b", "version": "1", "dependencies": {"a": "1"}, "overrides": ["modtest/a.txt", "modtest/base.txt"]}`, "modtest/a.txt", "modtest/base.txt", "modtest/b.txt")
+	writeMod(t, lib, "c.zip", `{"id": "c", "version": "1", "priority": 1, "overrides": ["modtest/b.txt"]}`, "modtest/b.txt", "modtest/c.txt")
+	writeMod(t, lib, "off.zip", `{"id": "off", "version": "1"}`, "modtest/off.txt")
+	writeMod(t, lib, "broken.zip", "", "modtest/broken.txt")
+	err := SaveModConfig(cfg, map[string]bool{"off": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LoadMods(lib, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		//a may only add, b overrides both explicitly, c overrides b without depending on it
+		"modtest/base.txt":   "b.zip:modtest/base.txt",
+		"modtest/a.txt":      "b.zip:modtest/a.txt",
+		"modtest/b.txt":      "c.zip:modtest/b.txt",
+		"modtest/c.txt":      "c.zip:modtest/c.txt",
+		"modtest/off.txt":    "",
+		"modtest/broken.txt": "",
+	}
+	for path, data := range expect {
+		res, ok := getRes(path)
+		got := ""
+		if ok {
+			got = string(res.data.([]byte))
+		}
+		if got != data {
+			t.Error(fmt.Sprintf("Unexpected resource %s: expected %q, got %q", path, data, got))
+		}
+	}
+
+	order := []string{}
+	for _, m := range GetMods() {
+		order = append(order, fmt.Sprintf("%s:%v", m.ID, m.Loaded))
+	}
+	if !slices.Equal(order, []string{"a:true", "b:true", "c:true", "off:false"}) {
+		t.Error(fmt.Sprintf("Unexpected mods: %v", order))
+	}
+	conflicts := []string{}
+	for _, c := range GetConflicts() {
+		conflicts = append(conflicts, c.String())
+	}
+	expectConflicts := []string{
+		"modtest/base.txt: a does not list it in overrides, keeping the one from base",
+		"modtest/b.txt: c overrides b without depending on it",
+	}
+	if !slices.Equal(conflicts, expectConflicts) {
+		t.Error(fmt.Sprintf("Unexpected conflicts: %q", conflicts))
+	}
+
+	//new mods are added to the config enabled
+	enabled, err := LoadModConfig(cfg)
+	if err != nil || len(enabled) != 4 || !enabled["a"] || enabled["off"] {
+		t.Error(fmt.Sprintf("Unexpected mods.json: %v %+v", enabled, err))
+	}
+}
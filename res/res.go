@@ -6,12 +6,13 @@ import (
 	"embed"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
-	raylib "github.com/gen2brain/raylib-go/raylib"
+	"karalis/internal/rlx"
+	"karalis/pkg/logging"
 )
 
 /*
@@ -27,6 +28,10 @@ type resource struct {
 
 var (
 	resources map[string]resource = map[string]resource{}
+	//guards resources, written by the watcher while the game reads them
+	mu sync.RWMutex
+
+	logger = logging.For(logging.Res)
 )
 
 const tmp = ".tmp/"
@@ -39,23 +44,19 @@ func Init() error {
 
 	err := LoadDir(".")
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Error("load resources", "err", err)
 	}
-	files, err := os.ReadDir("lib")
+	err = LoadMods(ModDir, ModsPath())
 	if err != nil {
-		log.Printf("%+v\n", err)
-	}
-	for _, entry := range files {
-		err = ReadArchive("lib/" + entry.Name())
-		if err != nil {
-			log.Printf("%+v\n", err)
-		}
+		logger.Error("load mods", "dir", ModDir, "err", err)
 	}
 
 	return nil
 }
 
 func WriteFs() error {
+	mu.RLock()
+	defer mu.RUnlock()
 	for path, res := range resources {
 		if res.err == nil {
 			err := os.MkdirAll(filepath.Dir(tmp+path), os.ModePerm)
@@ -90,31 +91,31 @@ func LoadDir(cwd string) error {
 			LoadDir(path)
 		} else {
 			data, err := resfs.ReadFile(path)
-			resources[path] = resource{data, err}
+			setRes(path, resource{data, err})
 		}
 	}
 	return nil
 }
 
 func GetRes(path string) (interface{}, error) {
-	if res, ok := resources[path]; ok {
+	if res, ok := getRes(path); ok {
 		if res.err == nil && strings.Contains(path, ".obj") {
-			return raylib.LoadModel(path), nil
+			return rlx.LoadModel(path), nil
 		}
 		if res.err == nil && strings.Contains(path, ".iqm") {
-			return raylib.LoadModel(path), nil
+			return rlx.LoadModel(path), nil
 		}
 		if res.err == nil && strings.Contains(path, ".gltf") {
-			return raylib.LoadModel(path), nil
+			return rlx.LoadModel(path), nil
 		}
 		if res.err == nil && strings.Contains(path, "glb") {
-			return raylib.LoadModel(path), nil
+			return rlx.LoadModel(path), nil
 		}
 		if res.err == nil && strings.Contains(path, "vox") {
-			return raylib.LoadModel(path), nil
+			return rlx.LoadModel(path), nil
 		}
 		if res.err == nil && strings.Contains(path, "m3d") {
-			return raylib.LoadModel(path), nil
+			return rlx.LoadModel(path), nil
 		}
 
 		return res.data, res.err
@@ -135,15 +136,27 @@ func ReadArchive(path string) error {
 }
 
 func ReadZip(data []byte) error {
-	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	files, err := readZip(data)
 	if err != nil {
 		return err
 	}
+	for path, res := range files {
+		setRes(path, res)
+	}
+	return nil
+}
+
+func readZip(data []byte) (map[string]resource, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]resource{}
 	for _, f := range zr.File {
 		data, err := readZipFile(f)
-		resources[f.Name] = resource{data, err}
+		files[f.Name] = resource{data, err}
 	}
-	return nil
+	return files, nil
 }
 
 func readZipFile(f *zip.File) (interface{}, error) {
@@ -177,9 +190,22 @@ func GetData(cfile *C.char, cdir *C.char) *C.char {
 		path = path[2:]
 	}
 
-	if res, ok := resources[path]; ok && res.err == nil {
+	if res, ok := getRes(path); ok && res.err == nil {
 		return C.CString(string(res.data.([]byte)))
 	}
 
 	return nil
 }
+
+func getRes(path string) (resource, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	res, ok := resources[path]
+	return res, ok
+}
+
+func setRes(path string, res resource) {
+	mu.Lock()
+	defer mu.Unlock()
+	resources[path] = res
+}
@@ -0,0 +1,161 @@
+package res
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"karalis/pkg/event"
+
+	pub_object "karalis/pkg/object"
+)
+
+const (
+	// how often Watch checks the folder
+	WatchInterval = 500 * time.Millisecond
+)
+
+var (
+	// changes found by watchers, flushed by whoever owns the objects using resources
+	// so handlers run while nothing is drawing
+	Changes = event.NewBus()
+
+	//background watchers running
+	watching atomic.Int32
+)
+
+// a resource was reloaded from disk
+type Changed struct {
+	Path string
+}
+
+type stamp struct {
+	mod  time.Time
+	size int64
+}
+
+// polls an on-disk copy of the resource folder, replacing resources whose files change
+type Watcher struct {
+	dir    string
+	stamps map[string]stamp
+}
+
+// watch dir, loading every file in it over the embedded ones except those mods provide
+func NewWatcher(dir string) (*Watcher, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fs.ErrInvalid
+	}
+
+	w := &Watcher{dir: dir, stamps: map[string]stamp{}}
+	_, err = w.scan(false)
+	return w, err
+}
+
+// reload the files changed since the last poll, posting a Changed for each
+func (w *Watcher) Poll() ([]string, error) {
+	if w == nil {
+		return []string{}, nil
+	}
+
+	return w.scan(true)
+}
+
+func (w *Watcher) scan(notify bool) ([]string, error) {
+	changed := []string{}
+	err := filepath.WalkDir(w.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(w.dir, path)
+		if err != nil {
+			return err
+		}
+		//resources live in folders, files at the top are the package sources
+		key := filepath.ToSlash(rel)
+		if !strings.Contains(key, "/") {
+			return nil
+		}
+		//the folder holds base resources, those a mod replaced keep the mods copy
+		if owner(key) != "" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		st := stamp{info.ModTime(), info.Size()}
+		if prev, ok := w.stamps[key]; ok && prev == st {
+			return nil
+		}
+		w.stamps[key] = st
+
+		data, err := os.ReadFile(path)
+		setRes(key, resource{data, err})
+		changed = append(changed, key)
+		return nil
+	})
+	sort.Strings(changed)
+	if notify {
+		for _, path := range changed {
+			event.Post(Changes, Changed{Path: path})
+		}
+	}
+	return changed, err
+}
+
+// poll dir in the background until stop is called
+func Watch(dir string, interval time.Duration) (func(), error) {
+	w, err := NewWatcher(dir)
+	if err != nil {
+		return func() {}, err
+	}
+
+	done := make(chan struct{})
+	watching.Add(1)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				changed, err := w.Poll()
+				if err != nil {
+					logger.Error("watch", "dir", dir, "err", err)
+				}
+				for _, path := range changed {
+					logger.Info("reloaded", "path", path)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		watching.Add(-1)
+	}, nil
+}
+
+// check if a background watcher runs, objects only follow reloads while one does
+func Watching() bool {
+	return watching.Load() > 0
+}
+
+// call fn when path is reloaded, until owner is removed
+// without an owner the subscription lasts until cancelled
+func OnChange(owner pub_object.Object, path string, fn func()) *event.Subscription {
+	return event.SubscribeFor(Changes, owner, func(c Changed) {
+		if c.Path == path {
+			fn()
+		}
+	})
+}
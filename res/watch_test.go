@@ -0,0 +1,154 @@
+package res
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"karalis/pkg/event"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path string, data string, mod time.Time) {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		err := os.MkdirAll(filepath.Dir(full), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(full, []byte(data), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.Chtimes(full, mod, mod)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write("shader/test.vert", "v1", start)
+	write("res.go", "package res", start)
+
+	w, err := NewWatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := GetRes("shader/test.vert"); err != nil || string(data.([]byte)) != "v1" {
+		t.Error(fmt.Sprintf("Watched folder was not loaded: %v, %+v", data, err))
+	}
+	if _, err := GetRes("res.go"); err == nil {
+		t.Error("Top level file was loaded as a resource")
+	}
+
+	changes := []string{}
+	sub := event.Subscribe(Changes, func(c Changed) {
+		changes = append(changes, c.Path)
+	})
+	defer sub.Cancel()
+
+	tests := []struct {
+		change func()
+		expect []string
+	}{
+		{func() {}, []string{}},
+		{func() { write("shader/test.vert", "v2", start.Add(time.Minute)) }, []string{"shader/test.vert"}},
+		{func() { write("tex/new.png", "png", start) }, []string{"tex/new.png"}},
+		{func() {
+			write("shader/test.vert", "v3", start.Add(2*time.Minute))
+			write("mdl/a.obj", "obj", start)
+		}, []string{"mdl/a.obj", "shader/test.vert"}},
+	}
+	for i, test := range tests {
+		changes = []string{}
+		test.change()
+		changed, err := w.Poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		Changes.Flush()
+		if !slices.Equal(changed, test.expect) || !slices.Equal(changes, test.expect) {
+			t.Error(fmt.Sprintf("Unexpected changes for case %d: expected %q, got %q and events %q", i, test.expect, changed, changes))
+		}
+	}
+	if data, _ := GetRes("shader/test.vert"); string(data.([]byte)) != "v3" {
+		t.Error(fmt.Sprintf("Changed resource was not reloaded: %s", data))
+	}
+
+	_, err = NewWatcher(filepath.Join(dir, "missing"))
+	if err == nil {
+		t.Error("Watcher accepted a missing folder")
+	}
+}
+
+func TestWatcherMods(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{"watchtest/modded.txt", "watchtest/base.txt"} {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		os.MkdirAll(filepath.Dir(full), 0755)
+		err := os.WriteFile(full, []byte("disk"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := &Mod{Manifest: Manifest{ID: "watchmod"}, files: map[string]resource{
+		"watchtest/modded.txt": {[]byte("mod"), nil},
+	}}
+	Mount([]*Mod{m})
+	defer func() {
+		mu.Lock()
+		delete(owners, "watchtest/modded.txt")
+		mu.Unlock()
+	}()
+
+	w, err := NewWatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//edits on disk to a modded resource are not picked up either
+	os.WriteFile(filepath.Join(dir, "watchtest", "modded.txt"), []byte("disk changed"), 0644)
+	changed, err := w.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 0 {
+		t.Error(fmt.Sprintf("TestWatcherMods expected no reloads, got %q", changed))
+	}
+
+	tests := []struct {
+		path  string
+		data  string
+		owner string
+	}{
+		{"watchtest/modded.txt", "mod", "watchmod"},
+		{"watchtest/base.txt", "disk", ""},
+	}
+	for _, test := range tests {
+		data, err := GetRes(test.path)
+		if err != nil || string(data.([]byte)) != test.data {
+			t.Error(fmt.Sprintf("TestWatcherMods expected %s to be %q, got %v, %+v", test.path, test.data, data, err))
+		}
+		if got := owner(test.path); got != test.owner {
+			t.Error(fmt.Sprintf("TestWatcherMods expected %s from %q, got %q", test.path, test.owner, got))
+		}
+	}
+}
+
+func TestWatching(t *testing.T) {
+	if Watching() {
+		t.Error("TestWatching expected no watcher")
+	}
+	stop, err := Watch(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Watching() {
+		t.Error("TestWatching expected a watcher")
+	}
+	stop()
+	if Watching() {
+		t.Error("TestWatching expected the watcher stopped")
+	}
+}